@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// validateJobBoard checks the settings of a job board and trims its names and filters
+func validateJobBoard(board *models.JobBoard) error {
+	if !board.Provider.Valid() {
+		return errors.New("provider must be one of greenhouse, lever, ashby or workable")
+	}
+	board.Board = strings.TrimSpace(board.Board)
+	if board.Board == "" {
+		return errors.New("board is required")
+	}
+	board.Company = strings.TrimSpace(board.Company)
+	board.Keywords = cleanFilterTerms(board.Keywords)
+	board.Locations = cleanFilterTerms(board.Locations)
+	return nil
+}
+
+// cleanFilterTerms trims filter terms and drops empty ones. Commas separate the stored
+// terms, so a term holding commas is split.
+func cleanFilterTerms(terms []string) []string {
+	cleaned := []string{}
+	for _, term := range terms {
+		for _, part := range strings.Split(term, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				cleaned = append(cleaned, part)
+			}
+		}
+	}
+	return cleaned
+}
+
+// jobBoardsHandler serves GET /api/job-boards and POST /api/job-boards with
+// {"provider", "board", "company", "keywords", "locations", "enabled"}
+func jobBoardsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	switch r.Method {
+	case http.MethodOptions:
+		handleCORS(w, "GET, POST, OPTIONS")
+	case http.MethodGet:
+		boards, err := sharedDB.GetJobBoards(false)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(boards)
+	case http.MethodPost:
+		board := models.JobBoard{Enabled: true}
+		if err := json.NewDecoder(r.Body).Decode(&board); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := validateJobBoard(&board); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id, err := sharedDB.InsertJobBoard(board)
+		if err == sharedDB.ErrDuplicate {
+			http.Error(w, "This job board is already configured", http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, "DB insert error", http.StatusInternalServerError)
+			return
+		}
+
+		created, err := sharedDB.GetJobBoardByID(int(id))
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// jobBoardHandler serves PUT /api/job-boards/{id} with the settings of the board and
+// DELETE /api/job-boards/{id}
+func jobBoardHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "PUT, DELETE, OPTIONS")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/job-boards/"))
+	if err != nil {
+		http.Error(w, "Invalid job board ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		board := models.JobBoard{Enabled: true}
+		if err := json.NewDecoder(r.Body).Decode(&board); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := validateJobBoard(&board); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		board.Id = id
+		if err := sharedDB.UpdateJobBoard(board); err == sharedDB.ErrNotFound {
+			http.Error(w, "Job board not found", http.StatusNotFound)
+			return
+		} else if err == sharedDB.ErrDuplicate {
+			http.Error(w, "This job board is already configured", http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, "DB update error", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := sharedDB.GetJobBoardByID(id)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if err := sharedDB.DeleteJobBoard(id); err == sharedDB.ErrNotFound {
+			http.Error(w, "Job board not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
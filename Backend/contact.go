@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// validateContact checks a contact from a request body and defaults its role
+func validateContact(w http.ResponseWriter, contact *models.Contact) bool {
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.Company = strings.TrimSpace(contact.Company)
+	contact.Email = strings.TrimSpace(contact.Email)
+	contact.LinkedinURL = strings.TrimSpace(contact.LinkedinURL)
+
+	if contact.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return false
+	}
+	if contact.Role == "" {
+		contact.Role = models.ContactRoleOther
+	}
+	if !contact.Role.Valid() {
+		http.Error(w, "Invalid role, expected recruiter, hiring_manager, referrer, interviewer or other", http.StatusBadRequest)
+		return false
+	}
+	if contact.Email != "" {
+		if _, err := mail.ParseAddress(contact.Email); err != nil {
+			http.Error(w, "Invalid email", http.StatusBadRequest)
+			return false
+		}
+	}
+	if contact.LinkedinURL != "" {
+		u, err := url.Parse(contact.LinkedinURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			http.Error(w, "Invalid linkedinUrl", http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+// listContactsHandler serves GET /api/contacts?q=&company=&role=&limit=
+func listContactsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	params := r.URL.Query()
+	query := sharedDB.ContactQuery{
+		Search:  strings.TrimSpace(params.Get("q")),
+		Company: params.Get("company"),
+		Role:    models.ContactRole(params.Get("role")),
+	}
+	if query.Role != "" && !query.Role.Valid() {
+		http.Error(w, "Invalid role parameter", http.StatusBadRequest)
+		return
+	}
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > sharedDB.MaxContactPageSize {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		query.Limit = limit
+	}
+
+	contacts, err := sharedDB.SearchContacts(query)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contacts)
+}
+
+func createContactHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	var contact models.Contact
+	if err := json.NewDecoder(r.Body).Decode(&contact); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !validateContact(w, &contact) {
+		return
+	}
+
+	id, err := sharedDB.InsertContact(contact)
+	if err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+
+	created, err := sharedDB.GetContactByID(int(id))
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// contactHandler serves GET, PUT and DELETE /api/contacts/{id}
+func contactHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, PUT, DELETE, OPTIONS")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/contacts/"))
+	if err != nil {
+		http.Error(w, "Invalid contact ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		contact, err := sharedDB.GetContactByID(id)
+		if err == sharedDB.ErrNotFound {
+			http.Error(w, "Contact not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contact)
+	case http.MethodPut:
+		var contact models.Contact
+		if err := json.NewDecoder(r.Body).Decode(&contact); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if !validateContact(w, &contact) {
+			return
+		}
+		contact.Id = id
+
+		if err := sharedDB.UpdateContact(contact); err == sharedDB.ErrNotFound {
+			http.Error(w, "Contact not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB update error", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := sharedDB.GetContactByID(id)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if err := sharedDB.DeleteContact(id); err == sharedDB.ErrNotFound {
+			http.Error(w, "Contact not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// jobContactsHandler serves GET /api/jobs/{id}/contacts, POST with {"contactId"} to
+// link a contact and DELETE ?contactId= to unlink it
+func jobContactsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, POST, DELETE, OPTIONS")
+		return
+	}
+
+	jobID, err := jobIDFromPath(r.URL.Path, "/contacts")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		contacts, err := sharedDB.GetJobContacts(jobID)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contacts)
+	case http.MethodPost:
+		var requestBody struct {
+			ContactId int `json:"contactId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if err := sharedDB.LinkJobContact(jobID, requestBody.ContactId); err == sharedDB.ErrNotFound {
+			http.Error(w, "Job or contact not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB insert error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		contactID, err := strconv.Atoi(r.URL.Query().Get("contactId"))
+		if err != nil {
+			http.Error(w, "Missing or invalid contactId parameter", http.StatusBadRequest)
+			return
+		}
+
+		if err := sharedDB.UnlinkJobContact(jobID, contactID); err == sharedDB.ErrNotFound {
+			http.Error(w, "Contact is not linked to this job", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/hirepilot/shared/models"
 	sharedNATS "github.com/hirepilot/shared/nats"
 )
 
@@ -37,6 +38,10 @@ func generateCVHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	if !checkPromptKind(w, reqBody.PromptId, models.PromptKindCV) {
+		return
+	}
+
 	// Send CV generation request via NATS instead of generating directly
 	err := sharedNATS.PublishCVGenerationRequest(id, reqBody.PromptId)
 	if err != nil {
@@ -73,6 +78,11 @@ func generateScoreHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A CV or cover letter prompt cannot be used for scoring
+	if !checkPromptKind(w, req.PromptId, models.PromptKindScore) {
+		return
+	}
+
 	// Send score generation request via NATS instead of generating directly
 	err := sharedNATS.PublishScoreGenerationRequest(id, req.PromptId)
 	if err != nil {
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/evaluation"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+)
+
+func listBenchmarksHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	sets, err := sharedDB.GetBenchmarkSets()
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sets)
+}
+
+// importBenchmarkHandler stores a benchmark set posted in the JSON fixture format
+func importBenchmarkHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	var set models.BenchmarkSet
+	if err := json.NewDecoder(r.Body).Decode(&set); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if set.Name == "" || len(set.Jobs) == 0 {
+		http.Error(w, "Benchmark set needs a name and at least one job", http.StatusBadRequest)
+		return
+	}
+
+	id, err := sharedDB.InsertBenchmarkSet(set)
+	if err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":   id,
+		"jobs": len(set.Jobs),
+	})
+}
+
+// getBenchmarkHandler serves /api/benchmarks/{id} and /api/benchmarks/{id}/export
+func getBenchmarkHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(r.URL.Path[len("/api/benchmarks/"):], "/export")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid benchmark ID", http.StatusBadRequest)
+		return
+	}
+
+	set, err := sharedDB.GetBenchmarkSetByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Benchmark set not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasSuffix(r.URL.Path, "/export") {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"benchmark-%d.json\"", id))
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(evaluation.Fixture(set))
+		return
+	}
+	json.NewEncoder(w).Encode(set)
+}
+
+func createEvaluationHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	var requestBody struct {
+		PromptID       int    `json:"promptId"`
+		Kind           string `json:"kind"`
+		BenchmarkSetID int    `json:"benchmarkSetId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if requestBody.Kind != evaluation.KindCV && requestBody.Kind != evaluation.KindCover {
+		http.Error(w, "kind must be cv or cover", http.StatusBadRequest)
+		return
+	}
+
+	if !checkPromptKind(w, &requestBody.PromptID, models.PromptKind(requestBody.Kind)) {
+		return
+	}
+	if _, err := sharedDB.GetBenchmarkSetByID(requestBody.BenchmarkSetID); err == sharedDB.ErrNotFound {
+		http.Error(w, "Benchmark set not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := sharedDB.InsertPromptEvaluation(requestBody.PromptID, requestBody.Kind, requestBody.BenchmarkSetID)
+	if err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+
+	// PromptEvaluator runs the evaluation and stores the report
+	if err := sharedNats.PublishPromptEvaluationRequest(int(id)); err != nil {
+		log.Printf("Failed to publish prompt evaluation request: %v", err)
+		http.Error(w, "Failed to process prompt evaluation request", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted) // 202 Accepted since processing is async
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Prompt evaluation requested",
+		"id":      id,
+		"status":  models.EvaluationStatusPending,
+	})
+}
+
+func getEvaluationHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract id from URL: /api/evaluations/{id}
+	id, err := strconv.Atoi(r.URL.Path[len("/api/evaluations/"):])
+	if err != nil {
+		http.Error(w, "Invalid evaluation ID", http.StatusBadRequest)
+		return
+	}
+
+	evaluationRun, err := sharedDB.GetPromptEvaluationByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Evaluation not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(evaluationRun)
+}
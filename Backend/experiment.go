@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+func listExperimentsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	experiments, err := sharedDB.GetExperiments()
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(experiments)
+}
+
+func createExperimentHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	var experiment models.Experiment
+	if err := json.NewDecoder(r.Body).Decode(&experiment); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if experiment.Name == "" {
+		http.Error(w, "Experiment name is required", http.StatusBadRequest)
+		return
+	}
+	if experiment.Kind != models.PromptKindCV && experiment.Kind != models.PromptKindCover {
+		http.Error(w, "kind must be cv or cover", http.StatusBadRequest)
+		return
+	}
+	if len(experiment.Variants) < 2 {
+		http.Error(w, "An experiment needs at least two variants", http.StatusBadRequest)
+		return
+	}
+
+	seen := make(map[int]bool)
+	for _, variant := range experiment.Variants {
+		if variant.Weight <= 0 {
+			http.Error(w, "Variant weights must be positive", http.StatusBadRequest)
+			return
+		}
+		if seen[variant.PromptId] {
+			http.Error(w, "Each variant must use a different prompt", http.StatusBadRequest)
+			return
+		}
+		seen[variant.PromptId] = true
+
+		promptID := variant.PromptId
+		if !checkPromptKind(w, &promptID, experiment.Kind) {
+			return
+		}
+	}
+
+	id, err := sharedDB.InsertExperiment(experiment)
+	if err == sharedDB.ErrDuplicate {
+		http.Error(w, "An experiment of this kind is already active", http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":     id,
+		"status": models.ExperimentStatusActive,
+	})
+}
+
+// experimentHandler serves /api/experiments/{id}, /api/experiments/{id}/report and /api/experiments/{id}/stop
+func experimentHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/experiments/")
+	action := ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		path, action = path[:i], path[i+1:]
+	}
+	id, err := strconv.Atoi(path)
+	if err != nil {
+		http.Error(w, "Invalid experiment ID", http.StatusBadRequest)
+		return
+	}
+
+	switch action {
+	case "":
+		if r.Method == http.MethodOptions {
+			handleCORS(w, "GET, OPTIONS")
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		getExperimentHandler(w, id)
+	case "report":
+		if r.Method == http.MethodOptions {
+			handleCORS(w, "GET, OPTIONS")
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		experimentReportHandler(w, id)
+	case "stop":
+		if r.Method == http.MethodOptions {
+			handleCORS(w, "POST, OPTIONS")
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		stopExperimentHandler(w, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func getExperimentHandler(w http.ResponseWriter, id int) {
+	experiment, err := sharedDB.GetExperimentByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Experiment not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(experiment)
+}
+
+// experimentReportHandler compares the variants on match score, interview rate and estimated AI cost
+func experimentReportHandler(w http.ResponseWriter, id int) {
+	experiment, err := sharedDB.GetExperimentByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Experiment not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	variants, err := sharedDB.GetExperimentReport(id)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	for i := range variants {
+		variants[i].EstimatedCost = sharedAI.EstimateCost(variants[i].InputTokens, variants[i].OutputTokens)
+		if variants[i].Jobs > 0 {
+			variants[i].CostPerJob = variants[i].EstimatedCost / float64(variants[i].Jobs)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"experiment": experiment,
+		"variants":   variants,
+	})
+}
+
+func stopExperimentHandler(w http.ResponseWriter, id int) {
+	if err := sharedDB.StopExperiment(id); err == sharedDB.ErrNotFound {
+		http.Error(w, "Active experiment not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB update error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":     id,
+		"status": models.ExperimentStatusStopped,
+	})
+}
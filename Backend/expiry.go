@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// expiryPolicyReport is what a job expiry policy would change if it ran now
+type expiryPolicyReport struct {
+	models.ExpiryPolicy
+	Note       string                   `json:"note"`
+	Candidates []models.ExpiryCandidate `json:"candidates"`
+}
+
+// expiryPoliciesHandler serves GET /api/job-expiry/policies
+func expiryPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	policies, err := sharedDB.GetExpiryPolicies(false)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policies)
+}
+
+// expiryPolicyHandler serves PUT /api/job-expiry/policies/{kind} with {"afterDays", "enabled"}
+func expiryPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "PUT, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPut {
+		http.Error(w, "Only PUT allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	kind := models.ExpiryPolicyKind(strings.TrimPrefix(r.URL.Path, "/api/job-expiry/policies/"))
+	if !kind.Valid() {
+		http.Error(w, "Policy not found", http.StatusNotFound)
+		return
+	}
+
+	var policy models.ExpiryPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if policy.AfterDays <= 0 {
+		http.Error(w, "afterDays must be positive", http.StatusBadRequest)
+		return
+	}
+	policy.Kind = kind
+
+	if err := sharedDB.UpdateExpiryPolicy(policy); err == sharedDB.ErrNotFound {
+		http.Error(w, "Policy not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB update error", http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := sharedDB.GetExpiryPolicy(kind)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// expiryReportHandler serves GET /api/job-expiry/report, a dry run of every policy.
+// Disabled policies are included so their effect can be checked before enabling them.
+func expiryReportHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	policies, err := sharedDB.GetExpiryPolicies(false)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	reports := []expiryPolicyReport{}
+	for _, policy := range policies {
+		candidates, err := sharedDB.GetExpiryCandidates(policy, now)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		reports = append(reports, expiryPolicyReport{ExpiryPolicy: policy, Note: policy.Note(), Candidates: candidates})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
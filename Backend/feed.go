@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// validateJobFeed checks the settings of a job feed and trims its URL and names
+func validateJobFeed(feed *models.JobFeed) error {
+	feed.URL = strings.TrimSpace(feed.URL)
+	u, err := url.Parse(feed.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an http or https link")
+	}
+	if len(feed.URL) > 1024 {
+		return errors.New("url must be at most 1024 characters")
+	}
+	feed.Name = strings.TrimSpace(feed.Name)
+	if feed.Name == "" {
+		feed.Name = u.Host
+	}
+	feed.Company = strings.TrimSpace(feed.Company)
+	return nil
+}
+
+// jobFeedsHandler serves GET /api/job-feeds and POST /api/job-feeds with
+// {"url", "name", "company", "fetchDescription", "enabled"}
+func jobFeedsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	switch r.Method {
+	case http.MethodOptions:
+		handleCORS(w, "GET, POST, OPTIONS")
+	case http.MethodGet:
+		feeds, err := sharedDB.GetJobFeeds(false)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(feeds)
+	case http.MethodPost:
+		feed := models.JobFeed{Enabled: true}
+		if err := json.NewDecoder(r.Body).Decode(&feed); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := validateJobFeed(&feed); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id, err := sharedDB.InsertJobFeed(feed)
+		if err == sharedDB.ErrDuplicate {
+			http.Error(w, "This feed is already watched", http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, "DB insert error", http.StatusInternalServerError)
+			return
+		}
+
+		created, err := sharedDB.GetJobFeedByID(int(id))
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// jobFeedHandler serves PUT /api/job-feeds/{id} with the settings of the feed and
+// DELETE /api/job-feeds/{id}
+func jobFeedHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "PUT, DELETE, OPTIONS")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/job-feeds/"))
+	if err != nil {
+		http.Error(w, "Invalid job feed ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var feed models.JobFeed
+		if err := json.NewDecoder(r.Body).Decode(&feed); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := validateJobFeed(&feed); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		feed.Id = id
+		if err := sharedDB.UpdateJobFeed(feed); err == sharedDB.ErrNotFound {
+			http.Error(w, "Job feed not found", http.StatusNotFound)
+			return
+		} else if err == sharedDB.ErrDuplicate {
+			http.Error(w, "This feed is already watched", http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, "DB update error", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := sharedDB.GetJobFeedByID(id)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if err := sharedDB.DeleteJobFeed(id); err == sharedDB.ErrNotFound {
+			http.Error(w, "Job feed not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
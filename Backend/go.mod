@@ -5,6 +5,7 @@ go 1.24
 require (
 	github.com/gorilla/websocket v1.5.0
 	github.com/hirepilot/shared v0.0.0
+	github.com/xuri/excelize/v2 v2.9.0
 )
 
 replace github.com/hirepilot/shared => ../shared
@@ -12,10 +13,16 @@ replace github.com/hirepilot/shared => ../shared
 require (
 	github.com/go-sql-driver/mysql v1.7.1 // indirect
 	github.com/klauspost/compress v1.17.2 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
 	github.com/nats-io/nats.go v1.31.0 // indirect
 	github.com/nats-io/nkeys v0.4.5 // indirect
 	github.com/nats-io/nuid v1.0.1 // indirect
-	golang.org/x/crypto v0.6.0 // indirect
-	golang.org/x/sys v0.18.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
+	github.com/richardlehane/mscfb v1.0.4 // indirect
+	github.com/richardlehane/msoleps v1.0.4 // indirect
+	github.com/xuri/efp v0.0.0-20240408161823-9ad904a10d6d // indirect
+	github.com/xuri/nfp v0.0.0-20240318013403-ab9948c2c4a7 // indirect
+	golang.org/x/crypto v0.28.0 // indirect
+	golang.org/x/net v0.30.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
 )
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+)
+
+// validateInterview checks an interview from a request body and defaults its outcome
+func validateInterview(w http.ResponseWriter, interview *models.Interview) bool {
+	if !interview.Round.Valid() {
+		http.Error(w, "Invalid round, expected phone_screen, technical, behavioral, take_home, onsite, final or other", http.StatusBadRequest)
+		return false
+	}
+	if interview.ScheduledAt.IsZero() {
+		http.Error(w, "scheduledAt is required", http.StatusBadRequest)
+		return false
+	}
+	if interview.DurationMinutes != nil && *interview.DurationMinutes <= 0 {
+		http.Error(w, "durationMinutes must be positive", http.StatusBadRequest)
+		return false
+	}
+	if interview.Outcome == "" {
+		interview.Outcome = models.InterviewOutcomePending
+	}
+	if !interview.Outcome.Valid() {
+		http.Error(w, "Invalid outcome, expected pending, passed, failed or cancelled", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// publishInterviewEvent notifies other services; the interview is stored already
+func publishInterviewEvent(event string, interview models.Interview) {
+	if err := sharedNats.PublishInterviewEvent(event, interview); err != nil {
+		log.Printf("Warning: Failed to publish interview %s event for interview %d: %v", event, interview.Id, err)
+	}
+}
+
+// recordInterviewActivity adds an interview change to the job timeline; the interview is stored already
+func recordInterviewActivity(kind models.JobActivityKind, interview models.Interview) {
+	round := strings.ReplaceAll(string(interview.Round), "_", " ")
+	var summary string
+	switch kind {
+	case models.JobActivityInterviewScheduled:
+		summary = round + " interview scheduled"
+	case models.JobActivityInterviewDeleted:
+		summary = round + " interview removed"
+	default:
+		summary = round + " interview updated, outcome " + string(interview.Outcome)
+	}
+
+	interviewID := interview.Id
+	if err := sharedDB.RecordJobActivity(models.JobActivity{
+		JobId:   interview.JobId,
+		Kind:    kind,
+		Summary: summary,
+		Detail:  interview.Feedback,
+		RefId:   &interviewID,
+	}); err != nil {
+		log.Printf("Warning: Failed to record %s activity for job %d: %v", kind, interview.JobId, err)
+	}
+}
+
+// listInterviewsHandler serves GET /api/interviews?jobId=
+func listInterviewsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	jobID, err := strconv.Atoi(r.URL.Query().Get("jobId"))
+	if err != nil {
+		http.Error(w, "Missing or invalid jobId parameter", http.StatusBadRequest)
+		return
+	}
+
+	interviews, err := sharedDB.GetInterviewsByJob(jobID)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(interviews)
+}
+
+func createInterviewHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	var interview models.Interview
+	if err := json.NewDecoder(r.Body).Decode(&interview); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if interview.JobId <= 0 {
+		http.Error(w, "jobId is required", http.StatusBadRequest)
+		return
+	}
+	if !validateInterview(w, &interview) {
+		return
+	}
+
+	id, err := sharedDB.InsertInterview(interview)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+
+	created, err := sharedDB.GetInterviewByID(int(id))
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	publishInterviewEvent(sharedNats.InterviewCreated, *created)
+	recordInterviewActivity(models.JobActivityInterviewScheduled, *created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// upcomingInterviewsHandler serves GET /api/interviews/upcoming?days=&limit= for the dashboard
+func upcomingInterviewsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days, limit := 14, 20
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "Invalid days parameter", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 100 {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	upcoming, err := sharedDB.GetUpcomingInterviews(time.Now().AddDate(0, 0, days), limit)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(upcoming)
+}
+
+// interviewHandler serves GET, PUT and DELETE /api/interviews/{id}
+func interviewHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, PUT, DELETE, OPTIONS")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/interviews/"))
+	if err != nil {
+		http.Error(w, "Invalid interview ID", http.StatusBadRequest)
+		return
+	}
+
+	existing, err := sharedDB.GetInterviewByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Interview not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(existing)
+	case http.MethodPut:
+		var interview models.Interview
+		if err := json.NewDecoder(r.Body).Decode(&interview); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if !validateInterview(w, &interview) {
+			return
+		}
+		interview.Id = id
+		interview.JobId = existing.JobId
+
+		if err := sharedDB.UpdateInterview(interview); err == sharedDB.ErrNotFound {
+			http.Error(w, "Interview not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB update error", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := sharedDB.GetInterviewByID(id)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		publishInterviewEvent(sharedNats.InterviewUpdated, *updated)
+		recordInterviewActivity(models.JobActivityInterviewUpdated, *updated)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if err := sharedDB.DeleteInterview(id); err == sharedDB.ErrNotFound {
+			http.Error(w, "Interview not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		publishInterviewEvent(sharedNats.InterviewDeleted, *existing)
+		recordInterviewActivity(models.JobActivityInterviewDeleted, *existing)
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
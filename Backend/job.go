@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	sharedDB "github.com/hirepilot/shared/db"
 	"github.com/hirepilot/shared/models"
@@ -23,24 +24,58 @@ func addJobHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var job models.Job
+	var job models.JobPosting
 	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
+	if err := validateJobDetails(job.JobDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// The posting key is derived from the link for jobs added through the API
+	job.Source, job.ExternalId = "", ""
+
+	// Report known postings right away; JobService still refreshes their description
+	known, err := sharedDB.GetJobByLink(job.Link)
+	if err != nil && err != sharedDB.ErrNotFound {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
 
 	// Publish job creation request to NATS JetStream (JobService will handle DB insertion)
-	err := sharedNats.PublishJobCreationRequest(job.Title, job.Company, job.Link, job.Description)
+	err = sharedNats.PublishJobCreationRequest(job)
 	if err != nil {
 		log.Printf("Failed to publish job creation request: %v", err)
 		http.Error(w, "Failed to process job creation request", http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	if known != nil {
+		log.Printf("Job already known with ID %d: %s at %s", known.Id, job.Title, job.Company)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "already_known",
+			"id":     known.Id,
+		})
+		return
+	}
+
 	log.Printf("Job creation request published for: %s at %s", job.Title, job.Company)
 	w.WriteHeader(http.StatusAccepted) // 202 Accepted since processing is async
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "accepted",
+	})
+}
+
+// jobIDFromPath extracts the job ID from /api/jobs/{id}{suffix}
+func jobIDFromPath(path, suffix string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/jobs/"), suffix))
 }
-func updateJobStatusHandler(w http.ResponseWriter, r *http.Request, status string) {
+
+// updateJobStatusHandler serves PUT /api/jobs/{id}/status with {"status", "note"}.
+// The transition is checked here for a quick answer; JobService enforces it.
+func updateJobStatusHandler(w http.ResponseWriter, r *http.Request) {
 	setCORSHeaders(w)
 	if r.Method == http.MethodOptions {
 		handleCORS(w, "PUT, OPTIONS")
@@ -50,28 +85,41 @@ func updateJobStatusHandler(w http.ResponseWriter, r *http.Request, status strin
 		http.Error(w, "Only PUT allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	// Extract id from URL: /api/jobs/{id}/apply or /api/jobs/{id}/close
-	idWithAction := r.URL.Path[len("/api/jobs/"):] // e.g. "123/apply" or "123/close"
-	var actionSuffix string
-	if status == "applied" {
-		actionSuffix = "/apply"
-	} else if status == "closed" {
-		actionSuffix = "/close"
-	} else if status == "open" {
-		actionSuffix = "/open"
-	} else {
+
+	id, err := jobIDFromPath(r.URL.Path, "/status")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		Status models.JobStatus `json:"status"`
+		Note   string           `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !requestBody.Status.Valid() {
 		http.Error(w, "Invalid status", http.StatusBadRequest)
 		return
 	}
-	idStr := idWithAction[:len(idWithAction)-len(actionSuffix)]
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+
+	job, err := sharedDB.GetJobByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	if !job.Status.CanTransitionTo(requestBody.Status) {
+		http.Error(w, "Cannot move job from "+string(job.Status)+" to "+string(requestBody.Status), http.StatusConflict)
 		return
 	}
 
 	// Send job status update request via NATS instead of updating directly
-	err = sharedNats.PublishJobStatusUpdateRequest(id, status)
+	err = sharedNats.PublishJobStatusUpdateRequest(id, requestBody.Status, requestBody.Note)
 	if err != nil {
 		http.Error(w, "Failed to publish job status update request: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -79,12 +127,43 @@ func updateJobStatusHandler(w http.ResponseWriter, r *http.Request, status strin
 
 	// Return response indicating status update has been requested
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "Job status update requested",
 		"job_id":  id,
-		"status":  status,
+		"from":    job.Status,
+		"status":  requestBody.Status,
 	})
 }
+
+// jobStatusHistoryHandler serves GET /api/jobs/{id}/history
+func jobStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := jobIDFromPath(r.URL.Path, "/history")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := sharedDB.GetJobStatusHistory(id)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
 func listJobsByAppliedToday(w http.ResponseWriter, r *http.Request) {
 	setCORSHeaders(w)
 	if r.Method == http.MethodOptions {
@@ -112,13 +191,40 @@ func listJobsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := r.URL.Query().Get("status")
-	jobs, err := sharedDB.GetJobsByStatus(status)
+	query, err := parseJobQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := sharedDB.CountJobs(query)
 	if err != nil {
 		http.Error(w, "DB query error", http.StatusInternalServerError)
 		return
 	}
 
+	// The list projection omits CV, cover letter and description unless view=full
+	var jobs interface{}
+	var next string
+	if r.URL.Query().Get("view") == "full" {
+		jobs, next, err = sharedDB.QueryJobs(query)
+	} else {
+		jobs, next, err = sharedDB.QueryJobSummaries(query)
+	}
+	if err == sharedDB.ErrInvalidCursor {
+		http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	// Paging metadata travels in headers so the body stays a plain job array
+	w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, X-Next-Cursor")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(jobs)
 }
@@ -174,15 +280,45 @@ func regenerateJobContentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse request body for prompt ID
+	// Parse request body for prompt IDs; a missing ID regenerates with the default prompt of that kind
 	var requestBody struct {
-		PromptID int `json:"promptId"`
+		PromptID      *int `json:"promptId"` // Used for the content of the kind the prompt belongs to
+		CvPromptID    *int `json:"cvPromptId"`
+		CoverPromptID *int `json:"coverPromptId"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
+	if requestBody.PromptID != nil {
+		prompt, err := sharedDB.GetPromptByID(*requestBody.PromptID)
+		if err == sharedDB.ErrNotFound {
+			http.Error(w, "Prompt not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		switch prompt.Kind {
+		case models.PromptKindCV:
+			if requestBody.CvPromptID == nil {
+				requestBody.CvPromptID = requestBody.PromptID
+			}
+		case models.PromptKindCover:
+			if requestBody.CoverPromptID == nil {
+				requestBody.CoverPromptID = requestBody.PromptID
+			}
+		default:
+			http.Error(w, "Only CV and cover letter prompts can be used to regenerate content", http.StatusBadRequest)
+			return
+		}
+	}
+	if !checkPromptKind(w, requestBody.CvPromptID, models.PromptKindCV) ||
+		!checkPromptKind(w, requestBody.CoverPromptID, models.PromptKindCover) {
+		return
+	}
+
 	// Get the job from database
 	_, err = sharedDB.GetJobByID(id)
 	if err == sharedDB.ErrNotFound {
@@ -194,7 +330,7 @@ func regenerateJobContentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Publish CV generation request
-	err = sharedNats.PublishCVGenerationRequest(strconv.Itoa(id), &requestBody.PromptID)
+	err = sharedNats.PublishCVGenerationRequest(strconv.Itoa(id), requestBody.CvPromptID)
 	if err != nil {
 		log.Printf("Failed to publish CV generation request: %v", err)
 		http.Error(w, "Failed to publish CV generation request", http.StatusInternalServerError)
@@ -202,7 +338,7 @@ func regenerateJobContentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Publish cover letter generation request
-	err = sharedNats.PublishCoverGenerationRequest(strconv.Itoa(id), &requestBody.PromptID)
+	err = sharedNats.PublishCoverGenerationRequest(strconv.Itoa(id), requestBody.CoverPromptID)
 	if err != nil {
 		log.Printf("Failed to publish cover generation request: %v", err)
 		http.Error(w, "Failed to publish cover generation request", http.StatusInternalServerError)
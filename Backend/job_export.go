@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// maxExportJobs bounds the jobs of one export
+const maxExportJobs = 10000
+
+var errExportTooLarge = fmt.Errorf("exports are limited to %d jobs, narrow the filters", maxExportJobs)
+
+// exportJob is a job with the dates it first reached each status
+type exportJob struct {
+	models.Job
+	StatusDates map[models.JobStatus]time.Time
+}
+
+// exportColumn is a column of an export. Values are strings, ints, *float64 or *time.Time.
+type exportColumn struct {
+	Name  string
+	Value func(job *exportJob) interface{}
+}
+
+func stringColumn(name string, value func(job *exportJob) string) exportColumn {
+	return exportColumn{Name: name, Value: func(job *exportJob) interface{} { return value(job) }}
+}
+
+// exportColumns lists the columns that can be exported, in export order. The status
+// date columns follow, one per status, named after the status with a Date suffix.
+var exportColumns = []exportColumn{
+	{Name: "id", Value: func(job *exportJob) interface{} { return job.Id }},
+	stringColumn("title", func(job *exportJob) string { return job.Title }),
+	stringColumn("company", func(job *exportJob) string { return job.Company }),
+	stringColumn("link", func(job *exportJob) string { return job.Link }),
+	stringColumn("status", func(job *exportJob) string { return string(job.Status) }),
+	{Name: "score", Value: func(job *exportJob) interface{} { return job.Score }},
+	stringColumn("location", func(job *exportJob) string { return job.Location }),
+	stringColumn("workplaceType", func(job *exportJob) string { return string(job.WorkplaceType) }),
+	stringColumn("employmentType", func(job *exportJob) string { return string(job.EmploymentType) }),
+	stringColumn("seniority", func(job *exportJob) string { return string(job.Seniority) }),
+	{Name: "salaryMin", Value: func(job *exportJob) interface{} { return job.SalaryMin }},
+	{Name: "salaryMax", Value: func(job *exportJob) interface{} { return job.SalaryMax }},
+	stringColumn("salaryCurrency", func(job *exportJob) string { return job.SalaryCurrency }),
+	stringColumn("source", func(job *exportJob) string { return job.Source }),
+	stringColumn("tags", func(job *exportJob) string { return strings.Join(job.Tags, ", ") }),
+	{Name: "createdAt", Value: func(job *exportJob) interface{} { return &job.CreatedAt }},
+	{Name: "appliedAt", Value: func(job *exportJob) interface{} { return job.AppliedAt }},
+	stringColumn("description", func(job *exportJob) string { return job.Description }),
+}
+
+// documentColumns hold the generated documents, exported with documents=true
+var documentColumns = []exportColumn{
+	stringColumn("cv", func(job *exportJob) string { return job.Cv }),
+	stringColumn("coverLetter", func(job *exportJob) string { return job.CoverLetter }),
+}
+
+func init() {
+	for _, status := range models.JobStatuses {
+		exportColumns = append(exportColumns, exportColumn{
+			Name: string(status) + "Date",
+			Value: func(job *exportJob) interface{} {
+				if at, ok := job.StatusDates[status]; ok {
+					return &at
+				}
+				return (*time.Time)(nil)
+			},
+		})
+	}
+}
+
+// defaultExportColumns are exported when no columns are chosen: every column but the description
+func defaultExportColumns() []exportColumn {
+	columns := make([]exportColumn, 0, len(exportColumns))
+	for _, column := range exportColumns {
+		if column.Name != "description" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// parseExportColumns resolves a comma separated list of column names
+func parseExportColumns(list string) ([]exportColumn, error) {
+	if strings.TrimSpace(list) == "" {
+		return defaultExportColumns(), nil
+	}
+	var columns []exportColumn
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, column := range exportColumns {
+			if column.Name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown export column %q", name)
+		}
+	}
+	return columns, nil
+}
+
+// exportJobsHandler serves GET /api/jobs/export?format=csv|xlsx|json. It takes the
+// filters and sort of the job list, a columns list and documents=true to add the
+// generated CV and cover letter. Every matching job is exported, without paging.
+func exportJobsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	values := r.URL.Query()
+	format := values.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" && format != "json" {
+		http.Error(w, "Invalid format, expected csv, xlsx or json", http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseJobQuery(values)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.Cursor = ""
+	query.Limit = sharedDB.MaxJobPageSize
+
+	columns, err := parseExportColumns(values.Get("columns"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if documents := values.Get("documents"); documents != "" {
+		include, err := strconv.ParseBool(documents)
+		if err != nil {
+			http.Error(w, "invalid documents, expected true or false", http.StatusBadRequest)
+			return
+		}
+		if include {
+			columns = append(columns, documentColumns...)
+		}
+	}
+
+	jobs, err := loadExportJobs(query)
+	if errors.Is(err, errExportTooLarge) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("jobs-%s.%s", time.Now().UTC().Format("20060102"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		err = writeCSVExport(w, columns, jobs)
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = writeJSONExport(w, columns, jobs)
+	case "xlsx":
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		err = writeXLSXExport(w, columns, jobs)
+	}
+	if err != nil {
+		log.Printf("Error writing %s export: %v", format, err)
+	}
+}
+
+// loadExportJobs pages through every job matching the query with its status dates
+func loadExportJobs(query sharedDB.JobQuery) ([]exportJob, error) {
+	jobs := []exportJob{}
+	for {
+		page, next, err := sharedDB.QueryJobs(query)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int, len(page))
+		for i, job := range page {
+			ids[i] = job.Id
+		}
+		dates, err := sharedDB.GetJobStatusDates(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range page {
+			jobs = append(jobs, exportJob{Job: job, StatusDates: dates[job.Id]})
+		}
+
+		if len(jobs) > maxExportJobs {
+			return nil, errExportTooLarge
+		}
+		if next == "" {
+			return jobs, nil
+		}
+		query.Cursor = next
+	}
+}
+
+// exportText formats a value for the text formats: numbers without trailing zeros,
+// dates in RFC 3339 and missing values as empty strings
+func exportText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case *float64:
+		if v != nil {
+			return strconv.FormatFloat(*v, 'f', -1, 64)
+		}
+	case *time.Time:
+		if v != nil {
+			return v.UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+// escapeCSVFormula prefixes text that spreadsheet applications would evaluate as a
+// formula with an apostrophe, so scraped values like "=HYPERLINK(...)" stay text
+func escapeCSVFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func writeCSVExport(w http.ResponseWriter, columns []exportColumn, jobs []exportJob) error {
+	writer := csv.NewWriter(w)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.Name
+	}
+	if err := writer.Write(record); err != nil {
+		return err
+	}
+	for i := range jobs {
+		for j, column := range columns {
+			value := column.Value(&jobs[i])
+			if text, ok := value.(string); ok {
+				record[j] = escapeCSVFormula(text)
+			} else {
+				record[j] = exportText(value)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeJSONExport writes an array of objects keyed by column name, missing numbers
+// and dates are null
+func writeJSONExport(w http.ResponseWriter, columns []exportColumn, jobs []exportJob) error {
+	rows := make([]map[string]interface{}, len(jobs))
+	for i := range jobs {
+		row := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			switch v := column.Value(&jobs[i]).(type) {
+			case *time.Time:
+				if v != nil {
+					row[column.Name] = v.UTC().Format(time.RFC3339)
+				} else {
+					row[column.Name] = nil
+				}
+			default:
+				row[column.Name] = v
+			}
+		}
+		rows[i] = row
+	}
+	return json.NewEncoder(w).Encode(rows)
+}
+
+// writeXLSXExport writes a single sheet with a header row. Numbers are number cells
+// and dates are date cells so they sort and filter in spreadsheet applications. Text
+// is written as string cells, which are never evaluated as formulas.
+func writeXLSXExport(w http.ResponseWriter, columns []exportColumn, jobs []exportJob) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheet = "Jobs"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+	dateFormat := "yyyy-mm-dd hh:mm"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return err
+	}
+	headerStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: column.Name}
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	row := make([]interface{}, len(columns))
+	for i := range jobs {
+		for j, column := range columns {
+			switch v := column.Value(&jobs[i]).(type) {
+			case *float64:
+				if v != nil {
+					row[j] = *v
+				} else {
+					row[j] = nil
+				}
+			case *time.Time:
+				if v != nil {
+					row[j] = excelize.Cell{StyleID: dateStyle, Value: v.UTC()}
+				} else {
+					row[j] = nil
+				}
+			case string:
+				row[j] = excelize.Cell{Value: v}
+			default:
+				row[j] = v
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+)
+
+// postingPageClient only reaches public addresses, the URL comes from the client
+var postingPageClient = extract.NewClient(20 * time.Second)
+
+// jobFromURLHandler serves POST /api/jobs/from-url with {"url"}. The posting page is
+// downloaded, its title, company, location and description are extracted and the job
+// is created like one added by hand. The extracted posting is part of the answer.
+func jobFromURLHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var requestBody struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	pageURL, err := url.Parse(strings.TrimSpace(requestBody.URL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		http.Error(w, "url must be an http or https link", http.StatusBadRequest)
+		return
+	}
+
+	job, err := extract.Fetch(r.Context(), postingPageClient, pageURL.String())
+	if err == extract.ErrNoPosting {
+		http.Error(w, "No job posting found at this URL", http.StatusUnprocessableEntity)
+		return
+	} else if errors.Is(err, extract.ErrBlockedAddress) {
+		http.Error(w, "url must point to a public website", http.StatusBadRequest)
+		return
+	} else if err != nil {
+		log.Printf("Failed to fetch posting %s: %v", pageURL, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	publishExtractedPosting(w, job)
+}
+
+// publishExtractedPosting requests the creation of a job extracted from a page and
+// answers with the posting and the parser used, so the client can show what was found
+func publishExtractedPosting(w http.ResponseWriter, job models.JobPosting) {
+	if job.Company == "" {
+		http.Error(w, "Could not find the company of this posting, add the job by hand", http.StatusUnprocessableEntity)
+		return
+	}
+	if err := validateJobDetails(job.JobDetails); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	known, err := sharedDB.GetJobByLink(job.Link)
+	if err != nil && err != sharedDB.ErrNotFound {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	if err := sharedNats.PublishJobCreationRequest(job); err != nil {
+		log.Printf("Failed to publish job creation request: %v", err)
+		http.Error(w, "Failed to process job creation request", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{"status": "accepted", "posting": job, "parser": extract.Parser(job.Link)}
+	w.Header().Set("Content-Type", "application/json")
+	if known != nil {
+		log.Printf("Job already known with ID %d: %s at %s", known.Id, job.Title, job.Company)
+		response["status"] = "already_known"
+		response["id"] = known.Id
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	log.Printf("Job creation request published from %s: %s at %s", job.Link, job.Title, job.Company)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/posting"
+)
+
+// maxImportRows bounds the rows of one import; the rest of the file is not read
+const maxImportRows = 5000
+
+// Statuses of the rows of an import report
+const (
+	importCreated = "created" // Creation requested from JobService
+	importSkipped = "skipped" // Already known, or a repeat of an earlier row
+	importInvalid = "invalid"
+)
+
+// importFields maps CSV column names, lower case without spaces or punctuation, to
+// the posting fields they hold
+var importFields = map[string]string{
+	"title": "title", "jobtitle": "title", "position": "title", "role": "title",
+	"company": "company", "companyname": "company", "employer": "company",
+	"link": "link", "url": "link", "joburl": "link", "joblink": "link",
+	"description": "description", "jobdescription": "description",
+	"location": "location", "city": "location",
+	"workplacetype": "workplaceType", "workplace": "workplaceType",
+	"employmenttype": "employmentType", "jobtype": "employmentType",
+	"seniority": "seniority", "level": "seniority",
+	"salarymin": "salaryMin", "minsalary": "salaryMin",
+	"salarymax": "salaryMax", "maxsalary": "salaryMax",
+	"salarycurrency": "salaryCurrency", "currency": "salaryCurrency",
+	"postedat": "postedAt", "posted": "postedAt", "dateposted": "postedAt",
+}
+
+// importRow is the outcome of one row of an import, rows are numbered from 1
+// without the CSV header
+type importRow struct {
+	Row     int    `json:"row"`
+	Status  string `json:"status"`
+	Title   string `json:"title,omitempty"`
+	Company string `json:"company,omitempty"`
+	JobId   int    `json:"jobId,omitempty"` // Stored job a skipped row is a copy of
+	Reason  string `json:"reason,omitempty"`
+}
+
+// importReport is the answer of an import. Error is set when the import stopped
+// early; the rows before it were processed.
+type importReport struct {
+	Created int         `json:"created"`
+	Skipped int         `json:"skipped"`
+	Invalid int         `json:"invalid"`
+	Rows    []importRow `json:"rows"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// jobImporter validates, deduplicates and publishes the rows of one import
+type jobImporter struct {
+	report importReport
+	seen   map[posting.Key]int // Row of each posting key accepted so far
+}
+
+// importJobsHandler serves POST /api/jobs/import. The body is a CSV file whose header
+// names the columns, or a JSON array of postings, chosen by the format parameter or
+// the Content-Type. CSV columns with other names are mapped with an X-Column-Mapping
+// header such as "Employer=company, Role name=title". The body is read row by row.
+func importJobsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch {
+		case strings.HasSuffix(mediaType, "csv"):
+			format = "csv"
+		case strings.HasSuffix(mediaType, "json"):
+			format = "json"
+		}
+	}
+
+	importer := &jobImporter{report: importReport{Rows: []importRow{}}, seen: map[posting.Key]int{}}
+	var err error
+	switch format {
+	case "csv":
+		err = importer.readCSV(r.Body, r.Header.Get("X-Column-Mapping"))
+	case "json":
+		err = importer.readJSON(r.Body)
+	default:
+		http.Error(w, "Unsupported format, send text/csv or application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	report := importer.report
+	status := http.StatusOK
+	if err != nil {
+		report.Error = err.Error()
+		if len(report.Rows) == 0 {
+			status = http.StatusBadRequest
+		}
+	}
+	log.Printf("Job import: %d created, %d skipped, %d invalid", report.Created, report.Skipped, report.Invalid)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseColumnMapping reads "name=field" pairs separated by commas
+func parseColumnMapping(header string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, field, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid X-Column-Mapping entry %q, expected column=field", pair)
+		}
+		target, known := importFields[columnKey(field)]
+		if !known {
+			return nil, fmt.Errorf("unknown field %q in X-Column-Mapping", strings.TrimSpace(field))
+		}
+		mapping[columnKey(name)] = target
+	}
+	return mapping, nil
+}
+
+// columnKey normalizes a column name for matching
+func columnKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+func (im *jobImporter) readCSV(body io.Reader, mappingHeader string) error {
+	mapping, err := parseColumnMapping(mappingHeader)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return errors.New("empty CSV file")
+	} else if err != nil {
+		return fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	// Field held by each column; unknown columns are ignored
+	fields := make([]string, len(header))
+	found := map[string]bool{}
+	for i, name := range header {
+		key := columnKey(strings.TrimPrefix(name, "\ufeff"))
+		if field, ok := mapping[key]; ok {
+			fields[i] = field
+		} else {
+			fields[i] = importFields[key]
+		}
+		found[fields[i]] = true
+	}
+	for _, required := range []string{"title", "company", "link"} {
+		if !found[required] {
+			return fmt.Errorf("CSV header has no %s column", required)
+		}
+	}
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if row > maxImportRows {
+			return fmt.Errorf("imports are limited to %d rows, the rest was not read", maxImportRows)
+		}
+
+		var p models.JobPosting
+		if err == nil {
+			for i, value := range record {
+				if i < len(fields) && fields[i] != "" {
+					if fieldErr := setImportField(&p, fields[i], strings.TrimSpace(value)); fieldErr != nil {
+						err = fieldErr
+						break
+					}
+				}
+			}
+		} else if _, ok := err.(*csv.ParseError); !ok {
+			return err
+		}
+		if err := im.add(row, p, err); err != nil {
+			return err
+		}
+	}
+}
+
+// setImportField sets a posting field from its CSV text
+func setImportField(p *models.JobPosting, field, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch field {
+	case "title":
+		p.Title = value
+	case "company":
+		p.Company = value
+	case "link":
+		p.Link = value
+	case "description":
+		p.Description = value
+	case "location":
+		p.Location = value
+	case "workplaceType":
+		p.WorkplaceType = models.WorkplaceType(value)
+	case "employmentType":
+		p.EmploymentType = models.EmploymentType(value)
+	case "seniority":
+		p.Seniority = models.Seniority(value)
+	case "salaryMin", "salaryMax":
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", field, value)
+		}
+		if field == "salaryMin" {
+			p.SalaryMin = &amount
+		} else {
+			p.SalaryMax = &amount
+		}
+	case "salaryCurrency":
+		p.SalaryCurrency = strings.ToUpper(value)
+	case "postedAt":
+		postedAt, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			if postedAt, err = time.Parse(time.RFC3339, value); err != nil {
+				return fmt.Errorf("invalid postedAt %q, expected YYYY-MM-DD or RFC 3339", value)
+			}
+		}
+		p.PostedAt = &postedAt
+	}
+	return nil
+}
+
+func (im *jobImporter) readJSON(body io.Reader) error {
+	decoder := json.NewDecoder(body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return errors.New("expected a JSON array of jobs")
+	}
+
+	for row := 1; decoder.More(); row++ {
+		if row > maxImportRows {
+			return fmt.Errorf("imports are limited to %d rows, the rest was not read", maxImportRows)
+		}
+
+		// Malformed JSON ends the import, a row of the wrong shape is only invalid
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid JSON at row %d: %w", row, err)
+		}
+		var p models.JobPosting
+		err := json.Unmarshal(raw, &p)
+		if err != nil {
+			err = errors.New("row is not a job object with the expected field types")
+		}
+		if err := im.add(row, p, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add reports a row and publishes its creation request when it is valid and new.
+// rowErr is the parse error of the row, if any. Errors are returned for failures
+// that end the import.
+func (im *jobImporter) add(row int, p models.JobPosting, rowErr error) error {
+	p.Title = strings.TrimSpace(p.Title)
+	p.Company = strings.TrimSpace(p.Company)
+	p.Link = strings.TrimSpace(p.Link)
+	result := importRow{Row: row, Title: p.Title, Company: p.Company}
+
+	// The posting key is derived from the link, as for jobs added one by one
+	p.Source, p.ExternalId = "", ""
+	key := posting.Canonicalize(p.Link)
+	switch {
+	case rowErr != nil:
+		result.Reason = rowErr.Error()
+	case p.Title == "" || p.Company == "":
+		result.Reason = "title and company are required"
+	case p.Link == "":
+		result.Reason = "link is required"
+	case key.IsZero():
+		result.Reason = "invalid link"
+	default:
+		if err := validateJobDetails(p.JobDetails); err != nil {
+			result.Reason = err.Error()
+		}
+	}
+	if result.Reason != "" {
+		result.Status = importInvalid
+		im.record(result)
+		return nil
+	}
+
+	if first, ok := im.seen[key]; ok {
+		result.Status = importSkipped
+		result.Reason = fmt.Sprintf("same posting as row %d", first)
+		im.record(result)
+		return nil
+	}
+	known, err := sharedDB.GetJobByLink(p.Link)
+	if err != nil && err != sharedDB.ErrNotFound {
+		return fmt.Errorf("could not check row %d against the stored jobs: %w", row, err)
+	}
+	if known != nil {
+		result.Status = importSkipped
+		result.JobId = known.Id
+		result.Reason = "job already known"
+		im.record(result)
+		return nil
+	}
+
+	if err := sharedNats.PublishJobCreationRequest(p); err != nil {
+		return fmt.Errorf("could not request the creation of row %d: %w", row, err)
+	}
+	im.seen[key] = row
+	result.Status = importCreated
+	im.record(result)
+	return nil
+}
+
+func (im *jobImporter) record(result importRow) {
+	switch result.Status {
+	case importCreated:
+		im.report.Created++
+	case importSkipped:
+		im.report.Skipped++
+	case importInvalid:
+		im.report.Invalid++
+	}
+	im.report.Rows = append(im.report.Rows, result)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hirepilot/shared/extract"
+)
+
+// maxIngestBodySize bounds the page HTML pushed to the ingest endpoint
+const maxIngestBodySize = 10 << 20
+
+// ingestHTMLHandler serves POST /api/jobs/ingest-html with {"url", "html"}, the page
+// a browser extension or bookmarklet is showing. The posting is read with the parser
+// of the site and created like a job added by hand. Requires a personal API token.
+func ingestHTMLHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAPIToken(w, r) {
+		return
+	}
+
+	var requestBody struct {
+		URL  string `json:"url"`
+		HTML string `json:"html"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIngestBodySize)).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON or page too large", http.StatusBadRequest)
+		return
+	}
+	pageURL, err := url.Parse(strings.TrimSpace(requestBody.URL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		http.Error(w, "url must be an http or https link", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(requestBody.HTML) == "" {
+		http.Error(w, "html is required", http.StatusBadRequest)
+		return
+	}
+
+	job, err := extract.Page(strings.NewReader(requestBody.HTML), pageURL.String())
+	if err == extract.ErrNoPosting {
+		http.Error(w, "No job posting found on this page", http.StatusUnprocessableEntity)
+		return
+	} else if err != nil {
+		http.Error(w, "Invalid HTML", http.StatusBadRequest)
+		return
+	}
+	publishExtractedPosting(w, job)
+}
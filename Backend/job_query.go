@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// parseJobQuery reads the filters, sort order and page of GET /api/jobs:
+// status, company, minScore, maxScore, from, to (YYYY-MM-DD, inclusive), hasCv, q,
+// location, workplaceType, employmentType, seniority, source, minSalary, currency,
+// tag (repeatable, jobs need every tag), skill (name or alias of a requirement), sort (created, applied or score), order (asc or desc), limit and cursor
+func parseJobQuery(values url.Values) (sharedDB.JobQuery, error) {
+	query := sharedDB.JobQuery{
+		Status:  values.Get("status"),
+		Company: values.Get("company"),
+		Search:  values.Get("q"),
+		Cursor:  values.Get("cursor"),
+
+		Location:       values.Get("location"),
+		WorkplaceType:  models.WorkplaceType(values.Get("workplaceType")),
+		EmploymentType: models.EmploymentType(values.Get("employmentType")),
+		Seniority:      models.Seniority(values.Get("seniority")),
+		Source:         values.Get("source"),
+		SalaryCurrency: strings.ToUpper(values.Get("currency")),
+	}
+	if skill := values.Get("skill"); skill != "" {
+		query.Skill = skillName(skill)
+	}
+	if err := validateJobDetails(models.JobDetails{
+		WorkplaceType:  query.WorkplaceType,
+		EmploymentType: query.EmploymentType,
+		Seniority:      query.Seniority,
+	}); err != nil {
+		return query, err
+	}
+
+	var err error
+	if query.Tags, err = normalizeTags(values["tag"]); err != nil {
+		return query, err
+	}
+	if query.MinScore, err = optionalFloat(values, "minScore"); err != nil {
+		return query, err
+	}
+	if query.MaxScore, err = optionalFloat(values, "maxScore"); err != nil {
+		return query, err
+	}
+	if query.MinSalary, err = optionalFloat(values, "minSalary"); err != nil {
+		return query, err
+	}
+
+	if from := values.Get("from"); from != "" {
+		day, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return query, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+		query.CreatedAfter = &day
+	}
+	if to := values.Get("to"); to != "" {
+		day, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return query, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+		dayAfter := day.AddDate(0, 0, 1)
+		query.CreatedBefore = &dayAfter
+	}
+
+	if hasCV := values.Get("hasCv"); hasCV != "" {
+		b, err := strconv.ParseBool(hasCV)
+		if err != nil {
+			return query, fmt.Errorf("invalid hasCv, expected true or false")
+		}
+		query.HasCV = &b
+	}
+
+	switch sort := values.Get("sort"); sort {
+	case "", sharedDB.JobSortCreated, sharedDB.JobSortApplied, sharedDB.JobSortScore:
+		query.Sort = sort
+	default:
+		return query, fmt.Errorf("invalid sort, expected created, applied or score")
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, fmt.Errorf("invalid order, expected asc or desc")
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return query, fmt.Errorf("invalid limit")
+		}
+		query.Limit = n
+	}
+
+	return query, nil
+}
+
+// validateJobDetails rejects unknown workplace, employment and seniority values
+func validateJobDetails(details models.JobDetails) error {
+	if !details.WorkplaceType.Valid() {
+		return fmt.Errorf("invalid workplaceType, expected remote, hybrid or on_site")
+	}
+	if !details.EmploymentType.Valid() {
+		return fmt.Errorf("invalid employmentType, expected full_time, part_time, contract, temporary or internship")
+	}
+	if !details.Seniority.Valid() {
+		return fmt.Errorf("invalid seniority, expected internship, entry, associate, mid_senior, director or executive")
+	}
+	if details.SalaryCurrency != "" && len(details.SalaryCurrency) != 3 {
+		return fmt.Errorf("salaryCurrency must be a three letter ISO 4217 code")
+	}
+	if details.SalaryMin != nil && details.SalaryMax != nil && *details.SalaryMin > *details.SalaryMax {
+		return fmt.Errorf("salaryMin must not exceed salaryMax")
+	}
+	return nil
+}
+
+func optionalFloat(values url.Values, name string) (*float64, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	return &f, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	sharedDB "github.com/hirepilot/shared/db"
 	sharedNats "github.com/hirepilot/shared/nats"
@@ -11,7 +12,7 @@ import (
 // CORS helper functions
 func setCORSHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Column-Mapping")
 }
 
 func handleCORS(w http.ResponseWriter, methods string) {
@@ -40,22 +41,28 @@ func main() {
 		}
 	})
 	http.HandleFunc("/api/jobs/", func(w http.ResponseWriter, r *http.Request) {
-		if len(r.URL.Path) > len("/api/jobs/") &&
-			len(r.URL.Path) > len("/apply") &&
-			r.URL.Path[len(r.URL.Path)-len("/apply"):] == "/apply" {
-			if r.Method == http.MethodPut || r.Method == http.MethodOptions {
-				updateJobStatusHandler(w, r, "applied")
-			} else {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		} else if len(r.URL.Path) > len("/api/jobs/") &&
-			len(r.URL.Path) > len("/close") &&
-			r.URL.Path[len(r.URL.Path)-len("/close"):] == "/close" {
-			if r.Method == http.MethodPut || r.Method == http.MethodOptions {
-				updateJobStatusHandler(w, r, "closed")
-			} else {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
+		if r.URL.Path == "/api/jobs/import" {
+			importJobsHandler(w, r)
+		} else if r.URL.Path == "/api/jobs/export" {
+			exportJobsHandler(w, r)
+		} else if r.URL.Path == "/api/jobs/from-url" {
+			jobFromURLHandler(w, r)
+		} else if r.URL.Path == "/api/jobs/ingest-html" {
+			ingestHTMLHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/status") {
+			updateJobStatusHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/history") {
+			jobStatusHistoryHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/timeline") {
+			jobTimelineHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/notes") {
+			jobNotesHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/contacts") {
+			jobContactsHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/tags") {
+			jobTagsHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/skills") {
+			jobSkillsHandler(w, r)
 		} else if len(r.URL.Path) > len("/generate-cv") &&
 			r.URL.Path[len(r.URL.Path)-len("/generate-cv"):] == "/generate-cv" {
 			generateCVHandler(w, r)
@@ -72,6 +79,11 @@ func main() {
 			getJobHandler(w, r)
 		}
 	})
+	http.HandleFunc("/api/tags", listTagsHandler)
+	http.HandleFunc("/api/tags/bulk", bulkTagHandler)
+	http.HandleFunc("/api/skills/top", topSkillsHandler)
+	http.HandleFunc("/api/tokens", apiTokensHandler)
+	http.HandleFunc("/api/tokens/", apiTokenHandler)
 	http.HandleFunc("/api/prompts", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
@@ -91,6 +103,134 @@ func main() {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
+	http.HandleFunc("/api/prompts/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/versions") {
+			listPromptVersionsHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/diff") {
+			diffPromptVersionsHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/rollback") {
+			rollbackPromptHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/default") {
+			setDefaultPromptHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/unarchive") {
+			archivePromptHandler(w, r, false)
+		} else if strings.HasSuffix(r.URL.Path, "/archive") {
+			archivePromptHandler(w, r, true)
+		} else if strings.HasSuffix(r.URL.Path, "/preview") {
+			previewPromptHandler(w, r)
+		} else if r.Method == http.MethodDelete {
+			deletePromptHandler(w, r)
+		} else if r.Method == http.MethodOptions {
+			handleCORS(w, "DELETE, OPTIONS")
+		} else {
+			http.NotFound(w, r)
+		}
+	})
+	http.HandleFunc("/api/benchmarks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			importBenchmarkHandler(w, r)
+		case http.MethodGet:
+			listBenchmarksHandler(w, r)
+		case http.MethodOptions:
+			handleCORS(w, "POST, GET, OPTIONS")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/benchmarks/", getBenchmarkHandler)
+	http.HandleFunc("/api/evaluations", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createEvaluationHandler(w, r)
+		case http.MethodOptions:
+			handleCORS(w, "POST, OPTIONS")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/evaluations/", getEvaluationHandler)
+	http.HandleFunc("/api/experiments", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createExperimentHandler(w, r)
+		case http.MethodGet:
+			listExperimentsHandler(w, r)
+		case http.MethodOptions:
+			handleCORS(w, "POST, GET, OPTIONS")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/experiments/", experimentHandler)
+	http.HandleFunc("/api/interviews", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createInterviewHandler(w, r)
+		case http.MethodGet:
+			listInterviewsHandler(w, r)
+		case http.MethodOptions:
+			handleCORS(w, "POST, GET, OPTIONS")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/interviews/upcoming", upcomingInterviewsHandler)
+	http.HandleFunc("/api/interviews/", interviewHandler)
+	http.HandleFunc("/api/notes/", noteHandler)
+	http.HandleFunc("/api/contacts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createContactHandler(w, r)
+		case http.MethodGet:
+			listContactsHandler(w, r)
+		case http.MethodOptions:
+			handleCORS(w, "POST, GET, OPTIONS")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/contacts/", contactHandler)
+	http.HandleFunc("/api/reminders", listRemindersHandler)
+	http.HandleFunc("/api/reminders/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/snooze") {
+			snoozeReminderHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/dismiss") {
+			dismissReminderHandler(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+	})
+	http.HandleFunc("/api/reminder-rules", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createReminderRuleHandler(w, r)
+		case http.MethodGet:
+			listReminderRulesHandler(w, r)
+		case http.MethodOptions:
+			handleCORS(w, "POST, GET, OPTIONS")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	http.HandleFunc("/api/reminder-rules/", reminderRuleHandler)
+	http.HandleFunc("/api/job-expiry/policies", expiryPoliciesHandler)
+	http.HandleFunc("/api/job-expiry/policies/", expiryPolicyHandler)
+	http.HandleFunc("/api/job-expiry/report", expiryReportHandler)
+	http.HandleFunc("/api/job-boards", jobBoardsHandler)
+	http.HandleFunc("/api/job-boards/", jobBoardHandler)
+	http.HandleFunc("/api/job-feeds", jobFeedsHandler)
+	http.HandleFunc("/api/job-feeds/", jobFeedHandler)
+	http.HandleFunc("/api/status-proposals", listStatusProposalsHandler)
+	http.HandleFunc("/api/status-proposals/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/accept") {
+			acceptStatusProposalHandler(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/dismiss") {
+			dismissStatusProposalHandler(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+	})
 	http.HandleFunc("/api/features", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
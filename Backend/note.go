@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// maxNoteLength is the size of the job_notes.body TEXT column
+const maxNoteLength = 65535
+
+// decodeNote reads {"body", "contactId"} from a request and checks the body fits a note
+func decodeNote(w http.ResponseWriter, r *http.Request) (models.JobNote, bool) {
+	var note models.JobNote
+	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return note, false
+	}
+	if strings.TrimSpace(note.Body) == "" {
+		http.Error(w, "body is required", http.StatusBadRequest)
+		return note, false
+	}
+	if len(note.Body) > maxNoteLength {
+		http.Error(w, "body is too long", http.StatusBadRequest)
+		return note, false
+	}
+	return note, true
+}
+
+// jobNotesHandler serves GET and POST /api/jobs/{id}/notes
+func jobNotesHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, POST, OPTIONS")
+		return
+	}
+
+	jobID, err := jobIDFromPath(r.URL.Path, "/notes")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		notes, err := sharedDB.GetJobNotes(jobID)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(notes)
+	case http.MethodPost:
+		note, ok := decodeNote(w, r)
+		if !ok {
+			return
+		}
+		note.JobId = jobID
+
+		id, err := sharedDB.InsertJobNote(note)
+		if err == sharedDB.ErrNotFound {
+			http.Error(w, "Job or contact not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB insert error", http.StatusInternalServerError)
+			return
+		}
+
+		created, err := sharedDB.GetJobNoteByID(int(id))
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// noteHandler serves GET, PUT and DELETE /api/notes/{id}
+func noteHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, PUT, DELETE, OPTIONS")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/notes/"))
+	if err != nil {
+		http.Error(w, "Invalid note ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		note, err := sharedDB.GetJobNoteByID(id)
+		if err == sharedDB.ErrNotFound {
+			http.Error(w, "Note not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(note)
+	case http.MethodPut:
+		note, ok := decodeNote(w, r)
+		if !ok {
+			return
+		}
+
+		if err := sharedDB.UpdateJobNote(id, note.Body); err == sharedDB.ErrNotFound {
+			http.Error(w, "Note not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB update error", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := sharedDB.GetJobNoteByID(id)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if err := sharedDB.DeleteJobNote(id); err == sharedDB.ErrNotFound {
+			http.Error(w, "Note not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// jobTimelineHandler serves GET /api/jobs/{id}/timeline, the activities of a job oldest first
+func jobTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID, err := jobIDFromPath(r.URL.Path, "/timeline")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := sharedDB.GetJobByID(jobID); err == sharedDB.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	timeline, err := sharedDB.GetJobTimeline(jobID)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(timeline)
+}
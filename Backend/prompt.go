@@ -2,9 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	sharedDB "github.com/hirepilot/shared/db"
 	"github.com/hirepilot/shared/models"
@@ -21,14 +23,23 @@ func addPromptHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	var prompt models.Prompt
-	if err := json.NewDecoder(r.Body).Decode(&prompt); err != nil {
+	var request promptRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
+	prompt, err := request.normalize(nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := normalizePromptTag(&prompt); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Publish prompt creation request to NATS JetStream (PromptService will handle DB insertion)
-	err := sharedNats.PublishPromptCreationRequest(prompt.Name, prompt.Prompt, prompt.CvGenerationDefault, prompt.ScoreGenerationDefault, prompt.CoverGenerationDefault)
+	err = sharedNats.PublishPromptCreationRequest(prompt.Name, prompt.Prompt, prompt.Kind, prompt.IsDefault, prompt.Tag)
 	if err != nil {
 		log.Printf("Failed to publish prompt creation request: %v", err)
 		http.Error(w, "Failed to process prompt creation request", http.StatusInternalServerError)
@@ -50,19 +61,46 @@ func listPromptsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prompts, err := sharedDB.GetAllPrompts()
+	var prompts []models.Prompt
+	var err error
+	includeArchived := r.URL.Query().Get("archived") == "true"
+	if kind := models.PromptKind(r.URL.Query().Get("kind")); kind != "" {
+		if !kind.Valid() {
+			http.Error(w, "Unknown prompt kind", http.StatusBadRequest)
+			return
+		}
+		prompts, err = sharedDB.GetPromptsByKind(kind, includeArchived)
+	} else {
+		prompts, err = sharedDB.GetAllPrompts(includeArchived)
+	}
 	if err != nil {
 		http.Error(w, "DB query error", http.StatusInternalServerError)
 		return
 	}
 
+	var response interface{} = prompts
+	if r.URL.Query().Get("groupBy") == "kind" {
+		// Every known kind is present, even without prompts, so clients can render empty groups
+		grouped := make(map[models.PromptKind][]models.Prompt)
+		for _, kind := range models.PromptKinds {
+			grouped[kind] = []models.Prompt{}
+		}
+		for _, prompt := range prompts {
+			grouped[prompt.Kind] = append(grouped[prompt.Kind], prompt)
+		}
+		response = grouped
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(prompts); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		http.Error(w, "JSON encode error", http.StatusInternalServerError)
 		return
 	}
 }
 
+// getPromptByID loads the stored prompt of an update, replaced in tests
+var getPromptByID = sharedDB.GetPromptByID
+
 func updatePromptHandler(w http.ResponseWriter, r *http.Request) {
 	setCORSHeaders(w)
 	if r.Method == http.MethodOptions {
@@ -74,14 +112,40 @@ func updatePromptHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var prompt models.Prompt
-	if err := json.NewDecoder(r.Body).Decode(&prompt); err != nil {
+	var request promptRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
+	current, err := getPromptByID(request.Id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	prompt, err := request.normalize(current)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := normalizePromptTag(&prompt); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if prompt.IsDefault && current.Archived {
+		http.Error(w, "An archived prompt cannot be the default", http.StatusConflict)
+		return
+	}
+	// Every kind keeps its default; it only moves by promoting another prompt of the kind
+	if current.IsDefault && (!prompt.IsDefault || prompt.Kind != current.Kind) {
+		http.Error(w, "This prompt is the default of its kind, make another prompt the default first", http.StatusConflict)
+		return
+	}
 
 	// Publish prompt update request to NATS JetStream (PromptService will handle DB update)
-	err := sharedNats.PublishPromptUpdateRequest(prompt.Id, prompt.Name, prompt.Prompt, prompt.CvGenerationDefault, prompt.ScoreGenerationDefault, prompt.CoverGenerationDefault)
+	err = sharedNats.PublishPromptUpdateRequest(prompt.Id, prompt.Name, prompt.Prompt, prompt.Kind, prompt.IsDefault, prompt.Tag)
 	if err != nil {
 		log.Printf("Failed to publish prompt update request: %v", err)
 		http.Error(w, "Failed to process prompt update request", http.StatusInternalServerError)
@@ -132,3 +196,226 @@ func getPromptHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// promptRequest is the body of prompt create and update requests. IsDefault is a
+// pointer so that updates leaving it out keep the stored default.
+type promptRequest struct {
+	models.Prompt
+	IsDefault *bool `json:"isDefault"`
+}
+
+// normalize validates the kind of a posted prompt and resolves the fields the client left out.
+// Clients still sending the legacy per-kind default flags get the kind and default derived
+// from them. Otherwise a missing kind is kept from current, the stored prompt on update, and
+// new prompts without one are CV prompts like the unflagged prompts of the original schema.
+func (r promptRequest) normalize(current *models.Prompt) (models.Prompt, error) {
+	prompt := r.Prompt
+	isDefault := r.IsDefault
+
+	if prompt.Kind == "" {
+		flags := 0
+		if prompt.CvGenerationDefault {
+			prompt.Kind = models.PromptKindCV
+			flags++
+		}
+		if prompt.CoverGenerationDefault {
+			prompt.Kind = models.PromptKindCover
+			flags++
+		}
+		if prompt.ScoreGenerationDefault {
+			prompt.Kind = models.PromptKindScore
+			flags++
+		}
+		switch {
+		case flags > 1:
+			return prompt, errors.New("a prompt can only be the default for one kind")
+		case flags == 1:
+			if isDefault == nil {
+				legacyDefault := true
+				isDefault = &legacyDefault
+			}
+		case current != nil:
+			prompt.Kind = current.Kind
+		default:
+			prompt.Kind = models.PromptKindCV
+		}
+	}
+
+	if !prompt.Kind.Valid() {
+		return prompt, errors.New("unknown prompt kind")
+	}
+
+	switch {
+	case isDefault != nil:
+		prompt.IsDefault = *isDefault
+	case current != nil:
+		prompt.IsDefault = current.IsDefault
+	default:
+		prompt.IsDefault = false
+	}
+	return prompt, nil
+}
+
+// normalizePromptTag normalizes the optional tag a prompt is used for
+func normalizePromptTag(prompt *models.Prompt) error {
+	if strings.TrimSpace(prompt.Tag) == "" {
+		prompt.Tag = ""
+		return nil
+	}
+	tag, ok := models.NormalizeTag(prompt.Tag)
+	if !ok {
+		return errors.New("invalid tag, expected letters, digits, dashes, underscores or dots")
+	}
+	prompt.Tag = tag
+	return nil
+}
+
+// setDefaultPromptHandler serves PUT /api/prompts/{id}/default
+func setDefaultPromptHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "PUT, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPut {
+		http.Error(w, "Only PUT allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	promptID, err := promptIDFromPath(r.URL.Path, "/default")
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+
+	prompt, err := sharedDB.GetPromptByID(promptID)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	if prompt.Archived {
+		http.Error(w, "An archived prompt cannot be the default", http.StatusConflict)
+		return
+	}
+
+	// PromptService swaps the default in one transaction
+	if err := sharedNats.PublishPromptSetDefaultRequest(promptID); err != nil {
+		log.Printf("Failed to publish prompt set default request: %v", err)
+		http.Error(w, "Failed to process prompt set default request", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Prompt set default request published for ID: %d", promptID)
+	w.WriteHeader(http.StatusAccepted) // 202 Accepted since processing is async
+}
+
+// deletePromptHandler serves DELETE /api/prompts/{id}
+func deletePromptHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	promptID, err := strconv.Atoi(r.URL.Path[len("/api/prompts/"):])
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+
+	if !checkPromptUnused(w, promptID) {
+		return
+	}
+
+	// Publish prompt delete request to NATS JetStream (PromptService will handle DB deletion)
+	if err := sharedNats.PublishPromptDeleteRequest(promptID); err != nil {
+		log.Printf("Failed to publish prompt delete request: %v", err)
+		http.Error(w, "Failed to process prompt delete request", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Prompt delete request published for ID: %d", promptID)
+	w.WriteHeader(http.StatusAccepted) // 202 Accepted since processing is async
+}
+
+// archivePromptHandler serves POST /api/prompts/{id}/archive and /api/prompts/{id}/unarchive
+func archivePromptHandler(w http.ResponseWriter, r *http.Request, archived bool) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	suffix := "/unarchive"
+	if archived {
+		suffix = "/archive"
+	}
+	promptID, err := promptIDFromPath(r.URL.Path, suffix)
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+
+	if archived {
+		if !checkPromptUnused(w, promptID) {
+			return
+		}
+	} else if _, err := sharedDB.GetPromptByID(promptID); err == sharedDB.ErrNotFound {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	// Publish prompt archive request to NATS JetStream (PromptService will handle DB update)
+	if err := sharedNats.PublishPromptArchiveRequest(promptID, archived); err != nil {
+		log.Printf("Failed to publish prompt archive request: %v", err)
+		http.Error(w, "Failed to process prompt archive request", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Prompt archive request published for ID: %d (archived: %t)", promptID, archived)
+	w.WriteHeader(http.StatusAccepted) // 202 Accepted since processing is async
+}
+
+// checkPromptUnused writes an error response and returns false when a prompt
+// is missing, a default or part of an active experiment
+func checkPromptUnused(w http.ResponseWriter, promptID int) bool {
+	err := sharedDB.CheckPromptUnused(promptID)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return false
+	} else if errors.Is(err, sharedDB.ErrPromptInUse) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return false
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// checkPromptKind writes an error response and returns false when the selected prompt
+// does not exist or was written for another kind. A nil ID selects the default prompt.
+func checkPromptKind(w http.ResponseWriter, promptID *int, kind models.PromptKind) bool {
+	if promptID == nil {
+		return true
+	}
+
+	_, err := sharedDB.GetPromptForKind(*promptID, kind)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return false
+	} else if errors.Is(err, sharedDB.ErrWrongPromptKind) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
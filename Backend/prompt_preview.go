@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/render"
+)
+
+// PromptPreview is the text a prompt would send to the model for a job
+type PromptPreview struct {
+	PromptID        int               `json:"promptId"`
+	Version         int               `json:"version"`
+	Kind            models.PromptKind `json:"kind"`
+	JobID           int               `json:"jobId"`
+	Text            string            `json:"text"`
+	EstimatedTokens int               `json:"estimatedTokens"`
+	Warnings        []string          `json:"warnings"`
+}
+
+// previewPromptHandler serves POST /api/prompts/{id}/preview?jobId=
+// It renders the final model input without calling the AI. The body may carry
+// an unsaved prompt text to preview edits before saving them.
+func previewPromptHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	promptID, err := promptIDFromPath(r.URL.Path, "/preview")
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+	jobID, err := strconv.Atoi(r.URL.Query().Get("jobId"))
+	if err != nil {
+		http.Error(w, "Missing or invalid jobId parameter", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		Prompt string `json:"prompt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil && err != io.EOF {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	prompt, err := sharedDB.GetPromptByID(promptID)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	job, err := sharedDB.GetJobByID(jobID)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	promptText := prompt.Prompt
+	if requestBody.Prompt != "" {
+		promptText = requestBody.Prompt
+	}
+
+	var text string
+	if prompt.Kind == models.PromptKindCover {
+		// Cover letters are addressed to the hiring manager, as CoverGenerator does
+		hiringManager, err := sharedDB.GetJobHiringManager(job.Id)
+		if err != nil && err != sharedDB.ErrNotFound {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		text = render.CoverForJob(promptText, job, hiringManager)
+	} else {
+		text, err = render.ForJob(prompt.Kind, promptText, job)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	warnings := []string{}
+	if prompt.Kind == models.PromptKindScore && job.Cv == "" {
+		warnings = append(warnings, "job has no generated CV yet, the score prompt would be sent without one")
+	}
+	if job.Description == "" {
+		warnings = append(warnings, "job has no description")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PromptPreview{
+		PromptID:        prompt.Id,
+		Version:         prompt.Version,
+		Kind:            prompt.Kind,
+		JobID:           job.Id,
+		Text:            text,
+		EstimatedTokens: sharedAI.EstimateTokens(text),
+		Warnings:        warnings,
+	})
+}
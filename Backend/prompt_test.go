@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hirepilot/shared/models"
+)
+
+func TestUpdatePromptKeepsDefault(t *testing.T) {
+	stored := &models.Prompt{Id: 1, Name: "DefaultCvGenerator", Prompt: "Write a CV", Kind: models.PromptKindCV, IsDefault: true, Version: 3}
+	original := getPromptByID
+	getPromptByID = func(id int) (*models.Prompt, error) { return stored, nil }
+	t.Cleanup(func() { getPromptByID = original })
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"clears isDefault", `{"id":1,"name":"DefaultCvGenerator","prompt":"Write a CV","kind":"cv","isDefault":false}`},
+		{"changes kind", `{"id":1,"name":"DefaultCvGenerator","prompt":"Write a CV","kind":"cover"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/prompts", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			updatePromptHandler(rec, req)
+			if rec.Code != http.StatusConflict {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body.String())
+			}
+		})
+	}
+}
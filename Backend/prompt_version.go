@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	sharedNats "github.com/hirepilot/shared/nats"
+)
+
+// DiffLine is a single line of a line-based diff between two prompt versions
+type DiffLine struct {
+	Op   string `json:"op"` // "equal", "insert" or "delete"
+	Text string `json:"text"`
+}
+
+// promptIDFromPath extracts the id from /api/prompts/{id}{suffix}
+func promptIDFromPath(path, suffix string) (int, error) {
+	idWithAction := path[len("/api/prompts/"):] // e.g. "3/versions"
+	idStr := idWithAction[:len(idWithAction)-len(suffix)]
+	return strconv.Atoi(idStr)
+}
+
+func listPromptVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract id from URL: /api/prompts/{id}/versions
+	id, err := promptIDFromPath(r.URL.Path, "/versions")
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+
+	versions, err := sharedDB.GetPromptVersions(id)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	if len(versions) == 0 {
+		http.Error(w, "Prompt not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+func diffPromptVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract id from URL: /api/prompts/{id}/diff?from=1&to=2
+	id, err := promptIDFromPath(r.URL.Path, "/diff")
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from parameter", http.StatusBadRequest)
+		return
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to parameter", http.StatusBadRequest)
+		return
+	}
+
+	fromVersion, err := sharedDB.GetPromptVersion(id, from)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Version not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	toVersion, err := sharedDB.GetPromptVersion(id, to)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Version not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"prompt_id":    id,
+		"from":         fromVersion.Version,
+		"to":           toVersion.Version,
+		"name_changed": fromVersion.Name != toVersion.Name,
+		"lines":        diffLines(fromVersion.Prompt, toVersion.Prompt),
+	})
+}
+
+func rollbackPromptHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract id from URL: /api/prompts/{id}/rollback
+	id, err := promptIDFromPath(r.URL.Path, "/rollback")
+	if err != nil {
+		http.Error(w, "Invalid prompt ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		Version int `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	// Validate the target version before handing the rollback to PromptService
+	_, err = sharedDB.GetPromptVersion(id, requestBody.Version)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Version not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := sharedNats.PublishPromptRollbackRequest(id, requestBody.Version); err != nil {
+		log.Printf("Failed to publish prompt rollback request: %v", err)
+		http.Error(w, "Failed to process prompt rollback request", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Prompt rollback request published for ID: %d to version: %d", id, requestBody.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted) // 202 Accepted since processing is async
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Prompt rollback requested",
+		"prompt_id": id,
+		"version":   requestBody.Version,
+	})
+}
+
+// diffLines computes a line-based diff using the longest common subsequence of lines
+func diffLines(from, to string) []DiffLine {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	// lcs[i][j] holds the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: "delete", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: "insert", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: "delete", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: "insert", Text: b[j]})
+	}
+
+	return lines
+}
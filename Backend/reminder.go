@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// listRemindersHandler serves GET /api/reminders?status=&jobId=. Without status the
+// reminders waiting for the user are listed, status=all lists every reminder.
+func listRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	statuses := []models.ReminderStatus{models.ReminderStatusDue}
+	switch status := params.Get("status"); status {
+	case "":
+	case "all":
+		statuses = nil
+	default:
+		statuses = nil
+		for _, value := range strings.Split(status, ",") {
+			s := models.ReminderStatus(strings.TrimSpace(value))
+			if !s.Valid() {
+				http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+				return
+			}
+			statuses = append(statuses, s)
+		}
+	}
+
+	var jobID int
+	if v := params.Get("jobId"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid jobId parameter", http.StatusBadRequest)
+			return
+		}
+		jobID = id
+	}
+
+	reminders, err := sharedDB.GetReminders(statuses, jobID)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reminders)
+}
+
+// reminderIDFromPath extracts the reminder ID from /api/reminders/{id}{suffix}
+func reminderIDFromPath(path, suffix string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/reminders/"), suffix))
+}
+
+// snoozeReminderHandler serves POST /api/reminders/{id}/snooze with {"hours"} or {"until"}
+func snoozeReminderHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := reminderIDFromPath(r.URL.Path, "/snooze")
+	if err != nil {
+		http.Error(w, "Invalid reminder ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody struct {
+		Hours int        `json:"hours"`
+		Until *time.Time `json:"until"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	var until time.Time
+	switch {
+	case requestBody.Until != nil:
+		until = *requestBody.Until
+	case requestBody.Hours > 0:
+		until = time.Now().Add(time.Duration(requestBody.Hours) * time.Hour)
+	default:
+		http.Error(w, "hours or until is required", http.StatusBadRequest)
+		return
+	}
+	if !until.After(time.Now()) {
+		http.Error(w, "Snooze must end in the future", http.StatusBadRequest)
+		return
+	}
+
+	if !updateReminder(w, id, sharedDB.SnoozeReminder(id, until)) {
+		return
+	}
+	writeReminder(w, id)
+}
+
+// dismissReminderHandler serves POST /api/reminders/{id}/dismiss
+func dismissReminderHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := reminderIDFromPath(r.URL.Path, "/dismiss")
+	if err != nil {
+		http.Error(w, "Invalid reminder ID", http.StatusBadRequest)
+		return
+	}
+
+	if !updateReminder(w, id, sharedDB.DismissReminder(id)) {
+		return
+	}
+	writeReminder(w, id)
+}
+
+// updateReminder writes the error response of a reminder update, if any
+func updateReminder(w http.ResponseWriter, id int, err error) bool {
+	switch err {
+	case nil:
+		return true
+	case sharedDB.ErrNotFound:
+		http.Error(w, "Reminder not found", http.StatusNotFound)
+	case sharedDB.ErrReminderClosed:
+		http.Error(w, "Reminder is dismissed or expired", http.StatusConflict)
+	default:
+		http.Error(w, "DB update error", http.StatusInternalServerError)
+	}
+	return false
+}
+
+func writeReminder(w http.ResponseWriter, id int) {
+	reminder, err := sharedDB.GetReminderByID(id)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reminder)
+}
+
+// validateReminderRule checks a rule from a request body
+func validateReminderRule(w http.ResponseWriter, rule *models.ReminderRule) bool {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return false
+	}
+	if !rule.Kind.Valid() {
+		http.Error(w, "Invalid kind, expected status_stale or interview_upcoming", http.StatusBadRequest)
+		return false
+	}
+	if rule.Kind == models.ReminderRuleStatusStale && !rule.Status.Valid() {
+		http.Error(w, "status_stale rules need a valid status", http.StatusBadRequest)
+		return false
+	}
+	if rule.Kind != models.ReminderRuleStatusStale {
+		rule.Status = ""
+	}
+	if rule.DelayHours <= 0 {
+		http.Error(w, "delayHours must be positive", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func listReminderRulesHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	rules, err := sharedDB.GetReminderRules(false)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+func createReminderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	// Rules are enabled unless the request says otherwise
+	rule := models.ReminderRule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if !validateReminderRule(w, &rule) {
+		return
+	}
+
+	id, err := sharedDB.InsertReminderRule(rule)
+	if err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+
+	created, err := sharedDB.GetReminderRuleByID(int(id))
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// reminderRuleHandler serves PUT and DELETE /api/reminder-rules/{id}
+func reminderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "PUT, DELETE, OPTIONS")
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/reminder-rules/"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var rule models.ReminderRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if !validateReminderRule(w, &rule) {
+			return
+		}
+		rule.Id = id
+
+		if err := sharedDB.UpdateReminderRule(rule); err == sharedDB.ErrNotFound {
+			http.Error(w, "Rule not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB update error", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := sharedDB.GetReminderRuleByID(id)
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if err := sharedDB.DeleteReminderRule(id); err == sharedDB.ErrNotFound {
+			http.Error(w, "Rule not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/skills"
+)
+
+// Sizes of the top skills list
+const (
+	defaultTopSkills = 20
+	maxTopSkills     = 100
+)
+
+// skillName returns the name a skill is indexed under, the canonical taxonomy name
+// for aliases such as k8s
+func skillName(name string) string {
+	name = strings.TrimSpace(name)
+	if _, canonical, _, ok := skills.Lookup(name); ok {
+		return canonical
+	}
+	return name
+}
+
+// topSkillsHandler serves GET /api/skills/top?status=&kind=&limit=, the requirements
+// asked for by the most jobs. status is a comma separated list, open by default, or
+// "all" for every job.
+func topSkillsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	statuses := []models.JobStatus{models.JobStatusOpen}
+	if value := params.Get("status"); value == "all" {
+		statuses = nil
+	} else if value != "" {
+		statuses = nil
+		for _, s := range strings.Split(value, ",") {
+			status := models.JobStatus(strings.TrimSpace(s))
+			if !status.Valid() {
+				http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+				return
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	kind := models.SkillKind(params.Get("kind"))
+	if kind != "" && !kind.Valid() {
+		http.Error(w, "Invalid kind parameter", http.StatusBadRequest)
+		return
+	}
+	limit := defaultTopSkills
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxTopSkills {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	demand, err := sharedDB.GetSkillDemand(statuses, kind, limit)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(demand)
+}
+
+// jobSkillsHandler serves GET /api/jobs/{id}/skills, the requirements read from the
+// description of a job
+func jobSkillsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID, err := jobIDFromPath(r.URL.Path, "/skills")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := sharedDB.GetJobByID(jobID); err == sharedDB.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	jobSkills, err := sharedDB.GetJobSkills(jobID)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobSkills)
+}
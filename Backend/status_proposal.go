@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+)
+
+// listStatusProposalsHandler serves GET /api/status-proposals?state=, the status changes
+// proposed by emails about applications. Without state the pending ones are listed.
+func listStatusProposalsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	state := models.StatusProposalPending
+	if value := r.URL.Query().Get("state"); value != "" {
+		state = models.StatusProposalState(value)
+		if !state.Valid() {
+			http.Error(w, "Invalid state parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	proposals, err := sharedDB.GetStatusProposals(state)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(proposals)
+}
+
+// statusProposalIDFromPath extracts the proposal ID from /api/status-proposals/{id}{suffix}
+func statusProposalIDFromPath(path, suffix string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/status-proposals/"), suffix))
+}
+
+// acceptStatusProposalHandler serves POST /api/status-proposals/{id}/accept. The status
+// change is requested like one made by hand, from the job's current status.
+func acceptStatusProposalHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := statusProposalIDFromPath(r.URL.Path, "/accept")
+	if err != nil {
+		http.Error(w, "Invalid proposal ID", http.StatusBadRequest)
+		return
+	}
+	proposal, ok := pendingStatusProposal(w, id)
+	if !ok {
+		return
+	}
+	job, err := sharedDB.GetJobByID(proposal.JobId)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	if !job.Status.CanTransitionTo(proposal.To) {
+		http.Error(w, "Cannot move job from "+string(job.Status)+" to "+string(proposal.To), http.StatusConflict)
+		return
+	}
+
+	if err := sharedNats.PublishJobStatusUpdateRequest(proposal.JobId, proposal.To, "From email: "+proposal.Subject); err != nil {
+		http.Error(w, "Failed to publish job status update request: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !decideStatusProposal(w, id, models.StatusProposalAccepted) {
+		log.Printf("Status %s requested for job %d but proposal %d was not marked accepted", proposal.To, proposal.JobId, id)
+		return
+	}
+	writeStatusProposal(w, id)
+}
+
+// dismissStatusProposalHandler serves POST /api/status-proposals/{id}/dismiss
+func dismissStatusProposalHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := statusProposalIDFromPath(r.URL.Path, "/dismiss")
+	if err != nil {
+		http.Error(w, "Invalid proposal ID", http.StatusBadRequest)
+		return
+	}
+	if _, ok := pendingStatusProposal(w, id); !ok {
+		return
+	}
+	if !decideStatusProposal(w, id, models.StatusProposalDismissed) {
+		return
+	}
+	writeStatusProposal(w, id)
+}
+
+// pendingStatusProposal loads a proposal, writing the error response if it is missing or decided
+func pendingStatusProposal(w http.ResponseWriter, id int) (*models.StatusProposal, bool) {
+	proposal, err := sharedDB.GetStatusProposalByID(id)
+	if err == sharedDB.ErrNotFound {
+		http.Error(w, "Proposal not found", http.StatusNotFound)
+		return nil, false
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if proposal.State != models.StatusProposalPending {
+		http.Error(w, "Proposal is already "+string(proposal.State), http.StatusConflict)
+		return nil, false
+	}
+	return proposal, true
+}
+
+// decideStatusProposal writes the error response of a decision, if any. A proposal
+// decided since it was loaded is a conflict.
+func decideStatusProposal(w http.ResponseWriter, id int, state models.StatusProposalState) bool {
+	switch err := sharedDB.DecideStatusProposal(id, state); err {
+	case nil:
+		return true
+	case sharedDB.ErrNotFound:
+		http.Error(w, "Proposal is no longer pending", http.StatusConflict)
+	default:
+		http.Error(w, "DB update error", http.StatusInternalServerError)
+	}
+	return false
+}
+
+func writeStatusProposal(w http.ResponseWriter, id int) {
+	proposal, err := sharedDB.GetStatusProposalByID(id)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(proposal)
+}
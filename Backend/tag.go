@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// maxBulkTagJobs bounds the selection of a bulk tag request
+const maxBulkTagJobs = 500
+
+// normalizeTags normalizes tag names from a request and drops duplicates
+func normalizeTags(names []string) ([]string, error) {
+	tags := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tag, ok := models.NormalizeTag(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q, expected up to %d letters, digits, dashes, underscores or dots", name, models.MaxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// listTagsHandler serves GET /api/tags
+func listTagsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tags, err := sharedDB.GetTags()
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+// jobTagsHandler serves GET, POST {"tags"} and DELETE ?tag= on /api/jobs/{id}/tags.
+// GET and POST answer with the tags of the job.
+func jobTagsHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "GET, POST, DELETE, OPTIONS")
+		return
+	}
+
+	jobID, err := jobIDFromPath(r.URL.Path, "/tags")
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var requestBody struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		tags, err := normalizeTags(requestBody.Tags)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if _, err := sharedDB.GetJobByID(jobID); err == sharedDB.ErrNotFound {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		if err := sharedDB.AddJobTags([]int{jobID}, tags); err != nil {
+			http.Error(w, "DB insert error", http.StatusInternalServerError)
+			return
+		}
+	case http.MethodDelete:
+		tags, err := normalizeTags([]string{r.URL.Query().Get("tag")})
+		if err != nil {
+			http.Error(w, "Missing or invalid tag parameter", http.StatusBadRequest)
+			return
+		}
+		if err := sharedDB.RemoveJobTags([]int{jobID}, tags); err != nil {
+			http.Error(w, "DB delete error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tags, err := sharedDB.GetJobTags(jobID)
+	if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+// bulkTagHandler serves POST /api/tags/bulk with {"jobIds", "add", "remove"}, tagging a
+// selection of jobs at once. Unknown job IDs are skipped.
+func bulkTagHandler(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	if r.Method == http.MethodOptions {
+		handleCORS(w, "POST, OPTIONS")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var requestBody struct {
+		JobIds []int    `json:"jobIds"`
+		Add    []string `json:"add"`
+		Remove []string `json:"remove"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(requestBody.JobIds) == 0 {
+		http.Error(w, "jobIds is required", http.StatusBadRequest)
+		return
+	}
+	if len(requestBody.JobIds) > maxBulkTagJobs {
+		http.Error(w, fmt.Sprintf("At most %d jobs can be tagged at once", maxBulkTagJobs), http.StatusBadRequest)
+		return
+	}
+	add, err := normalizeTags(requestBody.Add)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	remove, err := normalizeTags(requestBody.Remove)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		http.Error(w, "add or remove is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := sharedDB.AddJobTags(requestBody.JobIds, add); err != nil {
+		http.Error(w, "DB insert error", http.StatusInternalServerError)
+		return
+	}
+	if err := sharedDB.RemoveJobTags(requestBody.JobIds, remove); err != nil {
+		http.Error(w, "DB delete error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// apiTokenPrefix starts every token secret, so leaked tokens are easy to recognize
+const apiTokenPrefix = "hp_"
+
+// hashAPIToken returns the stored form of a token secret
+func hashAPIToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// requireAPIToken checks the personal API token of a request, sent as
+// "Authorization: Bearer <token>". It answers 401 and returns false when the token
+// is missing or unknown.
+func requireAPIToken(w http.ResponseWriter, r *http.Request) bool {
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !strings.HasPrefix(strings.TrimSpace(secret), apiTokenPrefix) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "API token required", http.StatusUnauthorized)
+		return false
+	}
+
+	token, err := sharedDB.UseAPIToken(hashAPIToken(strings.TrimSpace(secret)))
+	if err == sharedDB.ErrNotFound {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Invalid API token", http.StatusUnauthorized)
+		return false
+	} else if err != nil {
+		http.Error(w, "DB query error", http.StatusInternalServerError)
+		return false
+	}
+	log.Printf("Request to %s with API token %s", r.URL.Path, token.Prefix)
+	return true
+}
+
+// uiOrigin is the HirePilot UI, the only web page that may manage API tokens. Tokens
+// let other callers in, so no other page may mint one through the user's browser.
+var uiOrigin = strings.TrimRight(envOrDefault("UI_ORIGIN", "http://localhost"), "/")
+
+func envOrDefault(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// setTokenCORSHeaders allows the UI origin only, instead of the wildcard of setCORSHeaders.
+// It answers 403 and returns false for requests from any other web page.
+func setTokenCORSHeaders(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// Not sent by a browser on behalf of a web page
+		return true
+	}
+	if origin != uiOrigin {
+		http.Error(w, "API tokens can only be managed from the HirePilot UI", http.StatusForbidden)
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", uiOrigin)
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	return true
+}
+
+// apiTokensHandler serves GET and POST {"name"} on /api/tokens. The secret of a new
+// token is only part of the POST answer; it cannot be read back.
+func apiTokensHandler(w http.ResponseWriter, r *http.Request) {
+	if !setTokenCORSHeaders(w, r) {
+		return
+	}
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		tokens, err := sharedDB.GetAPITokens()
+		if err != nil {
+			http.Error(w, "DB query error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokens)
+	case http.MethodPost:
+		var requestBody struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		name := strings.TrimSpace(requestBody.Name)
+		if name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		random := make([]byte, 24)
+		if _, err := rand.Read(random); err != nil {
+			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+			return
+		}
+		secret := apiTokenPrefix + hex.EncodeToString(random)
+		token, err := sharedDB.InsertAPIToken(name, secret[:len(apiTokenPrefix)+6], hashAPIToken(secret))
+		if err != nil {
+			http.Error(w, "DB insert error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(struct {
+			models.APIToken
+			Secret string `json:"secret"`
+		}{*token, secret})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// apiTokenHandler serves DELETE /api/tokens/{id}, revoking the token
+func apiTokenHandler(w http.ResponseWriter, r *http.Request) {
+	if !setTokenCORSHeaders(w, r) {
+		return
+	}
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "DELETE, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Only DELETE allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/tokens/"))
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+	if err := sharedDB.DeleteAPIToken(id); err == sharedDB.ErrNotFound {
+		http.Error(w, "Token not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "DB delete error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
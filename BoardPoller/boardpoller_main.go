@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/boards"
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/worker"
+)
+
+// defaultInterval is how often the job boards are polled
+const defaultInterval = time.Hour
+
+// pollTimeout bounds the requests of one board
+const pollTimeout = 2 * time.Minute
+
+// BoardPoller pulls the postings of the enabled job boards from their ATS APIs and
+// requests a job for every new posting that passes the filters of its board. The
+// provider base URLs can be pointed elsewhere with GREENHOUSE_BASE_URL,
+// LEVER_BASE_URL, ASHBY_BASE_URL and WORKABLE_BASE_URL.
+func main() {
+	log.Println("Starting BoardPoller...")
+
+	// Initialize shared database
+	sharedDB.InitDB()
+
+	// Initialize shared NATS JetStream
+	sharedNats.InitJetStream()
+	defer sharedNats.Close()
+
+	baseURLs := map[models.BoardProvider]string{}
+	for _, provider := range models.BoardProviders {
+		baseURLs[provider] = os.Getenv(strings.ToUpper(string(provider)) + "_BASE_URL")
+	}
+	sources := boards.NewSources(&http.Client{Timeout: pollTimeout}, baseURLs)
+
+	worker.Every("the board polls", "BOARD_POLL_INTERVAL", defaultInterval, func() { run(sources) })
+}
+
+// run polls every enabled board once; failures are recorded on the board and retried
+// on the next run
+func run(sources map[models.BoardProvider]boards.JobSource) {
+	jobBoards, err := sharedDB.GetJobBoards(true)
+	if err != nil {
+		log.Printf("Failed to load job boards: %v", err)
+		return
+	}
+
+	for _, board := range jobBoards {
+		created, err := poll(sources[board.Provider], board)
+		pollErr := ""
+		if err != nil {
+			log.Printf("Failed to poll %s board %s: %v", board.Provider, board.Board, err)
+			pollErr = err.Error()
+		}
+		if created > 0 {
+			log.Printf("%s board %s published %d new postings", board.Provider, board.Board, created)
+		}
+		if err := sharedDB.RecordJobBoardPoll(board.Id, created, pollErr); err != nil {
+			log.Printf("Warning: failed to record poll of job board %d: %v", board.Id, err)
+		}
+	}
+}
+
+// poll publishes a job creation request for each new posting of a board that passes
+// its filters. A posting is marked seen only after its request is out, so a failed
+// publish is retried on the next run; filtered postings are not marked, so they are
+// picked up if the filters change.
+func poll(source boards.JobSource, board models.JobBoard) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pollTimeout)
+	defer cancel()
+
+	postings, err := source.Postings(ctx, board.Board)
+	if err != nil {
+		return 0, err
+	}
+	seen, err := sharedDB.GetSeenBoardPostings(board.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, p := range postings {
+		if p.ID == "" || seen[p.ID] || !board.Matches(p.JobPosting) {
+			continue
+		}
+		if p.Company == "" {
+			p.Company = board.Company
+		}
+		if p.Company == "" {
+			p.Company = board.Board
+		}
+		if err := sharedNats.PublishJobCreationRequest(p.JobPosting); err != nil {
+			return created, err
+		}
+		if err := sharedDB.MarkBoardPostingSeen(board.Id, p.ID); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
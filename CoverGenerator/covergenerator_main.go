@@ -2,14 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 
 	sharedAI "github.com/hirepilot/shared/ai"
 	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/experiment"
 	"github.com/hirepilot/shared/models"
 	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/render"
 )
 
 type JobMessage struct {
@@ -50,21 +53,18 @@ func handleJobCreated(data []byte) error {
 	}
 	log.Printf("Received job: %+v", jobMsg.Data.Id)
 
-	// Get the default cover letter generation prompt from database
-	coverPrompt, err := sharedDB.GetDefaultCoverPrompt()
+	// Use the default cover letter prompt, or the variant assigned by an active experiment
+	coverPrompt, assignment, err := experiment.ResolvePrompt(models.PromptKindCover, jobMsg.Data.Id)
 	if err == sharedDB.ErrNotFound {
 		log.Printf("No default cover letter generation prompt found")
 		return fmt.Errorf("no default cover letter generation prompt found")
 	} else if err != nil {
-		log.Printf("Failed to get default cover prompt: %v", err)
+		log.Printf("Failed to resolve cover prompt: %v", err)
 		return err
 	}
 
 	// Use shared AI client to generate cover letter
-	promptText := coverPrompt.Prompt + "\n\n" +
-		"Title: " + jobMsg.Data.Title + "\n" +
-		"Company: " + jobMsg.Data.Company + "\n" +
-		"Description: " + jobMsg.Data.Description + "\n"
+	promptText := render.CoverForJob(coverPrompt.Prompt, &jobMsg.Data, hiringManager(jobMsg.Data.Id))
 
 	aiClient, err := sharedAI.DefaultClient()
 	if err != nil {
@@ -80,13 +80,21 @@ func handleJobCreated(data []byte) error {
 
 	log.Printf("Generated cover letter for Job : %v", jobMsg.Data.Id)
 
+	if assignment != nil {
+		if err := sharedDB.RecordExperimentUsage(assignment.Id, sharedAI.EstimateTokens(promptText), sharedAI.EstimateTokens(coverLetter)); err != nil {
+			log.Printf("Failed to record experiment usage for job %d: %v", jobMsg.Data.Id, err)
+		}
+	}
+
 	// after generation, update the job with the generated cover letter using shared DB
-	err = sharedDB.UpdateJobCoverLetter(jobMsg.Data.Id, coverLetter)
+	versionID := promptVersionID(coverPrompt)
+	err = sharedDB.UpdateJobCoverLetter(jobMsg.Data.Id, coverLetter, versionID)
 	if err != nil {
 		log.Printf("Failed to update job with generated cover letter: %v", err)
 		return err
 	}
 	log.Printf("Job %d updated with generated cover letter", jobMsg.Data.Id)
+	recordActivity(jobMsg.Data.Id, versionID)
 
 	// Update job data with generated cover letter and publish message
 	jobMsg.Data.CoverLetter = coverLetter
@@ -138,20 +146,26 @@ func handleCoverGenerationRequest(data []byte) error {
 	}
 
 	var promptText string
+	var versionID *int
 	if coverReqMsg.Data.PromptID != nil {
 		// Fetch specific prompt
-		prompt, err := sharedDB.GetPromptByID(*coverReqMsg.Data.PromptID)
+		prompt, err := sharedDB.GetPromptForKind(*coverReqMsg.Data.PromptID, models.PromptKindCover)
 		if err == sharedDB.ErrNotFound {
 			log.Printf("Prompt not found for ID %d", *coverReqMsg.Data.PromptID)
 			return err
+		} else if errors.Is(err, sharedDB.ErrWrongPromptKind) {
+			// Redelivery cannot fix a prompt of the wrong kind, so acknowledge the request
+			log.Printf("Ignoring request for job %s: %v", coverReqMsg.Data.JobID, err)
+			return nil
 		} else if err != nil {
 			log.Printf("Failed to get prompt by ID %d: %v", *coverReqMsg.Data.PromptID, err)
 			return err
 		}
 		promptText = prompt.Prompt
+		versionID = promptVersionID(prompt)
 	} else {
-		// Get default cover letter generation prompt
-		coverPrompt, err := sharedDB.GetDefaultCoverPrompt()
+		// Get the cover prompt of one of the job's tags, or the default cover letter generation prompt
+		coverPrompt, err := sharedDB.GetJobPrompt(models.PromptKindCover, job.Id)
 		if err == sharedDB.ErrNotFound {
 			log.Printf("No default cover letter generation prompt found")
 			promptText = "Generate a professional cover letter for the following job:"
@@ -160,14 +174,12 @@ func handleCoverGenerationRequest(data []byte) error {
 			return err
 		} else {
 			promptText = coverPrompt.Prompt
+			versionID = promptVersionID(coverPrompt)
 		}
 	}
 
 	// Generate cover letter using AI
-	fullPrompt := promptText + "\n\n" +
-		"Title: " + job.Title + "\n" +
-		"Company: " + job.Company + "\n" +
-		"Description: " + job.Description + "\n"
+	fullPrompt := render.CoverForJob(promptText, job, hiringManager(job.Id))
 
 	aiClient, err := sharedAI.DefaultClient()
 	if err != nil {
@@ -190,12 +202,13 @@ func handleCoverGenerationRequest(data []byte) error {
 		return err
 	}
 
-	err = sharedDB.UpdateJobCoverLetter(jobID, coverLetter)
+	err = sharedDB.UpdateJobCoverLetter(jobID, coverLetter, versionID)
 	if err != nil {
 		log.Printf("Failed to update job with generated cover letter: %v", err)
 		return err
 	}
 	log.Printf("Job %s updated with generated cover letter", coverReqMsg.Data.JobID)
+	recordActivity(jobID, versionID)
 
 	// Publish cover letter generated message using shared NATS
 	job.CoverLetter = coverLetter
@@ -222,3 +235,37 @@ func handleCoverGenerationRequest(data []byte) error {
 
 	return nil
 }
+
+// promptVersionID resolves the version of a prompt for traceability; failures are logged, not fatal
+func promptVersionID(prompt *models.Prompt) *int {
+	versionID, err := sharedDB.GetPromptVersionID(prompt)
+	if err != nil {
+		log.Printf("Warning: Could not resolve version of prompt %d: %v", prompt.Id, err)
+		return nil
+	}
+	return versionID
+}
+
+// hiringManager returns the hiring manager linked to a job, or nil to use a generic greeting
+func hiringManager(jobID int) *models.Contact {
+	contact, err := sharedDB.GetJobHiringManager(jobID)
+	if err != nil {
+		if err != sharedDB.ErrNotFound {
+			log.Printf("Warning: Could not look up the hiring manager of job %d: %v", jobID, err)
+		}
+		return nil
+	}
+	return contact
+}
+
+// recordActivity adds the generation to the job timeline; failures are logged, not fatal
+func recordActivity(jobID int, versionID *int) {
+	if err := sharedDB.RecordJobActivity(models.JobActivity{
+		JobId:   jobID,
+		Kind:    models.JobActivityCoverGenerated,
+		Summary: "Cover letter generated",
+		RefId:   versionID,
+	}); err != nil {
+		log.Printf("Warning: Failed to record cover letter activity for job %d: %v", jobID, err)
+	}
+}
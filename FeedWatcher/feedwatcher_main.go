@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/feeds"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/worker"
+)
+
+// defaultInterval is how often the feeds are polled
+const defaultInterval = 30 * time.Minute
+
+// pollTimeout bounds the requests of one feed, including the posting pages it links to
+const pollTimeout = 5 * time.Minute
+
+// client only reaches public addresses, both feed URLs and the item links come from
+// the feeds' owners
+var client = extract.NewClient(30 * time.Second)
+
+// FeedWatcher polls the enabled RSS and Atom job feeds and requests a job for every
+// item it has not seen before. Feeds are requested conditionally, so unchanged feeds
+// cost a 304 answer.
+func main() {
+	log.Println("Starting FeedWatcher...")
+
+	// Initialize shared database
+	sharedDB.InitDB()
+
+	// Initialize shared NATS JetStream
+	sharedNats.InitJetStream()
+	defer sharedNats.Close()
+
+	worker.Every("the feed polls", "FEED_POLL_INTERVAL", defaultInterval, run)
+}
+
+// run polls every enabled feed once; failures are recorded on the feed and retried on
+// the next run
+func run() {
+	jobFeeds, err := sharedDB.GetJobFeeds(true)
+	if err != nil {
+		log.Printf("Failed to load job feeds: %v", err)
+		return
+	}
+
+	for _, feed := range jobFeeds {
+		ctx, cancel := context.WithTimeout(context.Background(), pollTimeout)
+		result, created, err := poll(ctx, feed)
+		cancel()
+
+		pollErr := ""
+		if err != nil {
+			log.Printf("Failed to poll feed %d (%s): %v", feed.Id, feed.URL, err)
+			pollErr = err.Error()
+			// Keep the old validators, so the items not published yet are read again
+			result.ETag, result.LastModified = feed.ETag, feed.LastModified
+		}
+		if created > 0 {
+			log.Printf("Feed %d (%s) published %d new items", feed.Id, feed.URL, created)
+		}
+		if err := sharedDB.RecordJobFeedPoll(feed.Id, result.ETag, result.LastModified, created, pollErr); err != nil {
+			log.Printf("Warning: failed to record poll of job feed %d: %v", feed.Id, err)
+		}
+	}
+}
+
+// poll publishes a job creation request for each unseen item of a feed. An item is
+// marked seen only after its request is out, so a failed publish is retried on the next
+// run. Items that name no company are marked seen without a job.
+func poll(ctx context.Context, feed models.JobFeed) (feeds.Result, int, error) {
+	result, err := feeds.Fetch(ctx, client, feed.URL, feed.ETag, feed.LastModified)
+	if err != nil || result.NotModified {
+		return result, 0, err
+	}
+	seen, err := sharedDB.GetSeenFeedItems(feed.Id)
+	if err != nil {
+		return result, 0, err
+	}
+
+	created := 0
+	for _, item := range result.Items {
+		if seen[item.GUID] {
+			continue
+		}
+
+		job := item.Posting(feed.Company)
+		if feed.FetchDescription && job.Link != "" {
+			if page, err := readPosting(ctx, client, feed, job); err != nil {
+				log.Printf("Warning: failed to read the posting of feed item %s: %v", item.GUID, err)
+			} else {
+				job = page
+			}
+		}
+
+		if job.Title == "" || job.Company == "" {
+			log.Printf("Skipping feed item %s of feed %d: no title or company found", item.GUID, feed.Id)
+		} else if err := sharedNats.PublishJobCreationRequest(job); err != nil {
+			return result, created, err
+		} else {
+			created++
+		}
+		if err := sharedDB.MarkFeedItemSeen(feed.Id, item.GUID); err != nil {
+			return result, created, err
+		}
+	}
+	return result, created, nil
+}
+
+// readPosting completes the job of a feed item from the posting page it links to.
+// The posting page is more complete than the item, the feed's company wins.
+func readPosting(ctx context.Context, client *http.Client, feed models.JobFeed, job models.JobPosting) (models.JobPosting, error) {
+	page, err := extract.Fetch(ctx, client, job.Link)
+	if err != nil {
+		return job, err
+	}
+	job = extract.Merge(page, job)
+	if feed.Company != "" {
+		job.Company = feed.Company
+	}
+	return job, nil
+}
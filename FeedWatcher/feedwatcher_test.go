@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/feeds"
+	"github.com/hirepilot/shared/models"
+)
+
+// newFeedServer serves an RSS feed whose item links back to the same loopback server
+func newFeedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Jobs</title>
+<item><guid>job-1</guid><title>Go Developer</title><link>%s/jobs/1</link></item>
+</channel></rss>`, server.URL)
+	})
+	mux.HandleFunc("/jobs/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Go Developer at Internal</title></head><body><h1>Go Developer</h1></body></html>`))
+	})
+	return server
+}
+
+func TestFeedOnLoopbackIsRejected(t *testing.T) {
+	server := newFeedServer(t)
+
+	_, err := feeds.Fetch(context.Background(), client, server.URL+"/feed.xml", "", "")
+	if !errors.Is(err, extract.ErrBlockedAddress) {
+		t.Errorf("feeds.Fetch error = %v, want %v", err, extract.ErrBlockedAddress)
+	}
+}
+
+func TestItemLinkOnLoopbackIsRejected(t *testing.T) {
+	server := newFeedServer(t)
+	feed := models.JobFeed{URL: server.URL + "/feed.xml", Company: "Acme", FetchDescription: true}
+
+	// The feed itself is read with the test server's client, only the item link is checked
+	result, err := feeds.Fetch(context.Background(), server.Client(), feed.URL, "", "")
+	if err != nil {
+		t.Fatalf("feeds.Fetch: %v", err)
+	}
+	if len(result.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(result.Items))
+	}
+
+	job := result.Items[0].Posting(feed.Company)
+	got, err := readPosting(context.Background(), client, feed, job)
+	if !errors.Is(err, extract.ErrBlockedAddress) {
+		t.Errorf("readPosting error = %v, want %v", err, extract.ErrBlockedAddress)
+	}
+	if got.Title != job.Title || got.Company != job.Company || got.Description != job.Description {
+		t.Errorf("readPosting changed the job to %+v", got)
+	}
+}
@@ -6,9 +6,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
+	sharedDB "github.com/hirepilot/shared/db"
 	"github.com/hirepilot/shared/models"
 	sharedNats "github.com/hirepilot/shared/nats"
 	"github.com/jung-kurt/gofpdf"
@@ -23,6 +23,9 @@ func main() {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
 
+	// Initialize shared database for the job timeline
+	sharedDB.InitDB()
+
 	// Initialize shared NATS JetStream
 	js := sharedNats.InitJetStream()
 	if js == nil {
@@ -86,6 +89,7 @@ func main() {
 		}
 
 		log.Printf("CV PDF generated successfully: %s", pdfPath)
+		recordPDFActivity(job.Id, "CV", pdfPath)
 		return nil
 	})
 
@@ -424,15 +428,22 @@ func handleCoverLetterGenerated(data []byte, outputDir string) error {
 	}
 
 	log.Printf("Cover letter PDF generated successfully: %s", pdfPath)
+	recordPDFActivity(job.Id, "Cover letter", pdfPath)
 	return nil
 }
 
-func cleanTextForPDF(text string) string {
-	// First, let's log the problematic text for debugging
-	if strings.Contains(text, "PandaDoc") {
-		log.Printf("Debug - Original text with PandaDoc: %q", text)
+// recordPDFActivity adds a created PDF to the job timeline; failures are logged, not fatal
+func recordPDFActivity(jobID int, document, pdfPath string) {
+	if err := sharedDB.RecordJobActivity(models.JobActivity{
+		JobId:   jobID,
+		Kind:    models.JobActivityPDFCreated,
+		Summary: fmt.Sprintf("%s PDF created: %s", document, filepath.Base(pdfPath)),
+	}); err != nil {
+		log.Printf("Warning: Failed to record PDF activity for job %d: %v", jobID, err)
 	}
-	
+}
+
+func cleanTextForPDF(text string) string {
 	// Convert text to ASCII-safe characters for PDF compatibility
 	// This is more aggressive but ensures PDF rendering works correctly
 	replacer := strings.NewReplacer(
@@ -451,49 +462,11 @@ func cleanTextForPDF(text string) string {
 		"\u00A9", "(C)", // U+00A9 COPYRIGHT SIGN
 		// Non-breaking space
 		"\u00A0", " ", // U+00A0 NON-BREAKING SPACE
-		// Fix malformed UTF-8 sequences - using safe string literals
-		"\xc3\xa2\xe2\x82\xac\xc2\xa2", "•", // Malformed bullet point
-		"\xc3\xa2\xe2\x82\xac\xe2\x80\x9d", "'", // Malformed right single quote (PandaDoc's issue)
-		"\xc3\xa2\xe2\x82\xac\xc5\x93", `"`, // Malformed left double quote
-		"\xc3\xa2\xe2\x82\xac\xc2\x9d", `"`, // Malformed right double quote
-		"\xc3\xa2\xe2\x82\xac\xe2\x80\x9c", "-", // Malformed em dash
-		"\xc3\xa2\xe2\x82\xac\xc2\xa6", "...", // Malformed ellipsis
-		"\xc3\xa2\xe2\x82\xac\xcb\x9c", "'", // Malformed left single quote
-		// Additional hex-encoded malformed sequences for safety
-		"\xe2\x80\xa2", "•", // Another hex-encoded malformed bullet
-		"\xe2\x80\x99", "'", // Another hex-encoded malformed apostrophe
-		"\xe2\x80\x9c", `"`, // Another hex-encoded malformed left quote
-		"\xe2\x80\x9d", `"`, // Another hex-encoded malformed right quote
-		"\xe2\x80\x93", "-", // Another hex-encoded malformed dash
-		"\xe2\x97\x8f", "•", // Another hex-encoded bullet variant
+		"\u25CF", "•", // U+25CF BLACK CIRCLE, a bullet variant
 	)
 
 	result := replacer.Replace(text)
 	
-	// Use regex to catch any remaining malformed UTF-8 sequences
-	// This is more aggressive and should catch patterns we might have missed
-	
-	// First, try to fix the most common malformed sequences using byte-level replacement
-	result = strings.ReplaceAll(result, string([]byte{0xc3, 0xa2, 0xe2, 0x82, 0xac, 0xc2, 0xa2}), "•") // Malformed bullet
-	result = strings.ReplaceAll(result, string([]byte{0xc3, 0xa2, 0xe2, 0x82, 0xac, 0xe2, 0x80, 0x9d}), "'") // Malformed apostrophe
-	
-	// Then use regex for any remaining patterns
-	regexReplacements := map[string]string{
-		`â€¢`: "•",  // Malformed bullet
-		`â€™`: "'",  // Malformed apostrophe
-		`â€œ`: `"`,  // Malformed left quote
-		`â€`: `"`,   // Malformed right quote
-		`â€"`: "-",  // Malformed dash
-		`â—•`: "•",  // Another malformed bullet
-		`â€¦`: "...", // Malformed ellipsis
-		`â€˜`: "'",  // Malformed left single quote
-	}
-	
-	for pattern, replacement := range regexReplacements {
-		re := regexp.MustCompile(regexp.QuoteMeta(pattern))
-		result = re.ReplaceAllString(result, replacement)
-	}
-	
 	// Additional aggressive cleaning for any remaining non-ASCII characters
 	// that might cause PDF rendering issues
 	result = strings.Map(func(r rune) rune {
@@ -525,11 +498,6 @@ func cleanTextForPDF(text string) string {
 		return ' '
 	}, result)
 	
-	// Log the result for debugging
-	if strings.Contains(text, "PandaDoc") {
-		log.Printf("Debug - Cleaned text with PandaDoc: %q", result)
-	}
-	
 	return result
 }
 
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/worker"
+)
+
+// defaultExpiryInterval is how often the job expiry policies are applied
+const defaultExpiryInterval = time.Hour
+
+// startExpiry applies the enabled job expiry policies now and then every
+// JOB_EXPIRY_INTERVAL in the background
+func startExpiry() {
+	go worker.Every("the job expiry policies", "JOB_EXPIRY_INTERVAL", defaultExpiryInterval, func() {
+		applyExpiryPolicies(time.Now())
+	})
+}
+
+// applyExpiryPolicies requests the status changes of the enabled policies. The changes go
+// through the status update requests like any other, so they are validated and recorded
+// in the history by handleJobStatusUpdate. Failures are retried on the next run.
+func applyExpiryPolicies(now time.Time) {
+	policies, err := sharedDB.GetExpiryPolicies(true)
+	if err != nil {
+		log.Printf("Failed to load job expiry policies: %v", err)
+		return
+	}
+
+	for _, policy := range policies {
+		candidates, err := sharedDB.GetExpiryCandidates(policy, now)
+		if err != nil {
+			log.Printf("Failed to evaluate job expiry policy %s: %v", policy.Kind, err)
+			continue
+		}
+
+		requested := 0
+		for _, candidate := range candidates {
+			if err := sharedNats.PublishJobStatusUpdateRequest(candidate.JobId, candidate.To, policy.Note()); err != nil {
+				log.Printf("Failed to request %s of job %d: %v", candidate.To, candidate.JobId, err)
+				continue
+			}
+			requested++
+		}
+		if requested > 0 {
+			log.Printf("Job expiry policy %s requested %d status changes", policy.Kind, requested)
+		}
+	}
+}
@@ -4,12 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 
 	sharedDB "github.com/hirepilot/shared/db"
 	sharedNats "github.com/hirepilot/shared/nats"
 	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/normalize"
 )
 
+type JobCreationMessage struct {
+	Type string            `json:"type"`
+	Data models.JobPosting `json:"data"`
+}
+
 type JobStatusUpdateMessage struct {
 	Type string `json:"type"`
 	Data sharedNats.JobStatusUpdateRequest `json:"data"`
@@ -30,21 +37,14 @@ func main() {
 		log.Printf("Received job creation request")
 
 		// Parse the message
-		var message map[string]interface{}
+		var message JobCreationMessage
 		if err := json.Unmarshal(data, &message); err != nil {
 			log.Printf("Error unmarshaling message: %v", err)
 			return err
 		}
 
-		// Extract job data
-		jobData, ok := message["data"].(map[string]interface{})
-		if !ok {
-			log.Printf("Invalid job data format")
-			return fmt.Errorf("invalid job data format")
-		}
-
 		// Process the job creation request
-		if err := handleJobCreation(jobData); err != nil {
+		if err := handleJobCreation(message.Data); err != nil {
 			log.Printf("Error handling job creation: %v", err)
 			return err
 		}
@@ -82,69 +82,137 @@ func main() {
 	}
 
 	log.Println("Job Service subscribed to job creation and status update messages")
+
+	// Close stale jobs according to the expiry policies
+	startExpiry()
+
+	// Read the requirements of jobs stored without them
+	startSkillExtraction()
+
 	log.Println("Job Service is running. Press Ctrl+C to exit.")
 
 	// Keep the service running
 	select {}
 }
 
-func handleJobCreation(jobData map[string]interface{}) error {
-	// Extract job fields
-	title, _ := jobData["title"].(string)
-	company, _ := jobData["company"].(string)
-	link, _ := jobData["link"].(string)
-	description, _ := jobData["description"].(string)
+func handleJobCreation(posting models.JobPosting) error {
+	log.Printf("Processing job creation: %s at %s", posting.Title, posting.Company)
 
-	log.Printf("Processing job creation: %s at %s", title, company)
-
-	// Insert job into database using shared library
-	id, err := sharedDB.InsertJob(title, company, link, description)
+	// Insert the job unless its posting is already known, e.g. republished by the scraper
+	result, err := sharedDB.UpsertJob(posting)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("Job saved to database with ID: %d", id)
-
-	// Create complete job object for further processing
-	job := models.Job{
-		Id:          int(id),
-		Title:       title,
-		Company:     company,
-		Link:        link,
-		Status:      models.JobStatusOpen,
-		CvGenerated: false,
-		Cv:          "",
-		Description: description,
+	if !result.Created {
+		log.Printf("Job already known with ID: %d (description updated: %t)", result.ID, result.DescriptionUpdated)
+		if result.DescriptionUpdated {
+			if err := extractJobSkills(result.ID, result.Description); err != nil {
+				log.Printf("Warning: Failed to store the requirements of job %d: %v", result.ID, err)
+			}
+			if err := sharedNats.PublishJobUpdateMessage(strconv.Itoa(result.ID), map[string]interface{}{"description": result.Description}); err != nil {
+				log.Printf("Warning: Failed to publish job update message: %v", err)
+			}
+		}
+		if !result.CreationHandled {
+			// A redelivered request of a job saved without its generation requested,
+			// e.g. after a failed publish
+			log.Printf("Job %d was saved by an earlier delivery, finishing its creation", result.ID)
+			return requestGeneration(result.ID, posting)
+		}
+		if err := sharedNats.PublishJobAlreadyKnownMessage(sharedNats.JobAlreadyKnownMessage{
+			JobID:              result.ID,
+			Link:               posting.Link,
+			DescriptionUpdated: result.DescriptionUpdated,
+		}); err != nil {
+			log.Printf("Warning: Failed to publish already known message: %v", err)
+		}
+		// Known postings never trigger generation again
+		return nil
+	}
+
+	log.Printf("Job saved to database with ID: %d", result.ID)
+
+	recordActivity(models.JobActivity{JobId: result.ID, Kind: models.JobActivityCreated, Summary: "Job created"})
+
+	// Read the requirements before generation, so the CV prompt can list them. Jobs
+	// left without them are retried by the background extraction.
+	if err := extractJobSkills(result.ID, normalize.Description(posting.Description)); err != nil {
+		log.Printf("Warning: Failed to store the requirements of job %d: %v", result.ID, err)
+	}
+
+	return requestGeneration(result.ID, posting)
+}
+
+// requestGeneration publishes the job for CV generation when it is enabled and then
+// marks its creation handled. Errors redeliver the creation request, which retries
+// only this step.
+func requestGeneration(id int, posting models.JobPosting) error {
+	// Load the stored job, with its canonical link and posting key, for further processing
+	job, err := sharedDB.GetJobByID(id)
+	if err != nil {
+		log.Printf("Warning: Could not reload job %d: %v", id, err)
+		job = &models.Job{
+			Id:             id,
+			Title:          posting.Title,
+			Company:        posting.Company,
+			Link:           posting.Link,
+			Status:         models.JobStatusOpen,
+			Description:    normalize.Description(posting.Description),
+			RawDescription: posting.Description,
+			JobDetails:     posting.JobDetails,
+		}
 	}
 
 	// Check if CV generation feature is enabled using shared library
 	cvGenerationEnabled, err := sharedDB.GetFeatureValue("cvGeneration")
 	if err != nil {
-		log.Printf("Warning: Could not check CV generation feature: %v", err)
-		return nil // Don't fail the whole process for this
+		return fmt.Errorf("could not check CV generation feature: %w", err)
 	}
 
 	// Publish job created event for CV generation if enabled
 	if cvGenerationEnabled {
-		if err := sharedNats.PublishJobMessage(job); err != nil {
-			log.Printf("Warning: Failed to publish job message for CV generation: %v", err)
-		} else {
-			log.Printf("Job message published for CV generation")
+		if err := sharedNats.PublishJobMessage(*job); err != nil {
+			return fmt.Errorf("failed to publish job message for CV generation: %w", err)
 		}
+		log.Printf("Job message published for CV generation")
 	}
 
-	return nil
+	return sharedDB.MarkJobCreationHandled(id)
 }
 
 func handleJobStatusUpdate(statusUpdate sharedNats.JobStatusUpdateRequest) error {
 	log.Printf("Processing job status update: Job ID %d to status %s", statusUpdate.JobID, statusUpdate.Status)
 
-	// Update job status in database using shared library
-	err := sharedDB.UpdateJobStatus(statusUpdate.JobID, statusUpdate.Status)
-	if err != nil {
+	// Validate the transition against the pipeline and record it in the history
+	change, err := sharedDB.TransitionJobStatus(statusUpdate.JobID, statusUpdate.Status, statusUpdate.Note)
+	if err == sharedDB.ErrNotFound || err == sharedDB.ErrInvalidStatus {
+		// Redelivery cannot fix these, acknowledge the request
+		log.Printf("Rejected status update of job %d to %s: %v", statusUpdate.JobID, statusUpdate.Status, err)
+		return nil
+	} else if err != nil {
 		return err
 	}
 
-	log.Printf("Job %d status updated to %s in database", statusUpdate.JobID, statusUpdate.Status)
+	log.Printf("Job %d status updated from %s to %s in database", change.JobId, change.From, change.To)
+
+	recordActivity(models.JobActivity{
+		JobId:   change.JobId,
+		Kind:    models.JobActivityStatusChanged,
+		Summary: fmt.Sprintf("Status changed from %s to %s", change.From, change.To),
+		Detail:  change.Note,
+	})
+
+	if err := sharedNats.PublishJobStatusChangedMessage(*change); err != nil {
+		log.Printf("Warning: Failed to publish job status changed message: %v", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// recordActivity adds an entry to the job timeline. The change itself is stored
+// already, so a failure is only logged.
+func recordActivity(activity models.JobActivity) {
+	if err := sharedDB.RecordJobActivity(activity); err != nil {
+		log.Printf("Warning: Failed to record %s activity for job %d: %v", activity.Kind, activity.JobId, err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/skills"
+	"github.com/hirepilot/shared/worker"
+)
+
+// defaultSkillsInterval is how often the requirements of pending jobs are read, e.g.
+// of jobs stored before the skills index or added through the API
+const defaultSkillsInterval = 10 * time.Minute
+
+// skillsBatchSize bounds the jobs read per batch of the background extraction
+const skillsBatchSize = 50
+
+// skillExtractor reads the requirements of job descriptions, with the AI model when
+// SKILLS_AI_EXTRACTION is enabled
+var skillExtractor skills.Extractor
+
+// startSkillExtraction configures the extractor and reads the requirements of pending
+// jobs now and then every SKILLS_EXTRACTION_INTERVAL in the background
+func startSkillExtraction() {
+	if value := os.Getenv("SKILLS_AI_EXTRACTION"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid SKILLS_AI_EXTRACTION %q, expected true or false", value)
+		}
+		if enabled {
+			client, err := sharedAI.DefaultClient()
+			if err != nil {
+				log.Fatalf("Failed to create the AI client of SKILLS_AI_EXTRACTION: %v", err)
+			}
+			skillExtractor.AI = client
+			log.Println("Reading job requirements with the AI model")
+		}
+	}
+
+	go worker.Every("the skill extraction of pending jobs", "SKILLS_EXTRACTION_INTERVAL", defaultSkillsInterval, extractPendingSkills)
+}
+
+// extractPendingSkills reads the requirements of every pending job, in batches.
+// Failures are retried on the next run.
+func extractPendingSkills() {
+	extracted := 0
+	for {
+		jobs, err := sharedDB.GetJobsPendingSkills(skillsBatchSize)
+		if err != nil {
+			log.Printf("Failed to load jobs pending requirements: %v", err)
+			return
+		}
+		for _, job := range jobs {
+			if err := extractJobSkills(job.JobId, job.Description); err != nil {
+				log.Printf("Failed to store the requirements of job %d: %v", job.JobId, err)
+				return
+			}
+			extracted++
+		}
+		if len(jobs) < skillsBatchSize {
+			break
+		}
+	}
+	if extracted > 0 {
+		log.Printf("Read the requirements of %d jobs", extracted)
+	}
+}
+
+// extractJobSkills reads the requirements of a job description and stores them in place
+// of the previous ones. A failing AI model leaves the taxonomy requirements.
+func extractJobSkills(jobID int, description string) error {
+	found, err := skillExtractor.Extract(description)
+	if err != nil {
+		log.Printf("Warning: AI requirement extraction failed for job %d: %v", jobID, err)
+	}
+	return sharedDB.ReplaceJobSkills(jobID, found)
+}
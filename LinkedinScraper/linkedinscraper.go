@@ -9,7 +9,9 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/hirepilot/shared/models"
 	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/posting"
 )
 
 func main() {
@@ -695,7 +697,7 @@ func processSingleJob(page *rod.Page, job *rod.Element, index int) error {
 	err = rod.Try(func() {
 		// Look for the specific job description container with timeout
 		descriptionElement := page.Timeout(5 * time.Second).MustElement(".jobs-box__html-content")
-		jobDescription = descriptionElement.MustText()
+		jobDescription = descriptionHTML(descriptionElement)
 	})
 	if err == nil && jobDescription != "" {
 		log.Printf("Job Description found (method 1)")
@@ -703,7 +705,7 @@ func processSingleJob(page *rod.Page, job *rod.Element, index int) error {
 		// Fallback 1: try the jobs description content class
 		err = rod.Try(func() {
 			descriptionElement := page.Timeout(5 * time.Second).MustElement(".jobs-description-content__text")
-			jobDescription = descriptionElement.MustText()
+			jobDescription = descriptionHTML(descriptionElement)
 		})
 		if err == nil && jobDescription != "" {
 			log.Printf("Job Description found (method 2)")
@@ -715,7 +717,7 @@ func processSingleJob(page *rod.Page, job *rod.Element, index int) error {
 					// Get the parent container and find the description div
 					parentContainer := aboutJobHeader.MustParent()
 					descriptionDiv := parentContainer.MustElement("div.mt4")
-					jobDescription = descriptionDiv.MustText()
+					jobDescription = descriptionHTML(descriptionDiv)
 				}
 			})
 			if err == nil && jobDescription != "" {
@@ -724,7 +726,7 @@ func processSingleJob(page *rod.Page, job *rod.Element, index int) error {
 				// Fallback 3: try the jobs description container
 				err = rod.Try(func() {
 					descriptionElement := page.Timeout(3 * time.Second).MustElement(".jobs-description__container")
-					jobDescription = descriptionElement.MustText()
+					jobDescription = descriptionHTML(descriptionElement)
 				})
 				if err == nil && jobDescription != "" {
 					log.Printf("Job Description found (method 4)")
@@ -743,6 +745,11 @@ func processSingleJob(page *rod.Page, job *rod.Element, index int) error {
 		log.Printf("Job Description: %s", jobDescription)
 	}
 
+	// Extract location, workplace, employment type, seniority, salary and posting date
+	log.Println("Extracting job details...")
+	details := extractJobDetails(page)
+	log.Printf("Job details: location=%q workplace=%q employment=%q seniority=%q", details.Location, details.WorkplaceType, details.EmploymentType, details.Seniority)
+
 	// Return to saved jobs list
 	log.Println("Navigating back to saved jobs list...")
 
@@ -790,20 +797,66 @@ func processSingleJob(page *rod.Page, job *rod.Element, index int) error {
 	}
 
 	// Save job to database
-	if err := sendJobCreationRequest(title, company, currentURL, jobDescription); err != nil {
+	if err := sendJobCreationRequest(models.JobPosting{
+		Title:       title,
+		Company:     company,
+		Link:        currentURL,
+		Description: jobDescription,
+		JobDetails:  details,
+	}); err != nil {
 		log.Printf("Warning: Failed to save job to database: %v", err)
 	}
 
 	return nil
 }
 
-func sendJobCreationRequest(title, company, link, description string) error {
+// descriptionHTML returns the markup of a description element so that JobService keeps
+// its headings and lists when normalising it, or nothing when the element is empty
+func descriptionHTML(element *rod.Element) string {
+	if strings.TrimSpace(element.MustText()) == "" {
+		return ""
+	}
+	return element.MustHTML()
+}
+
+// extractJobDetails reads the structured attributes from the top card of a job page.
+// The primary description reads "Location · 2 weeks ago · 100 applicants" and the
+// insights read e.g. "$120K/yr - $150K/yr  Remote  Full-time  Mid-Senior level".
+func extractJobDetails(page *rod.Page) models.JobDetails {
+	var primary, insights string
+
+	rod.Try(func() {
+		primary = page.Timeout(3 * time.Second).MustElement(".job-details-jobs-unified-top-card__primary-description-container").MustText()
+	})
+	for _, selector := range []string{
+		".job-details-preferences-and-skills",
+		".job-details-jobs-unified-top-card__job-insight",
+		".jobs-unified-top-card__job-insight",
+	} {
+		rod.Try(func() {
+			for _, element := range page.Timeout(2 * time.Second).MustElements(selector) {
+				insights += " " + element.MustText()
+			}
+		})
+	}
+
+	details := posting.ParseDetails(primary+" "+insights, time.Now())
+	if primary != "" {
+		details.Location = strings.TrimSpace(strings.Split(primary, "·")[0])
+	}
+	return details
+}
+
+func sendJobCreationRequest(job models.JobPosting) error {
+	key := posting.Canonicalize(job.Link)
+	job.Source, job.ExternalId = key.Source, key.ExternalID
+
 	// Publish job creation request to NATS JetStream (JobService will handle DB insertion)
-	err := sharedNats.PublishJobCreationRequest(title, company, link, description)
+	err := sharedNats.PublishJobCreationRequest(job)
 	if err != nil {
 		return fmt.Errorf("failed to publish job creation request: %w", err)
 	}
 
-	log.Printf("Job creation request published for scraped job: %s at %s", title, company)
+	log.Printf("Job creation request published for scraped job: %s at %s", job.Title, job.Company)
 	return nil
 }
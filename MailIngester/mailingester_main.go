@@ -0,0 +1,182 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	"github.com/hirepilot/shared/alerts"
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/mail"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/posting"
+	"github.com/hirepilot/shared/replies"
+	"github.com/hirepilot/shared/worker"
+)
+
+// defaultInterval is how often the mailbox is read
+const defaultInterval = 15 * time.Minute
+
+// defaultLookback is how far back messages are read; older ones are never processed
+const defaultLookback = 7 * 24 * time.Hour
+
+// MailIngester reads the job alert emails of LinkedIn and Indeed from a mailbox and
+// requests a job for every posting they list. The mailbox is an IMAP server
+// (MAIL_IMAP_ADDR, MAIL_IMAP_USER, MAIL_IMAP_PASSWORD, MAIL_IMAP_MAILBOX and
+// MAIL_IMAP_TLS, on by default), a Maildir (MAIL_MAILDIR) or an mbox file (MAIL_MBOX).
+// Every message is read once; the mailbox itself is never changed.
+//
+// Other messages about a job with an application in progress are added to its
+// timeline. Rejections, interview invitations and offers propose a status change,
+// applied right away from a confidence of MAIL_AUTO_APPLY_CONFIDENCE (0.9 by default,
+// above 1 to always confirm) and otherwise left for confirmation. MAIL_AI_CLASSIFIER
+// asks the AI model about the messages the rules are not sure about.
+func main() {
+	log.Println("Starting MailIngester...")
+
+	mailbox := mailboxFromEnv()
+	handler := replyHandlerFromEnv()
+
+	// Initialize shared database
+	sharedDB.InitDB()
+
+	// Initialize shared NATS JetStream
+	sharedNats.InitJetStream()
+	defer sharedNats.Close()
+
+	lookback := worker.DurationFromEnv("MAIL_LOOKBACK", defaultLookback)
+	worker.Every("the mailbox reads", "MAIL_POLL_INTERVAL", defaultInterval, func() {
+		run(mailbox, handler, time.Now().Add(-lookback))
+	})
+}
+
+// mailboxFromEnv returns the configured mailbox; exactly one must be configured
+func mailboxFromEnv() mail.Mailbox {
+	var mailboxes []mail.Mailbox
+	if addr := os.Getenv("MAIL_IMAP_ADDR"); addr != "" {
+		useTLS := true
+		if value := os.Getenv("MAIL_IMAP_TLS"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				log.Fatalf("Invalid MAIL_IMAP_TLS %q, expected true or false", value)
+			}
+			useTLS = parsed
+		}
+		mailboxes = append(mailboxes, &mail.IMAP{
+			Addr:     addr,
+			Username: os.Getenv("MAIL_IMAP_USER"),
+			Password: os.Getenv("MAIL_IMAP_PASSWORD"),
+			Mailbox:  os.Getenv("MAIL_IMAP_MAILBOX"),
+			TLS:      useTLS,
+		})
+	}
+	if path := os.Getenv("MAIL_MAILDIR"); path != "" {
+		mailboxes = append(mailboxes, &mail.Maildir{Path: path})
+	}
+	if path := os.Getenv("MAIL_MBOX"); path != "" {
+		mailboxes = append(mailboxes, &mail.Mbox{Path: path})
+	}
+	if len(mailboxes) != 1 {
+		log.Fatal("Configure exactly one mailbox: MAIL_IMAP_ADDR, MAIL_MAILDIR or MAIL_MBOX")
+	}
+	return mailboxes[0]
+}
+
+// replyHandlerFromEnv returns the handler of messages about applications
+func replyHandlerFromEnv() *replyHandler {
+	handler := &replyHandler{autoApply: replies.StrongConfidence}
+	if value := os.Getenv("MAIL_AUTO_APPLY_CONFIDENCE"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid MAIL_AUTO_APPLY_CONFIDENCE %q, expected a number such as 0.9", value)
+		}
+		handler.autoApply = parsed
+	}
+	if value := os.Getenv("MAIL_AI_CLASSIFIER"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid MAIL_AI_CLASSIFIER %q, expected true or false", value)
+		}
+		if enabled {
+			client, err := sharedAI.DefaultClient()
+			if err != nil {
+				log.Fatalf("Failed to create the AI client of MAIL_AI_CLASSIFIER: %v", err)
+			}
+			handler.classifier.AI = client
+		}
+	}
+	return handler
+}
+
+// run processes the messages received since a time that were not processed yet;
+// failures are logged and retried on the next run
+func run(mailbox mail.Mailbox, handler *replyHandler, since time.Time) {
+	known, err := sharedDB.GetMailMessageIDs(since)
+	if err != nil {
+		log.Printf("Failed to load processed messages: %v", err)
+		return
+	}
+	messages, err := mailbox.Messages(since, func(id string) bool { return known[id] })
+	if err != nil {
+		log.Printf("Failed to read the mailbox: %v", err)
+		return
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+	if err := handler.loadCandidates(); err != nil {
+		log.Printf("Failed to load the jobs with an application in progress: %v", err)
+		return
+	}
+
+	// The same posting is listed by many alerts, requests within a run are sent once
+	published := map[posting.Key]bool{}
+	for _, msg := range messages {
+		// Job sites also send notices about applications, such as a viewed application
+		if template, postings := alerts.Parse(msg); len(postings) > 0 {
+			record, err := ingest(msg, template, postings, published)
+			if err != nil {
+				log.Printf("Failed to ingest message %s: %v", msg.ID, err)
+				continue
+			}
+			if _, err := sharedDB.InsertMailMessage(record); err != nil {
+				log.Printf("Warning: failed to record message %s: %v", msg.ID, err)
+			}
+		} else if err := handler.handle(msg); err != nil {
+			log.Printf("Failed to record message %s: %v", msg.ID, err)
+		}
+	}
+}
+
+// ingest publishes a job creation request for each posting of a job alert. A message
+// is recorded only after all its requests are out, so a failed publish is retried on
+// the next run; JobService keeps a posting listed twice as one job.
+func ingest(msg mail.Message, template string, postings []models.JobPosting, published map[posting.Key]bool) (models.MailMessage, error) {
+	record := models.MailMessage{
+		MessageId:  msg.ID,
+		Sender:     msg.From,
+		Subject:    msg.Subject,
+		Kind:       models.MailKindJobAlert,
+		Template:   template,
+		Postings:   len(postings),
+		ReceivedAt: msg.Date,
+	}
+
+	for _, p := range postings {
+		key := posting.Canonicalize(p.Link)
+		if published[key] {
+			continue
+		}
+		p.Source, p.ExternalId = key.Source, key.ExternalID
+		if err := sharedNats.PublishJobCreationRequest(p); err != nil {
+			return record, err
+		}
+		published[key] = true
+	}
+	log.Printf("Read %d postings from %s alert %q", len(postings), template, msg.Subject)
+	return record, nil
+}
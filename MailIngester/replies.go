@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/mail"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/replies"
+)
+
+// applicationStatuses are the statuses of the jobs messages are matched against, the
+// ones with an application waiting for an answer
+var applicationStatuses = []models.JobStatus{
+	models.JobStatusApplied, models.JobStatusScreening, models.JobStatusInterviewing,
+	models.JobStatusOffer, models.JobStatusGhosted,
+}
+
+// maxEmailDetail bounds the runes of an email kept in the job timeline
+const maxEmailDetail = 10000
+
+// replyHandler attaches messages about applications to their jobs and proposes the
+// status changes they call for
+type replyHandler struct {
+	classifier replies.Classifier
+	autoApply  float64 // Confidence from which certain matches change the status without confirmation
+	candidates []replies.Candidate
+}
+
+// loadCandidates reads the jobs messages of this run can be about
+func (h *replyHandler) loadCandidates() error {
+	jobs, err := sharedDB.GetApplicationJobs(applicationStatuses)
+	if err != nil {
+		return err
+	}
+	h.candidates = make([]replies.Candidate, len(jobs))
+	for i, job := range jobs {
+		h.candidates[i] = replies.Candidate{
+			JobId: job.JobId, Title: job.Title, Company: job.Company, Status: job.Status, ContactEmails: job.ContactEmails,
+		}
+	}
+	return nil
+}
+
+// handle records a message that is not a job alert. Messages about a job are classified,
+// added to its timeline and may propose a status change, which is applied right away
+// when both the match and the classification are sure enough.
+func (h *replyHandler) handle(msg mail.Message) error {
+	record := models.MailMessage{
+		MessageId:  msg.ID,
+		Sender:     msg.From,
+		Subject:    msg.Subject,
+		Kind:       models.MailKindOther,
+		ReceivedAt: msg.Date,
+	}
+	match, ok := replies.MatchJob(msg, h.candidates)
+	if !ok {
+		// Messages about no known application are not worth a call to the AI model
+		record.Kind = replies.ClassifyRules(msg).Kind
+		_, err := sharedDB.InsertMailMessage(record)
+		return err
+	}
+
+	class, err := h.classifier.Classify(msg)
+	if err != nil {
+		log.Printf("Warning: AI classification of message %s failed, using the rules: %v", msg.ID, err)
+	}
+	record.Kind, record.JobId = class.Kind, &match.JobId
+	messageID, err := sharedDB.InsertMailMessage(record)
+	if err != nil || messageID == 0 {
+		return err
+	}
+	log.Printf("Message %q is a %s about job %d (%s, %s)", msg.Subject, class.Kind, match.JobId, match.Reason, class.Reason)
+
+	refID := int(messageID)
+	if err := sharedDB.RecordJobActivity(models.JobActivity{
+		JobId:   match.JobId,
+		Kind:    models.JobActivityEmailReceived,
+		Summary: emailSummary(msg, class.Kind),
+		Detail:  truncate(replies.Body(msg), maxEmailDetail),
+		RefId:   &refID,
+	}); err != nil {
+		log.Printf("Warning: failed to add message %s to the timeline of job %d: %v", msg.ID, match.JobId, err)
+	}
+
+	to := class.Kind.StatusChange()
+	if to == "" || !match.Status.CanTransitionTo(to) {
+		return nil
+	}
+	proposal := models.StatusProposal{
+		JobId:         match.JobId,
+		MailMessageId: refID,
+		Kind:          class.Kind,
+		From:          match.Status,
+		To:            to,
+		Confidence:    class.Confidence,
+		Reason:        fmt.Sprintf("%s; %s", class.Reason, match.Reason),
+		State:         models.StatusProposalPending,
+	}
+	if match.Certain && class.Confidence >= h.autoApply {
+		if err := sharedNats.PublishJobStatusUpdateRequest(match.JobId, to, "From email: "+msg.Subject); err != nil {
+			log.Printf("Warning: failed to request status %s for job %d, queueing it for confirmation: %v", to, match.JobId, err)
+		} else {
+			proposal.State, proposal.Automatic = models.StatusProposalAccepted, true
+			h.setStatus(match.JobId, to)
+			log.Printf("Requested status %s for job %d from message %q", to, match.JobId, msg.Subject)
+		}
+	}
+	if _, err := sharedDB.InsertStatusProposal(proposal); err != nil {
+		log.Printf("Warning: failed to record the status proposal for job %d: %v", match.JobId, err)
+	}
+	return nil
+}
+
+// setStatus updates the status of a candidate after a change was requested, so later
+// messages of the run start from it
+func (h *replyHandler) setStatus(jobID int, status models.JobStatus) {
+	for i := range h.candidates {
+		if h.candidates[i].JobId == jobID {
+			h.candidates[i].Status = status
+		}
+	}
+}
+
+// emailSummary names the sender, subject and kind of a message, e.g.
+// "Email from Jane Doe: Your application (interview invite)"
+func emailSummary(msg mail.Message, kind models.MailKind) string {
+	sender := msg.FromName
+	if sender == "" {
+		sender = msg.From
+	}
+	summary := fmt.Sprintf("Email from %s: %s", sender, msg.Subject)
+	if kind != models.MailKindOther {
+		summary += fmt.Sprintf(" (%s)", kindLabels[kind])
+	}
+	return truncate(summary, 512)
+}
+
+var kindLabels = map[models.MailKind]string{
+	models.MailKindRejection:       "rejection",
+	models.MailKindInterview:       "interview invite",
+	models.MailKindOffer:           "offer",
+	models.MailKindAcknowledgement: "acknowledgement",
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-1]) + "…"
+}
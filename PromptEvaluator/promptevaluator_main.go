@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/evaluation"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+)
+
+type PromptEvaluationRequestMessage struct {
+	Type string                             `json:"type"`
+	Data sharedNats.PromptEvaluationRequest `json:"data"`
+}
+
+// PromptEvaluator runs candidate prompts against a benchmark set of jobs.
+//
+// Without flags it runs as a service handling evaluation requests from the Backend.
+// With flags it runs once from the command line:
+//
+//	promptevaluator -prompt 7 -kind cv -benchmark 1 -out report.json
+//	promptevaluator -prompt 7 -kind cover -benchmark-file fixtures/benchmark.json
+//	promptevaluator -export 1 -out fixtures/benchmark.json
+//	promptevaluator -import fixtures/benchmark.json
+func main() {
+	promptID := flag.Int("prompt", 0, "ID of the candidate prompt to evaluate")
+	kind := flag.String("kind", evaluation.KindCV, "prompt kind: cv or cover")
+	benchmarkID := flag.Int("benchmark", 0, "ID of the stored benchmark set")
+	benchmarkFile := flag.String("benchmark-file", "", "path to a benchmark set JSON fixture")
+	exportID := flag.Int("export", 0, "export the benchmark set with this ID as a JSON fixture")
+	importFile := flag.String("import", "", "import a benchmark set JSON fixture")
+	out := flag.String("out", "", "output file for reports and exports (default stdout)")
+	flag.Parse()
+
+	// Initialize shared database
+	sharedDB.InitDB()
+
+	switch {
+	case *exportID != 0:
+		set, err := sharedDB.GetBenchmarkSetByID(*exportID)
+		if err != nil {
+			log.Fatalf("Failed to load benchmark set %d: %v", *exportID, err)
+		}
+		if err := writeJSON(*out, evaluation.Fixture(set)); err != nil {
+			log.Fatalf("Failed to write benchmark fixture: %v", err)
+		}
+	case *importFile != "":
+		set, err := readFixture(*importFile)
+		if err != nil {
+			log.Fatalf("Failed to read benchmark fixture: %v", err)
+		}
+		id, err := sharedDB.InsertBenchmarkSet(*set)
+		if err != nil {
+			log.Fatalf("Failed to import benchmark set: %v", err)
+		}
+		log.Printf("Imported benchmark set %q with ID: %d", set.Name, id)
+	case *promptID != 0:
+		// Evaluated as another kind, the prompt would be compared against the wrong default
+		prompt, err := sharedDB.GetPromptByID(*promptID)
+		if err != nil {
+			log.Fatalf("Failed to load prompt %d: %v", *promptID, err)
+		}
+		if prompt.Kind != models.PromptKind(*kind) {
+			log.Fatalf("Prompt %d is a %s prompt, run it with -kind %s", *promptID, prompt.Kind, prompt.Kind)
+		}
+
+		var set *models.BenchmarkSet
+		if *benchmarkFile != "" {
+			set, err = readFixture(*benchmarkFile)
+		} else {
+			set, err = sharedDB.GetBenchmarkSetByID(*benchmarkID)
+		}
+		if err != nil {
+			log.Fatalf("Failed to load benchmark set: %v", err)
+		}
+
+		report, err := runEvaluation(*promptID, *kind, set)
+		if err != nil {
+			log.Fatalf("Evaluation failed: %v", err)
+		}
+		if err := writeJSON(*out, report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	default:
+		runService()
+	}
+}
+
+func runService() {
+	// Initialize shared NATS JetStream
+	sharedNats.InitJetStream()
+	defer sharedNats.Close()
+
+	if _, err := sharedNats.SubscribeToPromptEvaluationRequestsGeneric(handlePromptEvaluationRequest); err != nil {
+		log.Fatalf("Failed to start prompt evaluation request consumer: %v", err)
+	}
+
+	log.Println("PromptEvaluator started successfully")
+	// Block forever
+	select {}
+}
+
+func handlePromptEvaluationRequest(data []byte) error {
+	var reqMsg PromptEvaluationRequestMessage
+	if err := json.Unmarshal(data, &reqMsg); err != nil {
+		log.Printf("Failed to unmarshal prompt evaluation request message: %v", err)
+		return err
+	}
+	evaluationID := reqMsg.Data.EvaluationID
+	log.Printf("Received prompt evaluation request: %d", evaluationID)
+
+	stored, err := sharedDB.GetPromptEvaluationByID(evaluationID)
+	if err == sharedDB.ErrNotFound {
+		log.Printf("Evaluation %d not found, ignoring request", evaluationID)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if stored.Status == models.EvaluationStatusCompleted {
+		log.Printf("Evaluation %d already completed", evaluationID)
+		return nil
+	}
+
+	if err := sharedDB.UpdatePromptEvaluationStatus(evaluationID, models.EvaluationStatusRunning, ""); err != nil {
+		return err
+	}
+
+	set, err := sharedDB.GetBenchmarkSetByID(stored.BenchmarkSetId)
+	if err != nil {
+		return failEvaluation(evaluationID, fmt.Errorf("failed to load benchmark set %d: %w", stored.BenchmarkSetId, err))
+	}
+
+	report, err := runEvaluation(stored.PromptId, stored.Kind, set)
+	if err != nil {
+		return failEvaluation(evaluationID, err)
+	}
+
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return failEvaluation(evaluationID, err)
+	}
+	if err := sharedDB.CompletePromptEvaluation(evaluationID, payload); err != nil {
+		log.Printf("Failed to store report of evaluation %d: %v", evaluationID, err)
+		return err
+	}
+
+	log.Printf("Evaluation %d completed", evaluationID)
+	return nil
+}
+
+// failEvaluation records the failure; the request is acknowledged since retrying would repeat the AI spend
+func failEvaluation(evaluationID int, cause error) error {
+	log.Printf("Evaluation %d failed: %v", evaluationID, cause)
+	if err := sharedDB.UpdatePromptEvaluationStatus(evaluationID, models.EvaluationStatusFailed, cause.Error()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// runEvaluation compares a candidate prompt against the current default prompt of the same kind
+func runEvaluation(promptID int, kind string, set *models.BenchmarkSet) (*evaluation.Report, error) {
+	candidate, err := sharedDB.GetPromptForKind(promptID, models.PromptKind(kind))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prompt %d: %w", promptID, err)
+	}
+
+	var baseline *models.Prompt
+	switch kind {
+	case evaluation.KindCV:
+		baseline, err = sharedDB.GetDefaultCVPrompt()
+	case evaluation.KindCover:
+		baseline, err = sharedDB.GetDefaultCoverPrompt()
+	default:
+		return nil, fmt.Errorf("unsupported prompt kind for evaluation: %s", kind)
+	}
+	if err == sharedDB.ErrNotFound {
+		log.Printf("No default %s prompt found, evaluating without a baseline", kind)
+		baseline = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if baseline != nil && baseline.Id == candidate.Id && baseline.Version == candidate.Version {
+		// The candidate is the current default, there is nothing to compare against
+		baseline = nil
+	}
+
+	var scorePrompt string
+	if scorePromptObj, err := sharedDB.GetDefaultScorePrompt(); err == nil {
+		scorePrompt = scorePromptObj.Prompt
+	} else {
+		log.Printf("No default score prompt available, skipping scoring: %v", err)
+	}
+
+	aiClient, err := sharedAI.DefaultClient()
+	if err != nil {
+		return nil, fmt.Errorf("AI client error: %w", err)
+	}
+
+	evaluator := &evaluation.Evaluator{
+		Client:      aiClient,
+		ScorePrompt: scorePrompt,
+		Config:      evaluation.DefaultConfig(kind),
+	}
+
+	log.Printf("Evaluating prompt %d (version %d) on benchmark %q with %d jobs", candidate.Id, candidate.Version, set.Name, len(set.Jobs))
+	return evaluator.Compare(kind, candidate, baseline, set)
+}
+
+func readFixture(path string) (*models.BenchmarkSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set models.BenchmarkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	if set.Name == "" || len(set.Jobs) == 0 {
+		return nil, fmt.Errorf("benchmark fixture %s needs a name and at least one job", path)
+	}
+	return &set, nil
+}
+
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
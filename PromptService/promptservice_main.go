@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 
 	sharedDB "github.com/hirepilot/shared/db"
@@ -18,6 +19,26 @@ type PromptUpdateMessage struct {
 	Data sharedNats.PromptUpdateRequest `json:"data"`
 }
 
+type PromptRollbackMessage struct {
+	Type string                           `json:"type"`
+	Data sharedNats.PromptRollbackRequest `json:"data"`
+}
+
+type PromptSetDefaultMessage struct {
+	Type string                             `json:"type"`
+	Data sharedNats.PromptSetDefaultRequest `json:"data"`
+}
+
+type PromptDeleteMessage struct {
+	Type string                         `json:"type"`
+	Data sharedNats.PromptDeleteRequest `json:"data"`
+}
+
+type PromptArchiveMessage struct {
+	Type string                          `json:"type"`
+	Data sharedNats.PromptArchiveRequest `json:"data"`
+}
+
 func main() {
 	log.Println("Starting Prompt Service...")
 
@@ -77,7 +98,103 @@ func main() {
 		log.Fatalf("Failed to subscribe to prompt update messages: %v", err)
 	}
 
-	log.Println("Prompt Service subscribed to prompt creation and update messages")
+	// Subscribe to prompt rollback requests
+	_, err = sharedNats.SubscribeToPromptRollbackRequestsGeneric(func(data []byte) error {
+		log.Printf("Received prompt rollback request")
+
+		var message PromptRollbackMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Printf("Error unmarshaling prompt rollback message: %v", err)
+			return err
+		}
+
+		// Process the prompt rollback request
+		if err := handlePromptRollback(message.Data); err != nil {
+			log.Printf("Error handling prompt rollback: %v", err)
+			return err
+		}
+
+		log.Printf("Prompt rollback handled successfully")
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("Failed to subscribe to prompt rollback messages: %v", err)
+	}
+
+	// Subscribe to prompt set default requests
+	_, err = sharedNats.SubscribeToPromptSetDefaultRequestsGeneric(func(data []byte) error {
+		log.Printf("Received prompt set default request")
+
+		var message PromptSetDefaultMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Printf("Error unmarshaling prompt set default message: %v", err)
+			return err
+		}
+
+		// Process the prompt set default request
+		if err := handlePromptSetDefault(message.Data); err != nil {
+			log.Printf("Error handling prompt set default: %v", err)
+			return err
+		}
+
+		log.Printf("Prompt set default handled successfully")
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("Failed to subscribe to prompt set default messages: %v", err)
+	}
+
+	// Subscribe to prompt delete requests
+	_, err = sharedNats.SubscribeToPromptDeleteRequestsGeneric(func(data []byte) error {
+		log.Printf("Received prompt delete request")
+
+		var message PromptDeleteMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Printf("Error unmarshaling prompt delete message: %v", err)
+			return err
+		}
+
+		// Process the prompt delete request
+		if err := handlePromptDelete(message.Data); err != nil {
+			log.Printf("Error handling prompt delete: %v", err)
+			return err
+		}
+
+		log.Printf("Prompt delete handled successfully")
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("Failed to subscribe to prompt delete messages: %v", err)
+	}
+
+	// Subscribe to prompt archive requests
+	_, err = sharedNats.SubscribeToPromptArchiveRequestsGeneric(func(data []byte) error {
+		log.Printf("Received prompt archive request")
+
+		var message PromptArchiveMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Printf("Error unmarshaling prompt archive message: %v", err)
+			return err
+		}
+
+		// Process the prompt archive request
+		if err := handlePromptArchive(message.Data); err != nil {
+			log.Printf("Error handling prompt archive: %v", err)
+			return err
+		}
+
+		log.Printf("Prompt archive handled successfully")
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("Failed to subscribe to prompt archive messages: %v", err)
+	}
+
+	log.Println("Prompt Service subscribed to prompt creation, update, rollback, set default, delete and archive messages")
 	log.Println("Prompt Service is running. Press Ctrl+C to exit.")
 
 	// Keep the service running
@@ -88,7 +205,7 @@ func handlePromptCreation(promptData sharedNats.PromptCreationRequest) error {
 	log.Printf("Processing prompt creation: %s", promptData.Name)
 
 	// Insert prompt into database using shared library
-	id, err := sharedDB.InsertPromptWithCover(promptData.Name, promptData.Prompt, promptData.CvGenerationDefault, promptData.ScoreGenerationDefault, promptData.CoverGenerationDefault)
+	id, err := sharedDB.InsertPrompt(promptData.Name, promptData.Prompt, promptData.Kind, promptData.IsDefault, promptData.Tag)
 	if err != nil {
 		return err
 	}
@@ -101,11 +218,92 @@ func handlePromptUpdate(promptData sharedNats.PromptUpdateRequest) error {
 	log.Printf("Processing prompt update: ID %d", promptData.ID)
 
 	// Update prompt in database using shared library
-	err := sharedDB.UpdatePromptWithCover(promptData.ID, promptData.Name, promptData.Prompt, promptData.CvGenerationDefault, promptData.ScoreGenerationDefault, promptData.CoverGenerationDefault)
-	if err != nil {
+	err := sharedDB.UpdatePrompt(promptData.ID, promptData.Name, promptData.Prompt, promptData.Kind, promptData.IsDefault, promptData.Tag)
+	if err == sharedDB.ErrPromptArchived {
+		// An archived prompt has to be restored before it can become a default
+		log.Printf("Prompt %d is archived, ignoring update", promptData.ID)
+		return nil
+	} else if errors.Is(err, sharedDB.ErrPromptInUse) {
+		// Retrying will not change the default, another prompt has to be promoted first
+		log.Printf("Prompt %d is the default of its kind, ignoring update: %v", promptData.ID, err)
+		return nil
+	} else if err != nil {
 		return err
 	}
 
 	log.Printf("Prompt %d updated in database", promptData.ID)
 	return nil
 }
+
+func handlePromptRollback(rollback sharedNats.PromptRollbackRequest) error {
+	log.Printf("Processing prompt rollback: ID %d to version %d", rollback.ID, rollback.Version)
+
+	// Restore the requested version; the history keeps every intermediate version
+	newVersion, err := sharedDB.RollbackPrompt(rollback.ID, rollback.Version)
+	if err == sharedDB.ErrNotFound {
+		// Retrying will not make a missing version appear, so acknowledge the message
+		log.Printf("Prompt %d has no version %d, ignoring rollback", rollback.ID, rollback.Version)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	log.Printf("Prompt %d rolled back to version %d (now version %d)", rollback.ID, rollback.Version, newVersion)
+	return nil
+}
+
+func handlePromptSetDefault(request sharedNats.PromptSetDefaultRequest) error {
+	log.Printf("Processing prompt set default: ID %d", request.ID)
+
+	// The previous default of the same kind is cleared in the same transaction
+	err := sharedDB.SetDefaultPrompt(request.ID)
+	if err == sharedDB.ErrNotFound {
+		log.Printf("Prompt %d not found, ignoring set default", request.ID)
+		return nil
+	} else if err == sharedDB.ErrPromptArchived {
+		log.Printf("Prompt %d is archived, ignoring set default", request.ID)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	log.Printf("Prompt %d is now the default of its kind", request.ID)
+	return nil
+}
+
+func handlePromptDelete(request sharedNats.PromptDeleteRequest) error {
+	log.Printf("Processing prompt delete: ID %d", request.ID)
+
+	// The Backend already checked the prompt, but it may have become a default since
+	err := sharedDB.DeletePrompt(request.ID)
+	if err == sharedDB.ErrNotFound {
+		log.Printf("Prompt %d not found, ignoring delete", request.ID)
+		return nil
+	} else if errors.Is(err, sharedDB.ErrPromptInUse) {
+		log.Printf("Refusing to delete prompt %d: %v", request.ID, err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	log.Printf("Prompt %d deleted from database", request.ID)
+	return nil
+}
+
+func handlePromptArchive(request sharedNats.PromptArchiveRequest) error {
+	log.Printf("Processing prompt archive: ID %d (archived: %t)", request.ID, request.Archived)
+
+	err := sharedDB.ArchivePrompt(request.ID, request.Archived)
+	if err == sharedDB.ErrNotFound {
+		log.Printf("Prompt %d not found, ignoring archive", request.ID)
+		return nil
+	} else if errors.Is(err, sharedDB.ErrPromptInUse) {
+		log.Printf("Refusing to archive prompt %d: %v", request.ID, err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	log.Printf("Prompt %d archive state updated", request.ID)
+	return nil
+}
@@ -2,14 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
 
 	sharedAI "github.com/hirepilot/shared/ai"
 	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/experiment"
 	"github.com/hirepilot/shared/models"
 	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/render"
 )
 
 type JobMessage struct {
@@ -50,21 +53,18 @@ func handleJobCreated(data []byte) error {
 	}
 	log.Printf("Received job: %+v", jobMsg.Data.Id)
 
-	// Get the default CV generation prompt from database
-	cvPrompt, err := sharedDB.GetDefaultCVPrompt()
+	// Use the default CV prompt, or the variant assigned by an active experiment
+	cvPrompt, assignment, err := experiment.ResolvePrompt(models.PromptKindCV, jobMsg.Data.Id)
 	if err == sharedDB.ErrNotFound {
 		log.Printf("No default CV generation prompt found")
 		return fmt.Errorf("no default CV generation prompt found")
 	} else if err != nil {
-		log.Printf("Failed to get default CV prompt: %v", err)
+		log.Printf("Failed to resolve CV prompt: %v", err)
 		return err
 	}
 
 	// Use shared AI client to generate CV
-	promptText := cvPrompt.Prompt + "\n\n" +
-		"Title: " + jobMsg.Data.Title + "\n" +
-		"Company: " + jobMsg.Data.Company + "\n" +
-		"Description: " + jobMsg.Data.Description + "\n"
+	promptText := render.GenerationForJob(cvPrompt.Prompt, &jobMsg.Data)
 
 	aiClient, err := sharedAI.DefaultClient()
 	if err != nil {
@@ -80,13 +80,21 @@ func handleJobCreated(data []byte) error {
 
 	log.Printf("Generated CV for Job : %v", jobMsg.Data.Id)
 
+	if assignment != nil {
+		if err := sharedDB.RecordExperimentUsage(assignment.Id, sharedAI.EstimateTokens(promptText), sharedAI.EstimateTokens(cv)); err != nil {
+			log.Printf("Failed to record experiment usage for job %d: %v", jobMsg.Data.Id, err)
+		}
+	}
+
 	// after generation, update the job with the generated CV using shared DB
-	err = sharedDB.UpdateJobCV(jobMsg.Data.Id, cv)
+	versionID := promptVersionID(cvPrompt)
+	err = sharedDB.UpdateJobCV(jobMsg.Data.Id, cv, versionID)
 	if err != nil {
 		log.Printf("Failed to update job with generated CV: %v", err)
 		return err
 	}
 	log.Printf("Job %d updated with generated CV", jobMsg.Data.Id)
+	recordActivity(jobMsg.Data.Id, versionID)
 
 	// Always publish CV generated message for PDF generation and other services
 	// Update job data with generated CV and publish message
@@ -140,20 +148,26 @@ func handleCVGenerationRequest(data []byte) error {
 	}
 
 	var promptText string
+	var versionID *int
 	if cvReqMsg.Data.PromptID != nil {
 		// Fetch specific prompt
-		prompt, err := sharedDB.GetPromptByID(*cvReqMsg.Data.PromptID)
+		prompt, err := sharedDB.GetPromptForKind(*cvReqMsg.Data.PromptID, models.PromptKindCV)
 		if err == sharedDB.ErrNotFound {
 			log.Printf("Prompt not found for ID %d", *cvReqMsg.Data.PromptID)
 			return err
+		} else if errors.Is(err, sharedDB.ErrWrongPromptKind) {
+			// Redelivery cannot fix a prompt of the wrong kind, so acknowledge the request
+			log.Printf("Ignoring request for job %s: %v", cvReqMsg.Data.JobID, err)
+			return nil
 		} else if err != nil {
 			log.Printf("Failed to get prompt by ID %d: %v", *cvReqMsg.Data.PromptID, err)
 			return err
 		}
 		promptText = prompt.Prompt
+		versionID = promptVersionID(prompt)
 	} else {
-		// Get default CV generation prompt
-		cvPrompt, err := sharedDB.GetDefaultCVPrompt()
+		// Get the CV prompt of one of the job's tags, or the default CV generation prompt
+		cvPrompt, err := sharedDB.GetJobPrompt(models.PromptKindCV, job.Id)
 		if err == sharedDB.ErrNotFound {
 			log.Printf("No default CV generation prompt found")
 			promptText = "Generate a professional CV for the following job:"
@@ -162,14 +176,12 @@ func handleCVGenerationRequest(data []byte) error {
 			return err
 		} else {
 			promptText = cvPrompt.Prompt
+			versionID = promptVersionID(cvPrompt)
 		}
 	}
 
 	// Generate CV using AI
-	fullPrompt := promptText + "\n\n" +
-		"Title: " + job.Title + "\n" +
-		"Company: " + job.Company + "\n" +
-		"Description: " + job.Description + "\n"
+	fullPrompt := render.GenerationForJob(promptText, job)
 
 	aiClient, err := sharedAI.DefaultClient()
 	if err != nil {
@@ -192,12 +204,13 @@ func handleCVGenerationRequest(data []byte) error {
 		return err
 	}
 
-	err = sharedDB.UpdateJobCV(jobID, cv)
+	err = sharedDB.UpdateJobCV(jobID, cv, versionID)
 	if err != nil {
 		log.Printf("Failed to update job with generated CV: %v", err)
 		return err
 	}
 	log.Printf("Job %s updated with generated CV", cvReqMsg.Data.JobID)
+	recordActivity(jobID, versionID)
 
 	// Publish CV generated message using shared NATS
 	job.Cv = cv
@@ -224,3 +237,25 @@ func handleCVGenerationRequest(data []byte) error {
 
 	return nil
 }
+
+// promptVersionID resolves the version of a prompt for traceability; failures are logged, not fatal
+func promptVersionID(prompt *models.Prompt) *int {
+	versionID, err := sharedDB.GetPromptVersionID(prompt)
+	if err != nil {
+		log.Printf("Warning: Could not resolve version of prompt %d: %v", prompt.Id, err)
+		return nil
+	}
+	return versionID
+}
+
+// recordActivity adds the generation to the job timeline; failures are logged, not fatal
+func recordActivity(jobID int, versionID *int) {
+	if err := sharedDB.RecordJobActivity(models.JobActivity{
+		JobId:   jobID,
+		Kind:    models.JobActivityCVGenerated,
+		Summary: "CV generated",
+		RefId:   versionID,
+	}); err != nil {
+		log.Printf("Warning: Failed to record CV activity for job %d: %v", jobID, err)
+	}
+}
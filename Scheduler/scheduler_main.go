@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/worker"
+)
+
+// defaultInterval is how often the reminder rules are evaluated
+const defaultInterval = time.Minute
+
+// maxUpcomingInterviews bounds the interviews looked at per rule and run
+const maxUpcomingInterviews = 500
+
+// Scheduler is the only time-driven service. On every run it expires reminders that
+// no longer apply, creates reminders from the enabled rules and publishes a
+// reminders.due event for each reminder that became due. Run a single instance.
+func main() {
+	log.Println("Starting Scheduler...")
+
+	// Initialize shared database
+	sharedDB.InitDB()
+
+	// Initialize shared NATS JetStream
+	sharedNats.InitJetStream()
+	defer sharedNats.Close()
+
+	worker.Every("the reminder rules", "SCHEDULER_INTERVAL", defaultInterval, func() { run(time.Now()) })
+}
+
+// run evaluates the rules once; failures are logged and retried on the next run
+func run(now time.Time) {
+	if expired, err := sharedDB.ExpireReminders(); err != nil {
+		log.Printf("Failed to expire reminders: %v", err)
+	} else if expired > 0 {
+		log.Printf("Expired %d reminders that no longer apply", expired)
+	}
+
+	rules, err := sharedDB.GetReminderRules(true)
+	if err != nil {
+		log.Printf("Failed to load reminder rules: %v", err)
+		return
+	}
+	for _, rule := range rules {
+		created, err := createReminders(rule, now)
+		if err != nil {
+			log.Printf("Failed to evaluate reminder rule %d (%s): %v", rule.Id, rule.Name, err)
+		}
+		if created > 0 {
+			log.Printf("Reminder rule %d (%s) created %d reminders", rule.Id, rule.Name, created)
+		}
+	}
+
+	notifyDueReminders()
+}
+
+// createReminders stores the reminders a rule asks for at now. Reminders created on
+// earlier runs are skipped by the database.
+func createReminders(rule models.ReminderRule, now time.Time) (int, error) {
+	delay := time.Duration(rule.DelayHours) * time.Hour
+	var reminders []models.Reminder
+
+	switch rule.Kind {
+	case models.ReminderRuleStatusStale:
+		jobs, err := sharedDB.GetStaleStatusJobs(rule.Status, now.Add(-delay))
+		if err != nil {
+			return 0, err
+		}
+		for _, job := range jobs {
+			reminders = append(reminders, models.Reminder{
+				JobId: job.JobId,
+				RefId: job.HistoryId,
+				Message: fmt.Sprintf("Follow up on %s at %s: %s for %s with no status change",
+					job.JobTitle, job.JobCompany, rule.Status, formatDelay(rule.DelayHours)),
+				DueAt: job.ChangedAt.Add(delay),
+			})
+		}
+	case models.ReminderRuleInterviewUpcoming:
+		interviews, err := sharedDB.GetUpcomingInterviews(now.Add(delay), maxUpcomingInterviews)
+		if err != nil {
+			return 0, err
+		}
+		for _, interview := range interviews {
+			reminders = append(reminders, models.Reminder{
+				JobId: interview.JobId,
+				RefId: interview.Id,
+				Message: fmt.Sprintf("%s interview for %s at %s on %s",
+					capitalize(strings.ReplaceAll(string(interview.Round), "_", " ")),
+					interview.JobTitle, interview.JobCompany, interview.ScheduledAt.UTC().Format("Mon 2 Jan 15:04 MST")),
+				DueAt: interview.ScheduledAt.Add(-delay),
+			})
+		}
+	default:
+		return 0, fmt.Errorf("unsupported rule kind: %s", rule.Kind)
+	}
+
+	created := 0
+	for _, reminder := range reminders {
+		reminder.RuleId = rule.Id
+		reminder.Kind = rule.Kind
+		isNew, err := sharedDB.InsertReminder(reminder)
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// notifyDueReminders publishes the reminders that became due. A reminder is marked
+// due only after its event is out, so a failed publish is retried on the next run.
+func notifyDueReminders() {
+	reminders, err := sharedDB.GetRemindersToNotify()
+	if err != nil {
+		log.Printf("Failed to load due reminders: %v", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		reminder.Status = models.ReminderStatusDue
+		if err := sharedNats.PublishReminderDueMessage(reminder); err != nil {
+			log.Printf("Failed to publish due reminder %d: %v", reminder.Id, err)
+			continue
+		}
+		if err := sharedDB.MarkReminderDue(reminder.Id); err != nil {
+			log.Printf("Failed to mark reminder %d as due: %v", reminder.Id, err)
+		}
+	}
+}
+
+// formatDelay renders a rule delay for messages, in days when it is a whole number of days
+func formatDelay(hours int) string {
+	switch {
+	case hours == 24:
+		return "1 day"
+	case hours%24 == 0:
+		return fmt.Sprintf("%d days", hours/24)
+	case hours == 1:
+		return "1 hour"
+	default:
+		return fmt.Sprintf("%d hours", hours)
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
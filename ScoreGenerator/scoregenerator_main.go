@@ -2,14 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 
 	sharedAI "github.com/hirepilot/shared/ai"
 	sharedDB "github.com/hirepilot/shared/db"
 	"github.com/hirepilot/shared/models"
 	sharedNats "github.com/hirepilot/shared/nats"
+	"github.com/hirepilot/shared/render"
 )
 
 type JobMessage struct {
@@ -62,8 +65,8 @@ func handleCVGenerated(data []byte) error {
 		return nil // Don't process if feature is disabled
 	}
 
-	// Get the default score generation prompt from database
-	scorePromptObj, err := sharedDB.GetDefaultScorePrompt()
+	// Get the score prompt of one of the job's tags, or the default score generation prompt
+	scorePromptObj, err := sharedDB.GetJobPrompt(models.PromptKindScore, jobMsg.Data.Id)
 	if err == sharedDB.ErrNotFound {
 		log.Printf("No default score generation prompt found")
 		return fmt.Errorf("no default score generation prompt found")
@@ -72,9 +75,7 @@ func handleCVGenerated(data []byte) error {
 		return err
 	}
 
-	scorePrompt := scorePromptObj.Prompt + "\n\n" +
-		"Job Description: " + jobMsg.Data.Description + "\n" +
-		"CV: " + jobMsg.Data.Cv + "\n"
+	scorePrompt := render.Score(scorePromptObj.Prompt, jobMsg.Data.Description, jobMsg.Data.Cv)
 
 	// Use shared AI client to generate score
 	aiClient, err := sharedAI.DefaultClient()
@@ -91,12 +92,14 @@ func handleCVGenerated(data []byte) error {
 	log.Printf("Generated Score : %s", score)
 
 	// Update job with score using shared DB
-	err = sharedDB.UpdateJobScore(jobMsg.Data.Id, score)
+	versionID := promptVersionID(scorePromptObj)
+	err = sharedDB.UpdateJobScore(jobMsg.Data.Id, score, versionID)
 	if err != nil {
 		log.Printf("DB update error: %v", err)
 		return err
 	}
 	log.Printf("Job %d updated with generated Score", jobMsg.Data.Id)
+	recordActivity(jobMsg.Data.Id, score, versionID)
 	return nil
 }
 
@@ -131,20 +134,26 @@ func handleScoreGenerationRequest(data []byte) error {
 	}
 
 	var promptText string
+	var versionID *int
 	if scoreReqMsg.Data.PromptID != nil {
 		// Fetch specific prompt
-		prompt, err := sharedDB.GetPromptByID(*scoreReqMsg.Data.PromptID)
+		prompt, err := sharedDB.GetPromptForKind(*scoreReqMsg.Data.PromptID, models.PromptKindScore)
 		if err == sharedDB.ErrNotFound {
 			log.Printf("Prompt not found for ID %d", *scoreReqMsg.Data.PromptID)
 			return err
+		} else if errors.Is(err, sharedDB.ErrWrongPromptKind) {
+			// Redelivery cannot fix a prompt of the wrong kind, so acknowledge the request
+			log.Printf("Ignoring request for job %s: %v", scoreReqMsg.Data.JobID, err)
+			return nil
 		} else if err != nil {
 			log.Printf("Failed to get prompt by ID %d: %v", *scoreReqMsg.Data.PromptID, err)
 			return err
 		}
 		promptText = prompt.Prompt
+		versionID = promptVersionID(prompt)
 	} else {
-		// Get default score generation prompt
-		scorePromptObj, err := sharedDB.GetDefaultScorePrompt()
+		// Get the score prompt of one of the job's tags, or the default score generation prompt
+		scorePromptObj, err := sharedDB.GetJobPrompt(models.PromptKindScore, job.Id)
 		if err == sharedDB.ErrNotFound {
 			log.Printf("No default score generation prompt found")
 			promptText = "Score the following CV based on the provided job description. The CV will start below '**Resume**'. Only return a numerical score between 0 and 100, where 0 is a poor match and 100 is a perfect match. Do not include any explanations, notes, or additional text."
@@ -153,13 +162,12 @@ func handleScoreGenerationRequest(data []byte) error {
 			return err
 		} else {
 			promptText = scorePromptObj.Prompt
+			versionID = promptVersionID(scorePromptObj)
 		}
 	}
 
 	// Generate score using AI
-	scorePrompt := promptText + "\n\n" +
-		"Job Description: " + job.Description + "\n" +
-		"CV: " + job.Cv + "\n"
+	scorePrompt := render.Score(promptText, job.Description, job.Cv)
 
 	aiClient, err := sharedAI.DefaultClient()
 	if err != nil {
@@ -182,12 +190,35 @@ func handleScoreGenerationRequest(data []byte) error {
 		return err
 	}
 
-	err = sharedDB.UpdateJobScore(jobID, score)
+	err = sharedDB.UpdateJobScore(jobID, score, versionID)
 	if err != nil {
 		log.Printf("Failed to update job with generated score: %v", err)
 		return err
 	}
 	log.Printf("Job %s updated with generated score", scoreReqMsg.Data.JobID)
+	recordActivity(jobID, score, versionID)
 
 	return nil
 }
+
+// promptVersionID resolves the version of a prompt for traceability; failures are logged, not fatal
+func promptVersionID(prompt *models.Prompt) *int {
+	versionID, err := sharedDB.GetPromptVersionID(prompt)
+	if err != nil {
+		log.Printf("Warning: Could not resolve version of prompt %d: %v", prompt.Id, err)
+		return nil
+	}
+	return versionID
+}
+
+// recordActivity adds the score to the job timeline; failures are logged, not fatal
+func recordActivity(jobID int, score string, versionID *int) {
+	if err := sharedDB.RecordJobActivity(models.JobActivity{
+		JobId:   jobID,
+		Kind:    models.JobActivityScoreGenerated,
+		Summary: "Score generated: " + strings.TrimSpace(score),
+		RefId:   versionID,
+	}); err != nil {
+		log.Printf("Warning: Failed to record score activity for job %d: %v", jobID, err)
+	}
+}
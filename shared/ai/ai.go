@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -136,4 +137,26 @@ func (o *OllamaClient) Generate(prompt string) (string, error) {
 // DefaultClient creates a default AI client (Gemini for now)
 func DefaultClient() (AIClient, error) {
 	return NewClient(ProviderGemini)
-}
\ No newline at end of file
+}
+
+// EstimateTokens approximates the token count of a text (roughly four characters per token)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// EstimateCost estimates the cost of a generation from token counts.
+// Rates per 1K tokens come from AI_INPUT_COST_PER_1K and AI_OUTPUT_COST_PER_1K, defaulting to Gemini 2.5 Pro pricing.
+func EstimateCost(inputTokens, outputTokens int) float64 {
+	inputRate := costRate("AI_INPUT_COST_PER_1K", 0.00125)
+	outputRate := costRate("AI_OUTPUT_COST_PER_1K", 0.01)
+	return float64(inputTokens)/1000*inputRate + float64(outputTokens)/1000*outputRate
+}
+
+func costRate(name string, fallback float64) float64 {
+	if value := os.Getenv(name); value != "" {
+		if rate, err := strconv.ParseFloat(value, 64); err == nil {
+			return rate
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,274 @@
+// Package alerts reads the postings listed in the job alert emails of job sites
+package alerts
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/mail"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Names of the alert templates, as returned by Parse
+const (
+	TemplateLinkedIn = "linkedin"
+	TemplateIndeed   = "indeed"
+)
+
+// template reads the alerts of one job site. jobLink returns the canonical link of the
+// posting a link in the email points at, or nothing for other links.
+type template struct {
+	name    string
+	domain  string
+	jobLink func(href string) string
+}
+
+var templates = []template{
+	{TemplateLinkedIn, "linkedin.com", linkedInJobLink},
+	{TemplateIndeed, "indeed.com", indeedJobLink},
+}
+
+// Parse returns the postings listed in a job alert email and the template that read
+// them. Messages that are not from a known job site return no template. Postings
+// carry the title, company and link, and the location and attributes when listed;
+// each posting is listed once.
+func Parse(msg mail.Message) (string, []models.JobPosting) {
+	_, domain, _ := strings.Cut(msg.From, "@")
+	for _, t := range templates {
+		if domain != t.domain && !strings.HasSuffix(domain, "."+t.domain) {
+			continue
+		}
+		var postings []models.JobPosting
+		if msg.HTML != "" {
+			if doc, err := html.Parse(strings.NewReader(msg.HTML)); err == nil {
+				postings = htmlPostings(doc, t.jobLink)
+			}
+		}
+		if len(postings) == 0 {
+			postings = textPostings(msg.Text, t.jobLink)
+		}
+		return t.name, postings
+	}
+	return "", nil
+}
+
+// linkedInJobLink accepts links such as linkedin.com/comm/jobs/view/3812345678/?trackingId=...
+func linkedInJobLink(href string) string {
+	if key := posting.Canonicalize(href); key.Source == posting.SourceLinkedIn {
+		return key.URL
+	}
+	return ""
+}
+
+var indeedJobKey = regexp.MustCompile(`\bjk=([0-9a-f]{16})\b`)
+
+// indeedJobLink accepts links carrying the job key, such as indeed.com/rc/clk/dl?jk=...
+// and the tracking redirects wrapping them
+func indeedJobLink(href string) string {
+	u, err := url.Parse(href)
+	if err != nil || !strings.HasSuffix(strings.ToLower(u.Hostname()), "indeed.com") {
+		return ""
+	}
+	decoded, err := url.QueryUnescape(href)
+	if err != nil {
+		decoded = href
+	}
+	if match := indeedJobKey.FindStringSubmatch(decoded); match != nil {
+		return "https://www.indeed.com/viewjob?jk=" + match[1]
+	}
+	return ""
+}
+
+// noiseLines are the badges and calls to action of alert job cards
+var noiseLines = regexp.MustCompile(`(?i)^(easy apply|actively (recruiting|hiring)|promoted|new|apply( now)?|view (job|details)|` +
+	`be an early applicant|urgently hiring|responsive employer|just posted|save( job)?|` +
+	`\d+\+? (applicants?|connections?|alumni|school alumni?|company alumni?)\b.*|.*\bago|.*\d+ days? ago)$`)
+
+// htmlPostings reads the job cards of an HTML alert. A card is the largest element
+// around the first link to a posting that links to no other posting. Its title is the
+// longest link text, the lines after the title name the company and location.
+func htmlPostings(doc *html.Node, jobLink func(href string) string) []models.JobPosting {
+	var links []string
+	anchors := map[string][]*html.Node{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			for _, a := range n.Attr {
+				if a.Key == "href" {
+					if link := jobLink(a.Val); link != "" {
+						if _, seen := anchors[link]; !seen {
+							links = append(links, link)
+						}
+						anchors[link] = append(anchors[link], n)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	owner := map[*html.Node]string{}
+	for link, nodes := range anchors {
+		for _, n := range nodes {
+			owner[n] = link
+		}
+	}
+
+	var postings []models.JobPosting
+	for _, link := range links {
+		card := anchors[link][0]
+		for card.Parent != nil && card.Parent.Type == html.ElementNode && linksOnly(card.Parent, link, owner) {
+			card = card.Parent
+		}
+
+		title := ""
+		for _, a := range anchors[link] {
+			if text := collapse(extract.Text(a)); len(text) > len(title) && !noiseLines.MatchString(text) {
+				title = text
+			}
+		}
+		if p, ok := cardPosting(title, lines(extract.Text(card)), link); ok {
+			postings = append(postings, p)
+		}
+	}
+	return postings
+}
+
+// linksOnly reports whether every posting link below n points at link
+func linksOnly(n *html.Node, link string, owner map[*html.Node]string) bool {
+	if other, ok := owner[n]; ok && other != link {
+		return false
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if !linksOnly(c, link, owner) {
+			return false
+		}
+	}
+	return true
+}
+
+// textPostings reads a plain text alert, where each posting is a block of title,
+// company and location lines followed by its link, on the last line of the block or
+// on its own after an empty line
+func textPostings(text string, jobLink func(href string) string) []models.JobPosting {
+	var postings []models.JobPosting
+	seen := map[string]bool{}
+	var block, previous []string
+	for _, line := range strings.Split(text, "\n") {
+		line = collapse(line)
+		link := ""
+		for _, field := range strings.Fields(line) {
+			if link = jobLink(strings.Trim(field, "<>()")); link != "" {
+				break
+			}
+		}
+		if link == "" {
+			if strings.Trim(line, "-=_* ") == "" {
+				// An empty line or separator ends a block
+				if len(block) > 0 {
+					previous = block
+				}
+				block = nil
+			} else if !noiseLines.MatchString(line) {
+				block = append(block, line)
+			}
+			continue
+		}
+
+		if len(block) == 0 {
+			block = previous
+		}
+		// Title, company and location are the last lines before the link, not counting
+		// the salary and job type lines that may follow them
+		end := len(block)
+		for end > 3 && detailLine(block[end-1]) {
+			end--
+		}
+		if end > 3 {
+			block = block[end-3:]
+		}
+		if !seen[link] && len(block) > 0 {
+			if p, ok := cardPosting(block[0], block, link); ok {
+				postings = append(postings, p)
+				seen[link] = true
+			}
+		}
+		block, previous = nil, nil
+	}
+	return postings
+}
+
+// detailLine reports whether a line of a text alert only lists a salary or job type
+func detailLine(line string) bool {
+	if strings.ContainsAny(line, "$€£") {
+		return true
+	}
+	return len(strings.Fields(line)) <= 2 && posting.ParseEmploymentType(line) != ""
+}
+
+// cardPosting builds a posting from the title and lines of a job card. The first
+// line after the title names the company, and the location after a " · " or on
+// the next line.
+func cardPosting(title string, cardLines []string, link string) (models.JobPosting, bool) {
+	if title == "" {
+		return models.JobPosting{}, false
+	}
+	var after []string
+	found := false
+	for _, line := range cardLines {
+		if !found {
+			found = line == title || strings.Contains(line, title)
+			continue
+		}
+		if !noiseLines.MatchString(line) {
+			after = append(after, line)
+		}
+	}
+	if len(after) == 0 {
+		return models.JobPosting{}, false
+	}
+
+	p := models.JobPosting{Title: title, Link: link}
+	company, location, hasLocation := strings.Cut(after[0], " · ")
+	p.Company = strings.TrimSpace(company)
+	if hasLocation {
+		location, _, _ = strings.Cut(location, " · ")
+	} else if len(after) > 1 && !strings.ContainsAny(after[1], "$€£") {
+		location = after[1]
+	}
+	if p.Company == "" {
+		return models.JobPosting{}, false
+	}
+
+	// Salaries, workplace and employment types are listed below the location
+	p.JobDetails = posting.ParseDetails(strings.Join(append([]string{title}, after...), " · "), time.Now())
+	p.Location = strings.TrimSpace(location)
+	if p.WorkplaceType == "" {
+		p.WorkplaceType = posting.ParseWorkplaceType(p.Location)
+	}
+	return p, true
+}
+
+// lines splits text into its non-empty lines, without list dashes or extra spaces
+func lines(text string) []string {
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = collapse(strings.TrimPrefix(strings.TrimSpace(line), "- ")); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
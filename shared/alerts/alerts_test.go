@@ -0,0 +1,128 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hirepilot/shared/mail"
+	"github.com/hirepilot/shared/models"
+)
+
+// The testdata Maildir and mbox hold the same LinkedIn and Indeed alert samples and
+// a newsletter that is not an alert
+var wantAlerts = map[string]struct {
+	template string
+	postings []models.JobPosting
+}{
+	"alert-3812345678@linkedin.com": {TemplateLinkedIn, []models.JobPosting{
+		{
+			Title: "Senior Go Developer", Company: "Acme", Link: "https://www.linkedin.com/jobs/view/3812345678/",
+			JobDetails: models.JobDetails{
+				Location:       "Amsterdam, North Holland, Netherlands (Hybrid)",
+				WorkplaceType:  models.WorkplaceHybrid,
+				SalaryMin:      floatPtr(70000),
+				SalaryMax:      floatPtr(90000),
+				SalaryCurrency: "EUR",
+			},
+		},
+		{
+			Title: "Platform Engineer", Company: "Globex", Link: "https://www.linkedin.com/jobs/view/3898765432/",
+			JobDetails: models.JobDetails{Location: "Utrecht, Netherlands (Remote)", WorkplaceType: models.WorkplaceRemote},
+		},
+	}},
+	"20261016.indeed.7f3a@indeed.com": {TemplateIndeed, []models.JobPosting{
+		{
+			Title: "Backend Engineer (Go)", Company: "Initech GmbH", Link: "https://www.indeed.com/viewjob?jk=0a1b2c3d4e5f6a7b",
+			JobDetails: models.JobDetails{
+				Location:       "Berlin",
+				EmploymentType: models.EmploymentFullTime,
+				SalaryMin:      floatPtr(85000),
+				SalaryMax:      floatPtr(85000),
+				SalaryCurrency: "USD",
+			},
+		},
+		{
+			Title: "Junior Backend Developer", Company: "Umbrella Corp", Link: "https://www.indeed.com/viewjob?jk=1234567890abcdef",
+			JobDetails: models.JobDetails{Location: "Berlin (Remote)", WorkplaceType: models.WorkplaceRemote, Seniority: models.SeniorityEntry},
+		},
+	}},
+	"weekly-42@example.org": {},
+}
+
+func TestParseMaildir(t *testing.T) {
+	testParseMailbox(t, &mail.Maildir{Path: "testdata/maildir"})
+}
+
+func TestParseMbox(t *testing.T) {
+	testParseMailbox(t, &mail.Mbox{Path: "testdata/alerts.mbox"})
+}
+
+func testParseMailbox(t *testing.T, mailbox mail.Mailbox) {
+	t.Helper()
+	messages, err := mailbox.Messages(time.Time{}, func(string) bool { return false })
+	if err != nil {
+		t.Fatalf("Messages: %v", err)
+	}
+	if len(messages) != len(wantAlerts) {
+		t.Fatalf("got %d messages, want %d", len(messages), len(wantAlerts))
+	}
+
+	for _, msg := range messages {
+		want, ok := wantAlerts[msg.ID]
+		if !ok {
+			t.Errorf("unexpected message %q", msg.ID)
+			continue
+		}
+		template, postings := Parse(msg)
+		if template != want.template {
+			t.Errorf("%s: template = %q, want %q", msg.ID, template, want.template)
+		}
+		if len(postings) != len(want.postings) {
+			t.Errorf("%s: got %d postings, want %d", msg.ID, len(postings), len(want.postings))
+			continue
+		}
+		for i, got := range postings {
+			assertPosting(t, got, want.postings[i])
+		}
+	}
+}
+
+func TestMessagesSinceAndKnown(t *testing.T) {
+	since := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	known := func(id string) bool { return id == "alert-3812345678@linkedin.com" }
+
+	for _, mailbox := range []mail.Mailbox{&mail.Maildir{Path: "testdata/maildir"}, &mail.Mbox{Path: "testdata/alerts.mbox"}} {
+		messages, err := mailbox.Messages(since, known)
+		if err != nil {
+			t.Fatalf("Messages: %v", err)
+		}
+		if len(messages) != 1 || messages[0].ID != "20261016.indeed.7f3a@indeed.com" {
+			t.Errorf("%T: got %d messages, want only the Indeed alert", mailbox, len(messages))
+		}
+	}
+}
+
+func assertPosting(t *testing.T, got, want models.JobPosting) {
+	t.Helper()
+	if got.Title != want.Title || got.Company != want.Company || got.Link != want.Link {
+		t.Errorf("got %q at %q (%s), want %q at %q (%s)", got.Title, got.Company, got.Link, want.Title, want.Company, want.Link)
+	}
+	if got.Location != want.Location || got.WorkplaceType != want.WorkplaceType ||
+		got.EmploymentType != want.EmploymentType || got.Seniority != want.Seniority || got.SalaryCurrency != want.SalaryCurrency {
+		t.Errorf("%s: details = %+v, want %+v", want.Title, got.JobDetails, want.JobDetails)
+	}
+	if !equalFloat(got.SalaryMin, want.SalaryMin) || !equalFloat(got.SalaryMax, want.SalaryMax) {
+		t.Errorf("%s: salary = %v-%v, want %v-%v", want.Title, got.SalaryMin, got.SalaryMax, want.SalaryMin, want.SalaryMax)
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func equalFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,100 @@
+package boards
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+)
+
+// DefaultAshbyURL is the base URL of the Ashby posting API
+const DefaultAshbyURL = "https://api.ashbyhq.com"
+
+// Ashby reads boards from {BaseURL}/posting-api/job-board/{board}
+type Ashby struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+type ashbyJobs struct {
+	Jobs []struct {
+		ID              string `json:"id"`
+		Title           string `json:"title"`
+		Location        string `json:"location"`
+		EmploymentType  string `json:"employmentType"`
+		IsRemote        bool   `json:"isRemote"`
+		WorkplaceType   string `json:"workplaceType"`
+		DescriptionHTML string `json:"descriptionHtml"`
+		PublishedAt     string `json:"publishedAt"`
+		JobURL          string `json:"jobUrl"`
+		IsListed        *bool  `json:"isListed"`
+		Compensation    *struct {
+			SummaryComponents []struct {
+				CompensationType string   `json:"compensationType"`
+				Interval         string   `json:"interval"`
+				CurrencyCode     string   `json:"currencyCode"`
+				MinValue         *float64 `json:"minValue"`
+				MaxValue         *float64 `json:"maxValue"`
+			} `json:"summaryComponents"`
+		} `json:"compensation"`
+	} `json:"jobs"`
+}
+
+var ashbyEmploymentTypes = map[string]models.EmploymentType{
+	"FullTime":  models.EmploymentFullTime,
+	"PartTime":  models.EmploymentPartTime,
+	"Contract":  models.EmploymentContract,
+	"Temporary": models.EmploymentTemporary,
+	"Intern":    models.EmploymentInternship,
+}
+
+var ashbyWorkplaceTypes = map[string]models.WorkplaceType{
+	"Remote": models.WorkplaceRemote,
+	"Hybrid": models.WorkplaceHybrid,
+	"OnSite": models.WorkplaceOnSite,
+}
+
+func (a *Ashby) Provider() models.BoardProvider {
+	return models.BoardAshby
+}
+
+func (a *Ashby) Postings(ctx context.Context, board string) ([]Posting, error) {
+	var response ashbyJobs
+	if err := getJSON(ctx, a.Client, a.BaseURL+"/posting-api/job-board/"+url.PathEscape(board)+"?includeCompensation=true", &response); err != nil {
+		return nil, err
+	}
+
+	postings := make([]Posting, 0, len(response.Jobs))
+	for _, job := range response.Jobs {
+		if job.IsListed != nil && !*job.IsListed {
+			continue
+		}
+
+		p := Posting{ID: job.ID}
+		p.Title = job.Title
+		p.Link = job.JobURL
+		p.Description = extract.FragmentText(job.DescriptionHTML)
+		p.Location = job.Location
+		p.WorkplaceType = ashbyWorkplaceTypes[job.WorkplaceType]
+		if p.WorkplaceType == "" && job.IsRemote {
+			p.WorkplaceType = models.WorkplaceRemote
+		}
+		p.EmploymentType = ashbyEmploymentTypes[job.EmploymentType]
+		p.Seniority = posting.ParseSeniority(job.Title)
+		p.PostedAt = parseTime(job.PublishedAt)
+		if job.Compensation != nil {
+			for _, c := range job.Compensation.SummaryComponents {
+				if c.CompensationType == "Salary" && c.Interval == "1 YEAR" && (c.MinValue != nil || c.MaxValue != nil) {
+					p.SalaryMin, p.SalaryMax, p.SalaryCurrency = c.MinValue, c.MaxValue, strings.ToUpper(c.CurrencyCode)
+					break
+				}
+			}
+		}
+		postings = append(postings, p)
+	}
+	return postings, nil
+}
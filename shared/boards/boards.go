@@ -0,0 +1,117 @@
+// Package boards reads the open postings of company job boards from public ATS APIs
+package boards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// ErrBoardNotFound is returned when the provider does not know the board
+var ErrBoardNotFound = errors.New("job board not found at the provider")
+
+// maxResponseSize bounds the JSON read from a board API
+const maxResponseSize = 20 << 20
+
+// Posting is an open posting of a board with the ID the provider gives it. Company
+// is empty when the provider does not name it.
+type Posting struct {
+	ID string
+	models.JobPosting
+}
+
+// JobSource reads the postings of the boards of one provider. Descriptions are
+// returned as plain text.
+type JobSource interface {
+	Provider() models.BoardProvider
+	Postings(ctx context.Context, board string) ([]Posting, error)
+}
+
+// NewSources returns a source for every provider. The production APIs are used
+// unless baseURLs names another base URL for a provider, e.g. a local fixture server.
+func NewSources(client *http.Client, baseURLs map[models.BoardProvider]string) map[models.BoardProvider]JobSource {
+	baseURL := func(provider models.BoardProvider, fallback string) string {
+		if url := baseURLs[provider]; url != "" {
+			return strings.TrimRight(url, "/")
+		}
+		return fallback
+	}
+	sources := []JobSource{
+		&Greenhouse{BaseURL: baseURL(models.BoardGreenhouse, DefaultGreenhouseURL), Client: client},
+		&Lever{BaseURL: baseURL(models.BoardLever, DefaultLeverURL), Client: client},
+		&Ashby{BaseURL: baseURL(models.BoardAshby, DefaultAshbyURL), Client: client},
+		&Workable{BaseURL: baseURL(models.BoardWorkable, DefaultWorkableURL), Client: client},
+	}
+
+	byProvider := make(map[models.BoardProvider]JobSource, len(sources))
+	for _, source := range sources {
+		byProvider[source.Provider()] = source
+	}
+	return byProvider
+}
+
+// getJSON decodes the JSON answer of a GET request into v
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "HirePilot/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrBoardNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered with status %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON from %s: %w", url, err)
+	}
+	return nil
+}
+
+// parseTime reads the RFC 3339 or date-only timestamps of the board APIs
+func parseTime(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// joinLocation joins the non-empty, distinct parts of a location
+func joinLocation(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		duplicate := false
+		for _, k := range kept {
+			if strings.EqualFold(k, part) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
@@ -0,0 +1,183 @@
+package boards
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// recordedResponses maps the API paths of the fixture boards to their recorded answers
+var recordedResponses = map[string]string{
+	"/v1/boards/acme/jobs":             "testdata/greenhouse.json",
+	"/v0/postings/globex":              "testdata/lever.json",
+	"/posting-api/job-board/initech":   "testdata/ashby.json",
+	"/api/v1/widget/accounts/umbrella": "testdata/workable.json",
+}
+
+// newFixtureSources returns sources for every provider backed by a local fixture server
+func newFixtureSources(t *testing.T) map[models.BoardProvider]JobSource {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := recordedResponses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, file)
+	}))
+	t.Cleanup(server.Close)
+
+	baseURLs := make(map[models.BoardProvider]string)
+	for _, provider := range []models.BoardProvider{models.BoardGreenhouse, models.BoardLever, models.BoardAshby, models.BoardWorkable} {
+		baseURLs[provider] = server.URL
+	}
+	return NewSources(server.Client(), baseURLs)
+}
+
+func TestPostings(t *testing.T) {
+	sources := newFixtureSources(t)
+
+	tests := []struct {
+		provider models.BoardProvider
+		board    string
+		want     Posting
+	}{
+		{
+			provider: models.BoardGreenhouse,
+			board:    "acme",
+			want: Posting{ID: "4012345", JobPosting: models.JobPosting{
+				Title:       "Senior Backend Engineer",
+				Company:     "Acme",
+				Link:        "https://boards.greenhouse.io/acme/jobs/4012345",
+				Description: "We are looking for a Backend Engineer.\n\n- Go\n- PostgreSQL",
+				JobDetails: models.JobDetails{
+					Location:      "Amsterdam, Netherlands (Hybrid)",
+					WorkplaceType: models.WorkplaceHybrid,
+					PostedAt:      timePtr("2026-09-02T12:30:00Z"),
+				},
+			}},
+		},
+		{
+			provider: models.BoardLever,
+			board:    "globex",
+			want: Posting{ID: "5ac21346-8e0c-4494-8e7a-3eb92ff77902", JobPosting: models.JobPosting{
+				Title:       "Staff Platform Engineer",
+				Link:        "https://jobs.lever.co/globex/5ac21346-8e0c-4494-8e7a-3eb92ff77902",
+				Description: "Run our Kubernetes platform.\n\nRequirements\n\n- Kubernetes\n- Terraform\n\nWe offer a learning budget.",
+				JobDetails: models.JobDetails{
+					Location:       "Berlin",
+					WorkplaceType:  models.WorkplaceHybrid,
+					EmploymentType: models.EmploymentFullTime,
+					SalaryMin:      floatPtr(95000),
+					SalaryMax:      floatPtr(120000),
+					SalaryCurrency: "EUR",
+					PostedAt:       timePtr("2026-09-02T08:00:00Z"),
+				},
+			}},
+		},
+		{
+			provider: models.BoardAshby,
+			board:    "initech",
+			want: Posting{ID: "0f2b8a5e-1c7d-4b6e-9a3f-6d2e8c4b1a90", JobPosting: models.JobPosting{
+				Title:       "Junior Data Engineer",
+				Link:        "https://jobs.ashbyhq.com/initech/0f2b8a5e-1c7d-4b6e-9a3f-6d2e8c4b1a90",
+				Description: "Build pipelines with dbt.",
+				JobDetails: models.JobDetails{
+					Location:       "Remote - Europe",
+					WorkplaceType:  models.WorkplaceRemote,
+					EmploymentType: models.EmploymentFullTime,
+					Seniority:      models.SeniorityEntry,
+					SalaryMin:      floatPtr(50000),
+					SalaryMax:      floatPtr(60000),
+					SalaryCurrency: "EUR",
+					PostedAt:       timePtr("2026-09-10T12:00:00Z"),
+				},
+			}},
+		},
+		{
+			provider: models.BoardWorkable,
+			board:    "umbrella",
+			want: Posting{ID: "A1B2C3D4E5", JobPosting: models.JobPosting{
+				Title:       "Contract QA Engineer",
+				Company:     "Umbrella Corp",
+				Link:        "https://apply.workable.com/j/A1B2C3D4E5",
+				Description: "Test our lab software.",
+				JobDetails: models.JobDetails{
+					Location:       "London, England, United Kingdom",
+					WorkplaceType:  models.WorkplaceRemote,
+					EmploymentType: models.EmploymentContract,
+					Seniority:      models.SeniorityAssociate,
+					PostedAt:       timePtr("2026-09-05T00:00:00Z"),
+				},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider), func(t *testing.T) {
+			postings, err := sources[tt.provider].Postings(context.Background(), tt.board)
+			if err != nil {
+				t.Fatalf("Postings: %v", err)
+			}
+			// Each response has one posting, the recorded Ashby board also an unlisted one to skip
+			if len(postings) != 1 {
+				t.Fatalf("got %d postings, want 1", len(postings))
+			}
+			assertPosting(t, postings[0], tt.want)
+		})
+	}
+}
+
+func TestPostingsUnknownBoard(t *testing.T) {
+	sources := newFixtureSources(t)
+	for provider, source := range sources {
+		if _, err := source.Postings(context.Background(), "missing"); !errors.Is(err, ErrBoardNotFound) {
+			t.Errorf("%s: Postings error = %v, want ErrBoardNotFound", provider, err)
+		}
+	}
+}
+
+func assertPosting(t *testing.T, got, want Posting) {
+	t.Helper()
+	if got.ID != want.ID || got.Title != want.Title || got.Company != want.Company || got.Link != want.Link {
+		t.Errorf("got %s %q at %q (%s), want %s %q at %q (%s)", got.ID, got.Title, got.Company, got.Link, want.ID, want.Title, want.Company, want.Link)
+	}
+	if got.Description != want.Description {
+		t.Errorf("description = %q, want %q", got.Description, want.Description)
+	}
+	if got.Location != want.Location || got.WorkplaceType != want.WorkplaceType ||
+		got.EmploymentType != want.EmploymentType || got.Seniority != want.Seniority || got.SalaryCurrency != want.SalaryCurrency {
+		t.Errorf("details = %+v, want %+v", got.JobDetails, want.JobDetails)
+	}
+	if !equalFloat(got.SalaryMin, want.SalaryMin) || !equalFloat(got.SalaryMax, want.SalaryMax) {
+		t.Errorf("salary = %v-%v, want %v-%v", got.SalaryMin, got.SalaryMax, want.SalaryMin, want.SalaryMax)
+	}
+	if (got.PostedAt == nil) != (want.PostedAt == nil) || got.PostedAt != nil && !got.PostedAt.Equal(*want.PostedAt) {
+		t.Errorf("postedAt = %v, want %v", got.PostedAt, want.PostedAt)
+	}
+}
+
+func timePtr(s string) *time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func equalFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,65 @@
+package boards
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+)
+
+// DefaultGreenhouseURL is the base URL of the Greenhouse job board API
+const DefaultGreenhouseURL = "https://boards-api.greenhouse.io"
+
+// Greenhouse reads boards from {BaseURL}/v1/boards/{board}/jobs
+type Greenhouse struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+type greenhouseJobs struct {
+	Jobs []struct {
+		ID          int64  `json:"id"`
+		Title       string `json:"title"`
+		AbsoluteURL string `json:"absolute_url"`
+		CompanyName string `json:"company_name"`
+		Content     string `json:"content"` // HTML, escaped once more
+		UpdatedAt   string `json:"updated_at"`
+		Published   string `json:"first_published"`
+		Location    struct {
+			Name string `json:"name"`
+		} `json:"location"`
+	} `json:"jobs"`
+}
+
+func (g *Greenhouse) Provider() models.BoardProvider {
+	return models.BoardGreenhouse
+}
+
+func (g *Greenhouse) Postings(ctx context.Context, board string) ([]Posting, error) {
+	var response greenhouseJobs
+	if err := getJSON(ctx, g.Client, g.BaseURL+"/v1/boards/"+url.PathEscape(board)+"/jobs?content=true", &response); err != nil {
+		return nil, err
+	}
+
+	postings := make([]Posting, 0, len(response.Jobs))
+	for _, job := range response.Jobs {
+		p := Posting{ID: strconv.FormatInt(job.ID, 10)}
+		p.Title = job.Title
+		p.Company = job.CompanyName
+		p.Link = job.AbsoluteURL
+		p.Description = extract.FragmentText(job.Content)
+		p.Location = job.Location.Name
+		p.WorkplaceType = posting.ParseWorkplaceType(job.Location.Name)
+		p.Seniority = posting.ParseSeniority(job.Title)
+		p.PostedAt = parseTime(job.Published)
+		if p.PostedAt == nil {
+			p.PostedAt = parseTime(job.UpdatedAt)
+		}
+		postings = append(postings, p)
+	}
+	return postings, nil
+}
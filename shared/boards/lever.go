@@ -0,0 +1,93 @@
+package boards
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+)
+
+// DefaultLeverURL is the base URL of the Lever postings API
+const DefaultLeverURL = "https://api.lever.co"
+
+// Lever reads boards from {BaseURL}/v0/postings/{board}
+type Lever struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+type leverPosting struct {
+	ID         string `json:"id"`
+	Text       string `json:"text"`
+	HostedURL  string `json:"hostedUrl"`
+	Categories struct {
+		Location   string `json:"location"`
+		Commitment string `json:"commitment"`
+	} `json:"categories"`
+	WorkplaceType string `json:"workplaceType"`
+	Description   string `json:"description"` // HTML
+	Lists         []struct {
+		Text    string `json:"text"`
+		Content string `json:"content"` // HTML list items
+	} `json:"lists"`
+	Additional  string `json:"additional"` // HTML
+	CreatedAt   int64  `json:"createdAt"`  // Milliseconds since the epoch
+	SalaryRange *struct {
+		Min      float64 `json:"min"`
+		Max      float64 `json:"max"`
+		Currency string  `json:"currency"`
+		Interval string  `json:"interval"`
+	} `json:"salaryRange"`
+}
+
+var leverWorkplaceTypes = map[string]models.WorkplaceType{
+	"remote": models.WorkplaceRemote,
+	"hybrid": models.WorkplaceHybrid,
+	"onsite": models.WorkplaceOnSite,
+}
+
+func (l *Lever) Provider() models.BoardProvider {
+	return models.BoardLever
+}
+
+func (l *Lever) Postings(ctx context.Context, board string) ([]Posting, error) {
+	var response []leverPosting
+	if err := getJSON(ctx, l.Client, l.BaseURL+"/v0/postings/"+url.PathEscape(board)+"?mode=json", &response); err != nil {
+		return nil, err
+	}
+
+	postings := make([]Posting, 0, len(response))
+	for _, job := range response {
+		// The description, the lists such as requirements and the closing text make up the posting
+		var description strings.Builder
+		description.WriteString(job.Description)
+		for _, list := range job.Lists {
+			description.WriteString("<h3>" + list.Text + "</h3><ul>" + list.Content + "</ul>")
+		}
+		description.WriteString(job.Additional)
+
+		p := Posting{ID: job.ID}
+		p.Title = job.Text
+		p.Link = job.HostedURL
+		p.Description = extract.FragmentText(description.String())
+		p.Location = job.Categories.Location
+		p.WorkplaceType = leverWorkplaceTypes[job.WorkplaceType]
+		p.EmploymentType = posting.ParseEmploymentType(job.Categories.Commitment)
+		p.Seniority = posting.ParseSeniority(job.Text)
+		if job.CreatedAt > 0 {
+			createdAt := time.UnixMilli(job.CreatedAt).UTC()
+			p.PostedAt = &createdAt
+		}
+		if s := job.SalaryRange; s != nil && s.Interval == "per-year-salary" && s.Max > 0 {
+			min, max := s.Min, s.Max
+			p.SalaryMin, p.SalaryMax, p.SalaryCurrency = &min, &max, strings.ToUpper(s.Currency)
+		}
+		postings = append(postings, p)
+	}
+	return postings, nil
+}
@@ -0,0 +1,70 @@
+package boards
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+)
+
+// DefaultWorkableURL is the base URL of the Workable job widget API
+const DefaultWorkableURL = "https://apply.workable.com"
+
+// Workable reads boards from {BaseURL}/api/v1/widget/accounts/{board}, where the board
+// is the account subdomain
+type Workable struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+type workableAccount struct {
+	Name string `json:"name"`
+	Jobs []struct {
+		Title          string `json:"title"`
+		Shortcode      string `json:"shortcode"`
+		EmploymentType string `json:"employment_type"`
+		Telecommuting  bool   `json:"telecommuting"`
+		URL            string `json:"url"`
+		City           string `json:"city"`
+		State          string `json:"state"`
+		Country        string `json:"country"`
+		Experience     string `json:"experience"`
+		PublishedOn    string `json:"published_on"`
+		Description    string `json:"description"` // HTML
+	} `json:"jobs"`
+}
+
+func (wk *Workable) Provider() models.BoardProvider {
+	return models.BoardWorkable
+}
+
+func (wk *Workable) Postings(ctx context.Context, board string) ([]Posting, error) {
+	var response workableAccount
+	if err := getJSON(ctx, wk.Client, wk.BaseURL+"/api/v1/widget/accounts/"+url.PathEscape(board)+"?details=true", &response); err != nil {
+		return nil, err
+	}
+
+	postings := make([]Posting, 0, len(response.Jobs))
+	for _, job := range response.Jobs {
+		p := Posting{ID: job.Shortcode}
+		p.Title = job.Title
+		p.Company = response.Name
+		p.Link = job.URL
+		p.Description = extract.FragmentText(job.Description)
+		p.Location = joinLocation(job.City, job.State, job.Country)
+		if job.Telecommuting {
+			p.WorkplaceType = models.WorkplaceRemote
+		}
+		p.EmploymentType = posting.ParseEmploymentType(job.EmploymentType)
+		p.Seniority = posting.ParseSeniority(job.Experience)
+		if p.Seniority == "" {
+			p.Seniority = posting.ParseSeniority(job.Title)
+		}
+		p.PostedAt = parseTime(job.PublishedOn)
+		postings = append(postings, p)
+	}
+	return postings, nil
+}
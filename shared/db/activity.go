@@ -0,0 +1,223 @@
+package db
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Job activity and note operations
+
+// maxActivitySummary is the length of the job_activities.summary column
+const maxActivitySummary = 512
+
+// RecordJobActivity appends an entry to the timeline of a job
+func RecordJobActivity(activity models.JobActivity) error {
+	_, err := recordJobActivity(db, activity)
+	return err
+}
+
+func recordJobActivity(ex execer, activity models.JobActivity) (int64, error) {
+	result, err := ex.Exec(
+		"INSERT INTO job_activities (job_id, kind, summary, detail, ref_id) VALUES (?, ?, ?, ?, ?)",
+		activity.JobId, activity.Kind, truncateRunes(activity.Summary, maxActivitySummary), nullString(activity.Detail), activity.RefId,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetJobTimeline returns the activities of a job, oldest first. Note entries carry
+// the current body of their note.
+func GetJobTimeline(jobID int) ([]models.JobActivity, error) {
+	rows, err := db.Query(`
+		SELECT a.id, a.job_id, a.kind, a.summary, COALESCE(n.body, a.detail, ''), a.ref_id, a.created_at
+		FROM job_activities a
+		LEFT JOIN job_notes n ON a.kind = ? AND n.id = a.ref_id
+		WHERE a.job_id = ?
+		ORDER BY a.created_at, a.id`,
+		models.JobActivityNote, jobID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timeline := []models.JobActivity{}
+	for rows.Next() {
+		var activity models.JobActivity
+		var refID sql.NullInt64
+		var createdAtStr string
+		if err := rows.Scan(&activity.Id, &activity.JobId, &activity.Kind, &activity.Summary, &activity.Detail, &refID, &createdAtStr); err != nil {
+			return nil, err
+		}
+		activity.RefId = nullIntPtr(refID)
+		activity.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+		timeline = append(timeline, activity)
+	}
+	return timeline, rows.Err()
+}
+
+// jobNoteColumns is the column list of note queries, in scanJobNote order
+const jobNoteColumns = "id, job_id, contact_id, body, created_at, updated_at"
+
+func scanJobNote(row rowScanner) (*models.JobNote, error) {
+	var note models.JobNote
+	var contactID sql.NullInt64
+	var createdAtStr, updatedAtStr string
+	if err := row.Scan(&note.Id, &note.JobId, &contactID, &note.Body, &createdAtStr, &updatedAtStr); err != nil {
+		return nil, err
+	}
+	note.ContactId = nullIntPtr(contactID)
+	note.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	note.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAtStr)
+	return &note, nil
+}
+
+// InsertJobNote attaches a note to an existing job and adds it to the timeline. A note
+// about a contact links the contact to the job and marks it as contacted now.
+func InsertJobNote(note models.JobNote) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM jobs WHERE id = ?", note.JobId).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		return 0, ErrNotFound
+	}
+
+	summary := "Note added"
+	if note.ContactId != nil {
+		var name string
+		err := tx.QueryRow("SELECT name FROM contacts WHERE id = ?", *note.ContactId).Scan(&name)
+		if err == sql.ErrNoRows {
+			return 0, ErrNotFound
+		} else if err != nil {
+			return 0, err
+		}
+		if err := linkJobContact(tx, note.JobId, *note.ContactId); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE contacts SET last_contacted_at = UTC_TIMESTAMP() WHERE id = ?", *note.ContactId); err != nil {
+			return 0, err
+		}
+		summary = "Note about " + name
+	}
+
+	result, err := tx.Exec("INSERT INTO job_notes (job_id, contact_id, body) VALUES (?, ?, ?)", note.JobId, note.ContactId, note.Body)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	noteID := int(id)
+	if _, err := recordJobActivity(tx, models.JobActivity{
+		JobId:   note.JobId,
+		Kind:    models.JobActivityNote,
+		Summary: summary,
+		RefId:   &noteID,
+	}); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateJobNote replaces the body of a note; its timeline entry shows the new body
+func UpdateJobNote(id int, body string) error {
+	result, err := db.Exec("UPDATE job_notes SET body = ? WHERE id = ?", body, id)
+	if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetJobNoteByID(id)
+		return err
+	})
+}
+
+// DeleteJobNote removes a note together with its timeline entry
+func DeleteJobNote(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM job_notes WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM job_activities WHERE kind = ? AND ref_id = ?", models.JobActivityNote, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetJobNoteByID returns a note
+func GetJobNoteByID(id int) (*models.JobNote, error) {
+	note, err := scanJobNote(db.QueryRow("SELECT "+jobNoteColumns+" FROM job_notes WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return note, err
+}
+
+// GetJobNotes returns the notes of a job, newest first
+func GetJobNotes(jobID int) ([]models.JobNote, error) {
+	rows, err := db.Query("SELECT "+jobNoteColumns+" FROM job_notes WHERE job_id = ? ORDER BY created_at DESC, id DESC", jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []models.JobNote{}
+	for rows.Next() {
+		note, err := scanJobNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, *note)
+	}
+	return notes, rows.Err()
+}
+
+// backfillJobActivities builds the timeline of jobs stored before it existed from
+// their status history and interviews
+func backfillJobActivities() {
+	_, err := db.Exec(`
+		INSERT INTO job_activities (job_id, kind, summary, detail, ref_id, created_at)
+		SELECT h.job_id,
+			CASE WHEN h.from_status IS NULL THEN 'created' ELSE 'status_changed' END,
+			CASE WHEN h.from_status IS NULL THEN 'Job created' ELSE CONCAT('Status changed from ', h.from_status, ' to ', h.to_status) END,
+			h.note, NULL, h.changed_at
+		FROM job_status_history h
+		WHERE NOT EXISTS (SELECT 1 FROM job_activities a WHERE a.job_id = h.job_id)
+		UNION ALL
+		SELECT i.job_id, 'interview_scheduled', CONCAT(REPLACE(i.round, '_', ' '), ' interview scheduled'), NULL, i.id, i.created_at
+		FROM interviews i
+		WHERE NOT EXISTS (SELECT 1 FROM job_activities a WHERE a.job_id = i.job_id)
+	`)
+	if err != nil {
+		log.Fatalf("Job activities backfill error: %v", err)
+	}
+}
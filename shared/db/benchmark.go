@@ -0,0 +1,191 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Benchmark and prompt evaluation operations
+
+// InsertBenchmarkSet stores a benchmark set together with its jobs
+func InsertBenchmarkSet(set models.BenchmarkSet) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"INSERT INTO benchmark_sets (name, description, created_at) VALUES (?, ?, ?)",
+		set.Name, set.Description, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	setID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range set.Jobs {
+		keywords, err := json.Marshal(job.Keywords)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(
+			"INSERT INTO benchmark_jobs (set_id, title, company, description, keywords) VALUES (?, ?, ?, ?, ?)",
+			setID, job.Title, job.Company, job.Description, string(keywords),
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return setID, nil
+}
+
+// GetBenchmarkSets lists benchmark sets without their jobs
+func GetBenchmarkSets() ([]models.BenchmarkSet, error) {
+	rows, err := db.Query("SELECT id, name, description, created_at FROM benchmark_sets ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []models.BenchmarkSet
+	for rows.Next() {
+		set, err := scanBenchmarkSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, *set)
+	}
+
+	return sets, nil
+}
+
+// GetBenchmarkSetByID gets a benchmark set including all of its jobs
+func GetBenchmarkSetByID(id int) (*models.BenchmarkSet, error) {
+	set, err := scanBenchmarkSet(db.QueryRow("SELECT id, name, description, created_at FROM benchmark_sets WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT id, set_id, title, company, description, keywords FROM benchmark_jobs WHERE set_id = ? ORDER BY id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job models.BenchmarkJob
+		var title, company, description, keywords sql.NullString
+		if err := rows.Scan(&job.Id, &job.SetId, &title, &company, &description, &keywords); err != nil {
+			return nil, err
+		}
+		job.Title = title.String
+		job.Company = company.String
+		job.Description = description.String
+		if keywords.Valid && keywords.String != "" {
+			if err := json.Unmarshal([]byte(keywords.String), &job.Keywords); err != nil {
+				return nil, err
+			}
+		}
+		set.Jobs = append(set.Jobs, job)
+	}
+
+	return set, nil
+}
+
+func scanBenchmarkSet(row rowScanner) (*models.BenchmarkSet, error) {
+	var set models.BenchmarkSet
+	var description sql.NullString
+	var createdAtStr string
+
+	if err := row.Scan(&set.Id, &set.Name, &description, &createdAtStr); err != nil {
+		return nil, err
+	}
+	set.Description = description.String
+	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+		set.CreatedAt = createdAt
+	}
+
+	return &set, nil
+}
+
+// InsertPromptEvaluation creates a pending evaluation
+func InsertPromptEvaluation(promptID int, kind string, benchmarkSetID int) (int64, error) {
+	result, err := db.Exec(
+		"INSERT INTO prompt_evaluations (prompt_id, kind, benchmark_set_id, status, created_at) VALUES (?, ?, ?, ?, ?)",
+		promptID, kind, benchmarkSetID, models.EvaluationStatusPending, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// UpdatePromptEvaluationStatus moves an evaluation to running or failed
+func UpdatePromptEvaluationStatus(id int, status models.EvaluationStatus, errorMessage string) error {
+	if status == models.EvaluationStatusFailed {
+		_, err := db.Exec("UPDATE prompt_evaluations SET status = ?, error = ?, completed_at = CURRENT_TIMESTAMP() WHERE id = ?", status, errorMessage, id)
+		return err
+	}
+
+	_, err := db.Exec("UPDATE prompt_evaluations SET status = ? WHERE id = ?", status, id)
+	return err
+}
+
+// CompletePromptEvaluation stores the comparison report of a finished evaluation
+func CompletePromptEvaluation(id int, report []byte) error {
+	_, err := db.Exec(
+		"UPDATE prompt_evaluations SET status = ?, report = ?, completed_at = CURRENT_TIMESTAMP() WHERE id = ?",
+		models.EvaluationStatusCompleted, string(report), id,
+	)
+	return err
+}
+
+// GetPromptEvaluationByID gets an evaluation including its report
+func GetPromptEvaluationByID(id int) (*models.PromptEvaluation, error) {
+	var evaluation models.PromptEvaluation
+	var report, errorMessage, completedAtStr sql.NullString
+	var createdAtStr string
+
+	err := db.QueryRow(
+		"SELECT id, prompt_id, kind, benchmark_set_id, status, report, error, created_at, completed_at FROM prompt_evaluations WHERE id = ?",
+		id,
+	).Scan(&evaluation.Id, &evaluation.PromptId, &evaluation.Kind, &evaluation.BenchmarkSetId, &evaluation.Status, &report, &errorMessage, &createdAtStr, &completedAtStr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if report.Valid && report.String != "" {
+		evaluation.Report = json.RawMessage(report.String)
+	}
+	evaluation.Error = errorMessage.String
+	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+		evaluation.CreatedAt = createdAt
+	}
+	if completedAtStr.Valid {
+		if completedAt, err := time.Parse("2006-01-02 15:04:05", completedAtStr.String); err == nil {
+			evaluation.CompletedAt = &completedAt
+		}
+	}
+
+	return &evaluation, nil
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Job board operations. Keyword and location filters are stored comma separated.
+
+// jobBoardColumns is the column list of job board queries, in scanJobBoard order
+const jobBoardColumns = "id, provider, board, company, keywords, locations, enabled, last_polled_at, COALESCE(last_error, ''), last_created, created_at"
+
+func scanJobBoard(row rowScanner) (*models.JobBoard, error) {
+	var board models.JobBoard
+	var keywords, locations string
+	var lastPolledAt sql.NullString
+	var createdAtStr string
+	if err := row.Scan(&board.Id, &board.Provider, &board.Board, &board.Company, &keywords, &locations,
+		&board.Enabled, &lastPolledAt, &board.LastError, &board.LastCreated, &createdAtStr); err != nil {
+		return nil, err
+	}
+	board.Keywords = splitList(keywords)
+	board.Locations = splitList(locations)
+	board.LastPolledAt = nullTimePtr(lastPolledAt)
+	board.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	return &board, nil
+}
+
+// splitList reads a comma separated list, an empty list for empty text
+func splitList(s string) []string {
+	values := []string{}
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// GetJobBoards returns the job boards, only the enabled ones if enabledOnly is set
+func GetJobBoards(enabledOnly bool) ([]models.JobBoard, error) {
+	query := "SELECT " + jobBoardColumns + " FROM job_boards"
+	if enabledOnly {
+		query += " WHERE enabled"
+	}
+	rows, err := db.Query(query + " ORDER BY provider, board")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	boards := []models.JobBoard{}
+	for rows.Next() {
+		board, err := scanJobBoard(rows)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, *board)
+	}
+	return boards, rows.Err()
+}
+
+// GetJobBoardByID returns a job board
+func GetJobBoardByID(id int) (*models.JobBoard, error) {
+	board, err := scanJobBoard(db.QueryRow("SELECT "+jobBoardColumns+" FROM job_boards WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return board, err
+}
+
+// InsertJobBoard stores a new job board; ErrDuplicate if the board is already configured
+func InsertJobBoard(board models.JobBoard) (int64, error) {
+	result, err := db.Exec(
+		"INSERT INTO job_boards (provider, board, company, keywords, locations, enabled) VALUES (?, ?, ?, ?, ?, ?)",
+		board.Provider, board.Board, board.Company, strings.Join(board.Keywords, ","), strings.Join(board.Locations, ","), board.Enabled,
+	)
+	if isDuplicateEntry(err) {
+		return 0, ErrDuplicate
+	} else if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateJobBoard replaces the settings of a job board
+func UpdateJobBoard(board models.JobBoard) error {
+	result, err := db.Exec(
+		"UPDATE job_boards SET provider = ?, board = ?, company = ?, keywords = ?, locations = ?, enabled = ? WHERE id = ?",
+		board.Provider, board.Board, board.Company, strings.Join(board.Keywords, ","), strings.Join(board.Locations, ","), board.Enabled, board.Id,
+	)
+	if isDuplicateEntry(err) {
+		return ErrDuplicate
+	} else if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetJobBoardByID(board.Id)
+		return err
+	})
+}
+
+// DeleteJobBoard removes a job board and the record of its postings; jobs created
+// from it are kept
+func DeleteJobBoard(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM job_boards WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec("DELETE FROM job_board_postings WHERE board_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordJobBoardPoll stores the outcome of a poll; pollErr is empty for successful polls
+func RecordJobBoardPoll(id int, created int, pollErr string) error {
+	_, err := db.Exec(
+		"UPDATE job_boards SET last_polled_at = CURRENT_TIMESTAMP(), last_created = ?, last_error = ? WHERE id = ?",
+		created, nullString(truncateRunes(pollErr, 1000)), id,
+	)
+	return err
+}
+
+// GetSeenBoardPostings returns the provider posting IDs a board has published already
+func GetSeenBoardPostings(boardID int) (map[string]bool, error) {
+	rows, err := db.Query("SELECT external_id FROM job_board_postings WHERE board_id = ?", boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seen[id] = true
+	}
+	return seen, rows.Err()
+}
+
+// MarkBoardPostingSeen records that a posting of a board was published
+func MarkBoardPostingSeen(boardID int, externalID string) error {
+	_, err := db.Exec("INSERT IGNORE INTO job_board_postings (board_id, external_id) VALUES (?, ?)", boardID, externalID)
+	return err
+}
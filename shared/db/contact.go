@@ -0,0 +1,254 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Contact operations
+
+// contactColumns is the column list of contact queries, in scanContact order
+const contactColumns = "c.id, c.name, c.role, c.company, c.email, c.phone, c.linkedin_url, c.notes, c.last_contacted_at, c.created_at, c.updated_at"
+
+func scanContact(row rowScanner) (*models.Contact, error) {
+	var contact models.Contact
+	var company, email, phone, linkedinURL, notes, lastContactedAt sql.NullString
+	var createdAtStr, updatedAtStr string
+
+	if err := row.Scan(&contact.Id, &contact.Name, &contact.Role, &company, &email, &phone,
+		&linkedinURL, &notes, &lastContactedAt, &createdAtStr, &updatedAtStr); err != nil {
+		return nil, err
+	}
+
+	contact.Company = company.String
+	contact.Email = email.String
+	contact.Phone = phone.String
+	contact.LinkedinURL = linkedinURL.String
+	contact.Notes = notes.String
+	if lastContactedAt.Valid {
+		if t, err := time.Parse("2006-01-02 15:04:05", lastContactedAt.String); err == nil {
+			contact.LastContactedAt = &t
+		}
+	}
+	contact.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	contact.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAtStr)
+	return &contact, nil
+}
+
+func queryContacts(query string, args ...interface{}) ([]models.Contact, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []models.Contact{}
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, *contact)
+	}
+	return contacts, rows.Err()
+}
+
+// contactArgs returns the writable columns of a contact, in insert/update order
+func contactArgs(contact models.Contact) []interface{} {
+	var lastContactedAt interface{}
+	if contact.LastContactedAt != nil {
+		lastContactedAt = contact.LastContactedAt.UTC().Format("2006-01-02 15:04:05")
+	}
+	return []interface{}{
+		contact.Name,
+		contact.Role,
+		nullString(contact.Company),
+		nullString(contact.Email),
+		nullString(contact.Phone),
+		nullString(contact.LinkedinURL),
+		nullString(contact.Notes),
+		lastContactedAt,
+	}
+}
+
+// InsertContact stores a new contact
+func InsertContact(contact models.Contact) (int64, error) {
+	result, err := db.Exec(
+		"INSERT INTO contacts (name, role, company, email, phone, linkedin_url, notes, last_contacted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		contactArgs(contact)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateContact replaces the fields of a contact
+func UpdateContact(contact models.Contact) error {
+	result, err := db.Exec(
+		"UPDATE contacts SET name = ?, role = ?, company = ?, email = ?, phone = ?, linkedin_url = ?, notes = ?, last_contacted_at = ? WHERE id = ?",
+		append(contactArgs(contact), contact.Id)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetContactByID(contact.Id)
+		return err
+	})
+}
+
+// DeleteContact removes a contact and its job links; notes about it are kept
+func DeleteContact(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM contacts WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM job_contacts WHERE contact_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE job_notes SET contact_id = NULL WHERE contact_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetContactByID returns a contact
+func GetContactByID(id int) (*models.Contact, error) {
+	contact, err := scanContact(db.QueryRow("SELECT "+contactColumns+" FROM contacts c WHERE c.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return contact, err
+}
+
+// Contact search page sizes
+const (
+	DefaultContactPageSize = 50
+	MaxContactPageSize     = 200
+)
+
+// ContactQuery filters the contact list; empty fields do not filter
+type ContactQuery struct {
+	Search  string // Part of the name, email, company or notes
+	Company string
+	Role    models.ContactRole
+	Limit   int // Defaults to 50, at most 200
+}
+
+// SearchContacts returns the contacts matching q, ordered by name
+func SearchContacts(q ContactQuery) ([]models.Contact, error) {
+	var conditions []string
+	var args []interface{}
+	if q.Search != "" {
+		pattern := "%" + escapeLike(q.Search) + "%"
+		conditions = append(conditions, "(c.name LIKE ? OR c.email LIKE ? OR c.company LIKE ? OR c.notes LIKE ?)")
+		args = append(args, pattern, pattern, pattern, pattern)
+	}
+	if q.Company != "" {
+		conditions = append(conditions, "c.company = ?")
+		args = append(args, q.Company)
+	}
+	if q.Role != "" {
+		conditions = append(conditions, "c.role = ?")
+		args = append(args, q.Role)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultContactPageSize
+	} else if limit > MaxContactPageSize {
+		limit = MaxContactPageSize
+	}
+
+	return queryContacts("SELECT "+contactColumns+" FROM contacts c"+where+" ORDER BY c.name, c.id LIMIT ?", append(args, limit)...)
+}
+
+// LinkJobContact links a contact to a job; linking twice is not an error
+func LinkJobContact(jobID, contactID int) error {
+	return linkJobContact(db, jobID, contactID)
+}
+
+// queryExecer is satisfied by both *sql.DB and *sql.Tx
+type queryExecer interface {
+	execer
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func linkJobContact(ex queryExecer, jobID, contactID int) error {
+	var found int
+	err := ex.QueryRow(
+		"SELECT (SELECT COUNT(*) FROM jobs WHERE id = ?) + (SELECT COUNT(*) FROM contacts WHERE id = ?)",
+		jobID, contactID,
+	).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found < 2 {
+		return ErrNotFound
+	}
+
+	_, err = ex.Exec("INSERT IGNORE INTO job_contacts (job_id, contact_id) VALUES (?, ?)", jobID, contactID)
+	return err
+}
+
+// UnlinkJobContact removes the link between a job and a contact
+func UnlinkJobContact(jobID, contactID int) error {
+	result, err := db.Exec("DELETE FROM job_contacts WHERE job_id = ? AND contact_id = ?", jobID, contactID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetJobContacts returns the contacts linked to a job, in link order
+func GetJobContacts(jobID int) ([]models.Contact, error) {
+	return queryContacts(
+		"SELECT "+contactColumns+" FROM contacts c JOIN job_contacts jc ON jc.contact_id = c.id WHERE jc.job_id = ? ORDER BY jc.created_at, c.id",
+		jobID,
+	)
+}
+
+// GetJobHiringManager returns the hiring manager linked to a job, the most recently
+// linked one if there are several
+func GetJobHiringManager(jobID int) (*models.Contact, error) {
+	contact, err := scanContact(db.QueryRow(
+		"SELECT "+contactColumns+" FROM contacts c JOIN job_contacts jc ON jc.contact_id = c.id "+
+			"WHERE jc.job_id = ? AND c.role = ? ORDER BY jc.created_at DESC, c.id DESC LIMIT 1",
+		jobID, models.ContactRoleHiringManager,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return contact, err
+}
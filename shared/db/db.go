@@ -1,21 +1,29 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/hirepilot/shared/models"
 )
 
 // Custom error types
 var (
-	ErrNotFound  = errors.New("record not found")
-	ErrDuplicate = errors.New("record already exists")
+	ErrNotFound        = errors.New("record not found")
+	ErrDuplicate       = errors.New("record already exists")
+	ErrWrongPromptKind = errors.New("prompt kind does not match")
+	ErrPromptInUse     = errors.New("prompt is still in use")
+	ErrPromptArchived  = errors.New("prompt is archived")
+	ErrInvalidStatus   = errors.New("job status transition not allowed")
+	ErrReminderClosed  = errors.New("reminder is dismissed or expired")
 )
 
 // Using shared models package for Job, Prompt, and Feature types
@@ -48,8 +56,11 @@ func InitDB() *sql.DB {
 			log.Fatalf("DB ping error: %v", err)
 		}
 
-		// Create all necessary tables
+		// Create all necessary tables. Every service runs this at startup, so the
+		// migrations are serialised with a named lock held on one connection.
+		unlock := lockSchema()
 		createTables()
+		unlock()
 
 		log.Println("Database initialized successfully")
 	})
@@ -110,6 +121,468 @@ func createTables() {
 		log.Fatalf("Prompts table creation error: %v", err)
 	}
 
+	// Create prompt versions table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS prompt_versions (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			prompt_id INT NOT NULL,
+			version INT NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			prompt TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_prompt_version (prompt_id, version)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Prompt versions table creation error: %v", err)
+	}
+
+	// Create benchmark tables used by the prompt evaluation harness
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS benchmark_sets (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			description TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Benchmark sets table creation error: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS benchmark_jobs (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			set_id INT NOT NULL,
+			title VARCHAR(255),
+			company VARCHAR(255),
+			description TEXT,
+			keywords TEXT,
+			INDEX idx_benchmark_jobs_set (set_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Benchmark jobs table creation error: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS prompt_evaluations (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			prompt_id INT NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			benchmark_set_id INT NOT NULL,
+			status ENUM('pending','running','completed','failed') NOT NULL DEFAULT 'pending',
+			report MEDIUMTEXT,
+			error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			completed_at TIMESTAMP NULL
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Prompt evaluations table creation error: %v", err)
+	}
+
+	// Create prompt experiment tables
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS experiments (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			status ENUM('active','stopped') NOT NULL DEFAULT 'active',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			stopped_at TIMESTAMP NULL
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Experiments table creation error: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS experiment_variants (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			experiment_id INT NOT NULL,
+			prompt_id INT NOT NULL,
+			weight INT NOT NULL DEFAULT 1,
+			INDEX idx_experiment_variants_experiment (experiment_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Experiment variants table creation error: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS experiment_assignments (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			experiment_id INT NOT NULL,
+			variant_id INT NOT NULL,
+			job_id INT NOT NULL,
+			input_tokens INT NOT NULL DEFAULT 0,
+			output_tokens INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_experiment_job (experiment_id, job_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Experiment assignments table creation error: %v", err)
+	}
+
+	// Create job status history table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_status_history (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			job_id INT NOT NULL,
+			from_status VARCHAR(32) NULL,
+			to_status VARCHAR(32) NOT NULL,
+			note TEXT NULL,
+			changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			INDEX idx_job_status_history_job (job_id, changed_at)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job status history table creation error: %v", err)
+	}
+
+	// Create interviews table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS interviews (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			job_id INT NOT NULL,
+			round VARCHAR(32) NOT NULL,
+			scheduled_at DATETIME NOT NULL,
+			duration_minutes INT NULL,
+			interviewers TEXT NULL,
+			location VARCHAR(512) NULL,
+			outcome VARCHAR(16) NOT NULL DEFAULT 'pending',
+			feedback TEXT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			INDEX idx_interviews_job (job_id),
+			INDEX idx_interviews_scheduled (scheduled_at)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Interviews table creation error: %v", err)
+	}
+
+	// Create job notes table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_notes (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			job_id INT NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			INDEX idx_job_notes_job (job_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job notes table creation error: %v", err)
+	}
+
+	// Create contacts table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS contacts (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			role VARCHAR(32) NOT NULL DEFAULT 'other',
+			company VARCHAR(255) NULL,
+			email VARCHAR(255) NULL,
+			phone VARCHAR(64) NULL,
+			linkedin_url VARCHAR(512) NULL,
+			notes TEXT NULL,
+			last_contacted_at DATETIME NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			INDEX idx_contacts_company (company),
+			INDEX idx_contacts_name (name)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Contacts table creation error: %v", err)
+	}
+
+	// Create job contacts table, linking contacts to the jobs they are involved in
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_contacts (
+			job_id INT NOT NULL,
+			contact_id INT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (job_id, contact_id),
+			INDEX idx_job_contacts_contact (contact_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job contacts table creation error: %v", err)
+	}
+
+	// Create job activities table, the timeline of each job
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_activities (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			job_id INT NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			summary VARCHAR(512) NOT NULL,
+			detail TEXT NULL,
+			ref_id INT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			INDEX idx_job_activities_job (job_id, created_at)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job activities table creation error: %v", err)
+	}
+
+	// Create reminder rules table
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS reminder_rules (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			status VARCHAR(32) NULL,
+			delay_hours INT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Reminder rules table creation error: %v", err)
+	}
+
+	// Insert default reminder rules if not exists
+	_, err = db.Exec(`
+		INSERT IGNORE INTO reminder_rules (id, name, kind, status, delay_hours) VALUES
+			(1, 'Follow up after applying', 'status_stale', 'applied', 168),
+			(2, 'Interview tomorrow', 'interview_upcoming', NULL, 24)
+	`)
+	if err != nil {
+		log.Fatalf("Default reminder rules insertion error: %v", err)
+	}
+
+	// Create reminders table; a rule creates one reminder per source and due time
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS reminders (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			rule_id INT NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			job_id INT NOT NULL,
+			ref_id INT NOT NULL,
+			message VARCHAR(512) NOT NULL,
+			due_at DATETIME NOT NULL,
+			status VARCHAR(16) NOT NULL DEFAULT 'pending',
+			snoozed_until DATETIME NULL,
+			notified_at DATETIME NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_reminders_source (rule_id, ref_id, due_at),
+			INDEX idx_reminders_status (status, due_at),
+			INDEX idx_reminders_job (job_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Reminders table creation error: %v", err)
+	}
+
+	// Create job expiry policies table, one row per policy kind
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_expiry_policies (
+			kind VARCHAR(32) PRIMARY KEY,
+			after_days INT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job expiry policies table creation error: %v", err)
+	}
+
+	// Insert default job expiry policies if not exists
+	_, err = db.Exec(`
+		INSERT IGNORE INTO job_expiry_policies (kind, after_days) VALUES
+			('close_unapplied', 30),
+			('ghost_unanswered', 30)
+	`)
+	if err != nil {
+		log.Fatalf("Default job expiry policies insertion error: %v", err)
+	}
+
+	// Create tags table, user-defined labels of jobs
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS tags (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(64) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_tags_name (name)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Tags table creation error: %v", err)
+	}
+
+	// Create job tags table, linking tags to the jobs they label
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_tags (
+			job_id INT NOT NULL,
+			tag_id INT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (job_id, tag_id),
+			INDEX idx_job_tags_tag (tag_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job tags table creation error: %v", err)
+	}
+
+	// Create job boards table, public ATS boards pulled by the board poller
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_boards (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			provider VARCHAR(32) NOT NULL,
+			board VARCHAR(191) NOT NULL,
+			company VARCHAR(255) NOT NULL DEFAULT '',
+			keywords TEXT NOT NULL,
+			locations TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			last_polled_at TIMESTAMP NULL,
+			last_error TEXT,
+			last_created INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_job_boards_board (provider, board)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job boards table creation error: %v", err)
+	}
+
+	// Create job board postings table, the postings each board has published
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_board_postings (
+			board_id INT NOT NULL,
+			external_id VARCHAR(191) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (board_id, external_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job board postings table creation error: %v", err)
+	}
+
+	// Create job feeds table, RSS and Atom feeds polled by the feed watcher
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_feeds (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			url VARCHAR(1024) NOT NULL,
+			url_hash CHAR(40) NOT NULL,
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			company VARCHAR(255) NOT NULL DEFAULT '',
+			fetch_description BOOLEAN NOT NULL DEFAULT FALSE,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			etag VARCHAR(255),
+			last_modified VARCHAR(64),
+			last_polled_at TIMESTAMP NULL,
+			last_error TEXT,
+			last_created INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_job_feeds_url (url_hash)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job feeds table creation error: %v", err)
+	}
+
+	// Create job feed items table, the items each feed has published
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_feed_items (
+			feed_id INT NOT NULL,
+			guid TEXT NOT NULL,
+			guid_hash CHAR(40) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (feed_id, guid_hash)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job feed items table creation error: %v", err)
+	}
+
+	// Create mail messages table, the messages the mail ingester has processed
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS mail_messages (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			message_id TEXT NOT NULL,
+			message_key CHAR(40) NOT NULL,
+			sender VARCHAR(255) NOT NULL DEFAULT '',
+			subject VARCHAR(512) NOT NULL DEFAULT '',
+			kind VARCHAR(32) NOT NULL,
+			template VARCHAR(32),
+			postings INT NOT NULL DEFAULT 0,
+			received_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_mail_messages_key (message_key)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Mail messages table creation error: %v", err)
+	}
+
+	// Create status proposals table, status changes suggested by messages about applications
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS status_proposals (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			job_id INT NOT NULL,
+			mail_message_id INT NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			from_status VARCHAR(32) NOT NULL,
+			to_status VARCHAR(32) NOT NULL,
+			confidence DOUBLE NOT NULL,
+			reason VARCHAR(512) NOT NULL DEFAULT '',
+			state VARCHAR(16) NOT NULL DEFAULT 'pending',
+			automatic BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			decided_at TIMESTAMP NULL,
+			INDEX idx_status_proposals_state (state, created_at),
+			INDEX idx_status_proposals_job (job_id)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Status proposals table creation error: %v", err)
+	}
+
+	// Create job skills table, the requirements read from job descriptions
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_skills (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			job_id INT NOT NULL,
+			kind VARCHAR(32) NOT NULL,
+			name VARCHAR(128) NOT NULL,
+			category VARCHAR(32) NOT NULL DEFAULT '',
+			years INT NULL,
+			required BOOLEAN NOT NULL DEFAULT TRUE,
+			detail VARCHAR(255) NOT NULL DEFAULT '',
+			source VARCHAR(16) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY uq_job_skills (job_id, kind, name),
+			INDEX idx_job_skills_name (name)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Job skills table creation error: %v", err)
+	}
+
+	// Create API tokens table, personal tokens of clients such as browser extensions
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			prefix VARCHAR(16) NOT NULL,
+			token_hash CHAR(64) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_used_at TIMESTAMP NULL,
+			UNIQUE KEY uq_api_tokens_hash (token_hash)
+		)
+	`)
+	if err != nil {
+		log.Fatalf("API tokens table creation error: %v", err)
+	}
+
 	// Create features table
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS features (
@@ -315,23 +788,217 @@ Read like it was written by a real person with intent and confidence`
 		log.Fatalf("Default prompts insertion error: %v", err)
 	}
 
+	// Columns added after the initial schema
+	ensureColumn("prompts", "version", "INT NOT NULL DEFAULT 1")
+	ensureColumn("jobs", "cv_prompt_version_id", "INT NULL")
+	ensureColumn("jobs", "cover_prompt_version_id", "INT NULL")
+	ensureColumn("jobs", "score_prompt_version_id", "INT NULL")
+
+	// Prompt kinds replace the per-kind default flags. Existing prompts take the kind of the flag
+	// they are default for; prompts without a flag cannot be classified and start as CV prompts.
+	ensureColumn("prompts", "kind", "VARCHAR(32) NULL")
+	ensureColumn("prompts", "is_default", "BOOLEAN NOT NULL DEFAULT FALSE")
+	ensureColumn("prompts", "archived", "BOOLEAN NOT NULL DEFAULT FALSE")
+	_, err = db.Exec(`
+		UPDATE prompts SET
+			kind = CASE
+				WHEN cvGenerationDefault THEN 'cv'
+				WHEN coverGenerationDefault THEN 'cover'
+				WHEN scoreGenerationDefault THEN 'score'
+				ELSE 'cv'
+			END,
+			is_default = (cvGenerationDefault OR coverGenerationDefault OR scoreGenerationDefault)
+		WHERE kind IS NULL
+	`)
+	if err != nil {
+		log.Fatalf("Prompt kinds backfill error: %v", err)
+	}
+
+	// Keep only the oldest default of each kind
+	_, err = db.Exec(`
+		UPDATE prompts p
+		JOIN (SELECT kind, MIN(id) AS keep_id FROM prompts WHERE is_default GROUP BY kind) d ON p.kind = d.kind
+		SET p.is_default = FALSE
+		WHERE p.is_default AND p.id <> d.keep_id
+	`)
+	if err != nil {
+		log.Fatalf("Prompt defaults cleanup error: %v", err)
+	}
+
+	// Jobs are deduplicated by the posting they were created from
+	ensureColumn("jobs", "source", "VARCHAR(64) NULL")
+	ensureColumn("jobs", "external_id", "VARCHAR(191) NULL")
+	ensureColumn("jobs", "location", "VARCHAR(255) NULL")
+	ensureColumn("jobs", "workplace_type", "VARCHAR(16) NULL")
+	ensureColumn("jobs", "employment_type", "VARCHAR(16) NULL")
+	ensureColumn("jobs", "seniority", "VARCHAR(16) NULL")
+	ensureColumn("jobs", "salary_min", "DOUBLE NULL")
+	ensureColumn("jobs", "salary_max", "DOUBLE NULL")
+	ensureColumn("jobs", "salary_currency", "CHAR(3) NULL")
+	ensureColumn("jobs", "posted_at", "DATETIME NULL")
+	backfillJobPostingKeys()
+	ensureIndex("jobs", "uq_jobs_source_external_id", "UNIQUE", "source, external_id")
+
+	// The application pipeline outgrew the original status ENUM
+	migrateJobStatusColumn()
+	backfillJobStatusHistory()
+	backfillJobActivities()
+
+	// Notes can be about a contact
+	ensureColumn("job_notes", "contact_id", "INT NULL")
+
+	// Prompts can replace the default of their kind for jobs with a tag
+	ensureColumn("prompts", "tag", "VARCHAR(64) NULL")
+
+	// Messages about an application are matched to its job
+	ensureColumn("mail_messages", "job_id", "INT NULL")
+
+	// Descriptions are stored normalised, next to the description as received
+	ensureColumn("jobs", "raw_description", "MEDIUMTEXT NULL")
+	backfillNormalizedDescriptions()
+
+	// Requirements are read from descriptions once; jobs without the time are pending
+	ensureColumn("jobs", "skills_extracted_at", "TIMESTAMP NULL")
+
+	// A job creation is handled once the generation was requested, or is off. New jobs
+	// are inserted without the time; jobs stored before the column count as handled.
+	ensureColumn("jobs", "creation_handled_at", "TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP")
+
+	// Job list search and sorting
+	ensureIndex("jobs", "ft_jobs_search", "FULLTEXT", "title, company, description")
+	ensureIndex("jobs", "idx_jobs_created_at", "", "created_at, id")
+
+	// Every prompt needs at least its current version in the history
+	_, err = db.Exec(`
+		INSERT IGNORE INTO prompt_versions (prompt_id, version, name, prompt)
+		SELECT id, version, name, prompt FROM prompts
+	`)
+	if err != nil {
+		log.Fatalf("Prompt versions backfill error: %v", err)
+	}
+
 	log.Println("All database tables created successfully")
 }
 
-// Job-related database operations
-func InsertJob(title, company, link, description string) (int64, error) {
-	result, err := db.Exec(
-		"INSERT INTO jobs (title, company, link, status, cvGenerated, cv, description, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
-		title, company, link, "open", false, "", description, time.Now(),
-	)
+// schemaLockName is the MySQL named lock held while the schema is migrated
+const schemaLockName = "hirepilot_schema"
+
+// MySQL error numbers for schema changes that another service already made
+const (
+	mysqlDuplicateColumn = 1060
+	mysqlDuplicateKey    = 1061
+)
+
+// lockSchema waits for the schema lock and returns the function releasing it
+func lockSchema() func() {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
 	if err != nil {
-		return 0, err
+		log.Fatalf("Schema lock connection error: %v", err)
 	}
 
-	return result.LastInsertId()
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 300)", schemaLockName).Scan(&acquired); err != nil {
+		conn.Close()
+		log.Fatalf("Schema lock error: %v", err)
+	}
+	if acquired.Int64 != 1 {
+		conn.Close()
+		log.Fatalf("Schema lock error: timed out waiting for %s", schemaLockName)
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", schemaLockName); err != nil {
+			log.Printf("Schema lock release error: %v", err)
+		}
+		conn.Close()
+	}
 }
 
-func GetJobByID(id int) (*models.Job, error) {
+// isMySQLError reports whether err is the MySQL error with the given number
+func isMySQLError(err error, number uint16) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == number
+}
+
+// checkUpdated checks the result of an UPDATE by id. MySQL reports rows left unchanged
+// as unaffected, so exists, typically a Get…ByID returning ErrNotFound, tells those
+// apart from missing rows.
+func checkUpdated(result sql.Result, exists func() error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return exists()
+	}
+	return nil
+}
+
+// ensureColumn adds a column to an existing table if it is missing
+func ensureColumn(table, column, definition string) {
+	var count int
+	err := db.QueryRow(
+		"SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?",
+		table, column,
+	).Scan(&count)
+	if err != nil {
+		log.Fatalf("Column lookup error for %s.%s: %v", table, column, err)
+	}
+	if count > 0 {
+		return
+	}
+
+	if _, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition); err != nil {
+		if isMySQLError(err, mysqlDuplicateColumn) {
+			return
+		}
+		log.Fatalf("Column creation error for %s.%s: %v", table, column, err)
+	}
+	log.Printf("Added column %s.%s", table, column)
+}
+
+// ensureIndex adds an index to an existing table if it is missing.
+// kind is "" for a plain index, or UNIQUE or FULLTEXT.
+func ensureIndex(table, index, kind, columns string) {
+	var count int
+	err := db.QueryRow(
+		"SELECT COUNT(*) FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?",
+		table, index,
+	).Scan(&count)
+	if err != nil {
+		log.Fatalf("Index lookup error for %s.%s: %v", table, index, err)
+	}
+	if count > 0 {
+		return
+	}
+
+	if _, err := db.Exec("ALTER TABLE " + table + " ADD " + strings.TrimSpace(kind+" INDEX "+index) + " (" + columns + ")"); err != nil {
+		if isMySQLError(err, mysqlDuplicateKey) {
+			return
+		}
+		log.Fatalf("Index creation error for %s.%s: %v", table, index, err)
+	}
+	log.Printf("Added index %s.%s", table, index)
+}
+
+// Job-related database operations
+
+// InsertJob stores a job as given, without deduplication; see UpsertJob
+func InsertJob(p models.JobPosting) (int64, error) {
+	return insertJob(db, p, p.Source, p.ExternalId, p.Link)
+}
+
+// jobColumns is the column list shared by all job queries, in scanJob order
+const jobColumns = "id, title, company, link, status, cvGenerated, cv, description, raw_description, score, created_at, applied_at, cover_letter, cv_prompt_version_id, cover_prompt_version_id, score_prompt_version_id, source, external_id, " + jobDetailColumns
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanJob scans a row selected with jobColumns into a Job
+func scanJob(row rowScanner) (*models.Job, error) {
 	var job models.Job
 	var createdAtStr string
 	var appliedAtStr sql.NullString
@@ -339,18 +1006,16 @@ func GetJobByID(id int) (*models.Job, error) {
 	var companyStr sql.NullString
 	var linkStr sql.NullString
 	var cvStr sql.NullString
-	var descriptionStr sql.NullString
+	var descriptionStr, rawDescriptionStr sql.NullString
 	var coverLetterStr sql.NullString
+	var cvPromptVersionID, coverPromptVersionID, scorePromptVersionID sql.NullInt64
+	var sourceStr, externalIDStr sql.NullString
+	var details jobDetailScan
 
-	err := db.QueryRow(
-		"SELECT id, title, company, link, status, cvGenerated, cv, description, score, created_at, applied_at, cover_letter FROM jobs WHERE id = ?",
-		id,
-	).Scan(&job.Id, &titleStr, &companyStr, &linkStr, &job.Status, &job.CvGenerated, &cvStr, &descriptionStr, &job.Score, &createdAtStr, &appliedAtStr, &coverLetterStr)
-
+	dest := []interface{}{&job.Id, &titleStr, &companyStr, &linkStr, &job.Status, &job.CvGenerated, &cvStr, &descriptionStr, &rawDescriptionStr, &job.Score, &createdAtStr, &appliedAtStr, &coverLetterStr,
+		&cvPromptVersionID, &coverPromptVersionID, &scorePromptVersionID, &sourceStr, &externalIDStr}
+	err := row.Scan(append(dest, details.dest()...)...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrNotFound
-		}
 		return nil, err
 	}
 
@@ -370,6 +1035,7 @@ func GetJobByID(id int) (*models.Job, error) {
 	if descriptionStr.Valid {
 		job.Description = descriptionStr.String
 	}
+	job.RawDescription = rawDescriptionStr.String
 
 	// Parse created_at
 	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
@@ -388,20 +1054,51 @@ func GetJobByID(id int) (*models.Job, error) {
 		job.CoverLetter = coverLetterStr.String
 	}
 
+	job.CvPromptVersionId = nullIntPtr(cvPromptVersionID)
+	job.CoverPromptVersionId = nullIntPtr(coverPromptVersionID)
+	job.ScorePromptVersionId = nullIntPtr(scorePromptVersionID)
+	job.Source = sourceStr.String
+	job.ExternalId = externalIDStr.String
+	details.apply(&job.JobDetails)
+
 	return &job, nil
 }
 
+// nullIntPtr converts a nullable integer column into an optional int
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+func GetJobByID(id int) (*models.Job, error) {
+	job, err := scanJob(db.QueryRow("SELECT "+jobColumns+" FROM jobs WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if job.Tags, err = GetJobTags(id); err != nil {
+		return nil, err
+	}
+	if job.Skills, err = GetJobSkills(id); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
 func GetJobsByStatus(status string) ([]models.Job, error) {
-	var query string
 	var rows *sql.Rows
 	var err error
 
 	if status != "" {
-		query = "SELECT id, title, company, link, status, cvGenerated, cv, description, score, created_at, applied_at, cover_letter FROM jobs WHERE status = ?"
-		rows, err = db.Query(query, status)
+		rows, err = db.Query("SELECT "+jobColumns+" FROM jobs WHERE status = ?", status)
 	} else {
-		query = "SELECT id, title, company, link, status, cvGenerated, cv, description, score, created_at, applied_at, cover_letter FROM jobs"
-		rows, err = db.Query(query)
+		rows, err = db.Query("SELECT " + jobColumns + " FROM jobs")
 	}
 
 	if err != nil {
@@ -412,76 +1109,21 @@ func GetJobsByStatus(status string) ([]models.Job, error) {
 
 	var jobs []models.Job
 	for rows.Next() {
-		var job models.Job
-		var createdAtStr string
-		var appliedAtStr sql.NullString
-		var titleStr sql.NullString
-		var companyStr sql.NullString
-		var linkStr sql.NullString
-		var cvStr sql.NullString
-		var descriptionStr sql.NullString
-		var coverLetterStr sql.NullString
-
-		if err := rows.Scan(&job.Id, &titleStr, &companyStr, &linkStr, &job.Status, &job.CvGenerated, &cvStr, &descriptionStr, &job.Score, &createdAtStr, &appliedAtStr, &coverLetterStr); err != nil {
+		job, err := scanJob(rows)
+		if err != nil {
 			log.Printf("Database scan error in GetJobsByStatus: %v", err)
 			return nil, err
 		}
-
-		// Handle potentially NULL string fields
-		if titleStr.Valid {
-			job.Title = titleStr.String
-		}
-		if companyStr.Valid {
-			job.Company = companyStr.String
-		}
-		if linkStr.Valid {
-			job.Link = linkStr.String
-		}
-		if cvStr.Valid {
-			job.Cv = cvStr.String
-		}
-		if descriptionStr.Valid {
-			job.Description = descriptionStr.String
-		}
-
-		// Parse created_at
-		if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
-			job.CreatedAt = createdAt
-		}
-
-		// Parse applied_at if not null
-		if appliedAtStr.Valid {
-			if appliedAt, err := time.Parse("2006-01-02 15:04:05", appliedAtStr.String); err == nil {
-				job.AppliedAt = &appliedAt
-			}
-		}
-
-		// Handle cover_letter if not null
-		if coverLetterStr.Valid {
-			job.CoverLetter = coverLetterStr.String
-		}
-
-		jobs = append(jobs, job)
+		jobs = append(jobs, *job)
 	}
 
 	return jobs, nil
 }
 
-func UpdateJobStatus(id int, status string) error {
-	if status == "applied" {
-		_, err := db.Exec("UPDATE jobs SET applied_at = CURRENT_TIMESTAMP() WHERE id = ?", id)
-		if err != nil {
-			return err
-		}
-	}
-
-	_, err := db.Exec("UPDATE jobs SET status = ? WHERE id = ?", status, id)
-	return err
-}
-
 func GetAppliedJobsToday() (int, error) {
 	var count int
-	err := db.QueryRow("SELECT count(*) FROM jobs WHERE status = 'applied' AND DATE(applied_at) = CURDATE()").Scan(&count)
+	// Applications that already moved further down the pipeline still count
+	err := db.QueryRow("SELECT count(*) FROM jobs WHERE applied_at IS NOT NULL AND DATE(applied_at) = CURDATE()").Scan(&count)
 	return count, err
 }
 
@@ -523,29 +1165,64 @@ func GetAllFeatures() ([]models.Feature, error) {
 }
 
 // Prompt-related database operations
-func InsertPrompt(name, prompt string, cvDefault, scoreDefault bool) (int64, error) {
-	return InsertPromptWithCover(name, prompt, cvDefault, scoreDefault, false)
+
+// promptColumns is the column list shared by all prompt queries, in scanPrompt order
+const promptColumns = "id, name, prompt, kind, is_default, version, archived, COALESCE(tag, '')"
+
+// scanPrompt scans a row selected with promptColumns into a Prompt
+func scanPrompt(row rowScanner) (*models.Prompt, error) {
+	var prompt models.Prompt
+	err := row.Scan(&prompt.Id, &prompt.Name, &prompt.Prompt, &prompt.Kind, &prompt.IsDefault, &prompt.Version, &prompt.Archived, &prompt.Tag)
+	if err != nil {
+		return nil, err
+	}
+	prompt.CvGenerationDefault = prompt.IsDefault && prompt.Kind == models.PromptKindCV
+	prompt.ScoreGenerationDefault = prompt.IsDefault && prompt.Kind == models.PromptKindScore
+	prompt.CoverGenerationDefault = prompt.IsDefault && prompt.Kind == models.PromptKindCover
+	return &prompt, nil
 }
 
-func InsertPromptWithCover(name, prompt string, cvDefault, scoreDefault, coverDefault bool) (int64, error) {
-	result, err := db.Exec(
-		"INSERT INTO prompts (name, prompt, cvGenerationDefault, scoreGenerationDefault, coverGenerationDefault) VALUES (?, ?, ?, ?, ?)",
-		name, prompt, cvDefault, scoreDefault, coverDefault,
+// InsertPrompt inserts a prompt and records it as version 1 in the prompt history.
+// A default prompt replaces the current default of its kind; a tag is optional.
+func InsertPrompt(name, prompt string, kind models.PromptKind, isDefault bool, tag string) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if isDefault {
+		if err := clearDefaultPrompt(tx, kind); err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := tx.Exec(
+		"INSERT INTO prompts (name, prompt, kind, is_default, tag, version) VALUES (?, ?, ?, ?, ?, 1)",
+		name, prompt, kind, isDefault, nullString(tag),
 	)
 	if err != nil {
 		return 0, err
 	}
 
-	return result.LastInsertId()
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertPromptVersion(tx, int(id), 1, name, prompt); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return id, nil
 }
 
 func GetPromptByID(id int) (*models.Prompt, error) {
-	var prompt models.Prompt
-	err := db.QueryRow(
-		"SELECT id, name, prompt, cvGenerationDefault, scoreGenerationDefault, coverGenerationDefault FROM prompts WHERE id = ?",
-		id,
-	).Scan(&prompt.Id, &prompt.Name, &prompt.Prompt, &prompt.CvGenerationDefault, &prompt.ScoreGenerationDefault, &prompt.CoverGenerationDefault)
-
+	prompt, err := scanPrompt(db.QueryRow("SELECT "+promptColumns+" FROM prompts WHERE id = ?", id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
@@ -553,11 +1230,34 @@ func GetPromptByID(id int) (*models.Prompt, error) {
 		return nil, err
 	}
 
-	return &prompt, nil
+	return prompt, nil
 }
 
-func GetAllPrompts() ([]models.Prompt, error) {
-	rows, err := db.Query("SELECT id, name, prompt, cvGenerationDefault, scoreGenerationDefault, coverGenerationDefault FROM prompts")
+// GetPromptForKind gets a prompt that is about to be used for the given kind.
+// ErrWrongPromptKind is returned when the prompt was written for another kind.
+func GetPromptForKind(id int, kind models.PromptKind) (*models.Prompt, error) {
+	prompt, err := GetPromptByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if prompt.Kind != kind {
+		return nil, fmt.Errorf("%w: prompt %d is a %s prompt, not a %s prompt", ErrWrongPromptKind, id, prompt.Kind, kind)
+	}
+	return prompt, nil
+}
+
+// GetAllPrompts lists prompts grouped by kind, default first; archived prompts only when asked for
+func GetAllPrompts(includeArchived bool) ([]models.Prompt, error) {
+	return queryPrompts("SELECT "+promptColumns+" FROM prompts WHERE (archived = FALSE OR ?) ORDER BY kind, is_default DESC, id", includeArchived)
+}
+
+// GetPromptsByKind gets the prompts of one kind, default first; archived prompts only when asked for
+func GetPromptsByKind(kind models.PromptKind, includeArchived bool) ([]models.Prompt, error) {
+	return queryPrompts("SELECT "+promptColumns+" FROM prompts WHERE kind = ? AND (archived = FALSE OR ?) ORDER BY is_default DESC, id", kind, includeArchived)
+}
+
+func queryPrompts(query string, args ...interface{}) ([]models.Prompt, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -565,86 +1265,240 @@ func GetAllPrompts() ([]models.Prompt, error) {
 
 	var prompts []models.Prompt
 	for rows.Next() {
-		var prompt models.Prompt
-		if err := rows.Scan(&prompt.Id, &prompt.Name, &prompt.Prompt, &prompt.CvGenerationDefault, &prompt.ScoreGenerationDefault, &prompt.CoverGenerationDefault); err != nil {
+		prompt, err := scanPrompt(rows)
+		if err != nil {
 			return nil, err
 		}
-		prompts = append(prompts, prompt)
+		prompts = append(prompts, *prompt)
 	}
 
 	return prompts, nil
 }
 
-func UpdatePrompt(id int, name, promptText string, cvDefault, scoreDefault bool) error {
-	_, err := db.Exec(
-		"UPDATE prompts SET name = ?, prompt = ?, cvGenerationDefault = ?, scoreGenerationDefault = ? WHERE id = ?",
-		name, promptText, cvDefault, scoreDefault, id,
-	)
-	return err
+// UpdatePrompt updates a prompt; making it the default replaces the current default of its kind.
+// The default itself cannot stop being the default or change kind; that returns ErrPromptInUse.
+func UpdatePrompt(id int, name, promptText string, kind models.PromptKind, isDefault bool, tag string) error {
+	return updatePrompt(id, name, promptText, func(tx *sql.Tx) error {
+		var currentKind models.PromptKind
+		var wasDefault, archived bool
+		if err := tx.QueryRow("SELECT kind, is_default, archived FROM prompts WHERE id = ?", id).Scan(&currentKind, &wasDefault, &archived); err != nil {
+			return err
+		}
+		// The default only moves by promoting another prompt of its kind, see SetDefaultPrompt
+		if wasDefault && (!isDefault || kind != currentKind) {
+			return fmt.Errorf("%w: prompt %d is the default of its kind", ErrPromptInUse, id)
+		}
+		if isDefault {
+			if archived {
+				return ErrPromptArchived
+			}
+			if err := clearDefaultPrompt(tx, kind); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec("UPDATE prompts SET kind = ?, is_default = ?, tag = ? WHERE id = ?", kind, isDefault, nullString(tag), id)
+		return err
+	})
 }
 
-// UpdatePromptWithCover updates a prompt including cover generation default
-func UpdatePromptWithCover(id int, name, promptText string, cvDefault, scoreDefault, coverDefault bool) error {
-	_, err := db.Exec(
-		"UPDATE prompts SET name = ?, prompt = ?, cvGenerationDefault = ?, scoreGenerationDefault = ?, coverGenerationDefault = ? WHERE id = ?",
-		name, promptText, cvDefault, scoreDefault, coverDefault, id,
-	)
-	return err
+// SetDefaultPrompt makes a prompt the only default of its kind
+func SetDefaultPrompt(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var kind models.PromptKind
+	var archived bool
+	err = tx.QueryRow("SELECT kind, archived FROM prompts WHERE id = ? FOR UPDATE", id).Scan(&kind, &archived)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if archived {
+		return ErrPromptArchived
+	}
+
+	if err := clearDefaultPrompt(tx, kind); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE prompts SET is_default = TRUE WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// UpdateJobCV updates the CV and cvGenerated status for a job
-func UpdateJobCV(jobID int, cv string) error {
-	_, err := db.Exec("UPDATE jobs SET cv = ?, cvGenerated = TRUE WHERE id = ?", cv, jobID)
-	return err
+// ArchivePrompt hides a prompt from listings and selection, or restores it.
+// A default prompt or one used by an active experiment cannot be archived.
+func ArchivePrompt(id int, archived bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if archived {
+		if err := checkPromptUnused(tx, id); err != nil {
+			return err
+		}
+	} else if err := lockPrompt(tx, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE prompts SET archived = ? WHERE id = ?", archived, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// UpdateJobCoverLetter updates the cover letter for a job
-func UpdateJobCoverLetter(jobID int, coverLetter string) error {
-	_, err := db.Exec("UPDATE jobs SET cover_letter = ? WHERE id = ?", coverLetter, jobID)
+// DeletePrompt removes a prompt. A default prompt or one used by an active experiment cannot be deleted.
+// The version history is kept since generated jobs reference the versions they were created with.
+func DeletePrompt(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkPromptUnused(tx, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM prompts WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CheckPromptUnused returns ErrPromptInUse when a prompt cannot be deleted or archived right now
+func CheckPromptUnused(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	return checkPromptUnused(tx, id)
+}
+
+// lockPrompt locks a prompt row for the rest of the transaction
+func lockPrompt(tx *sql.Tx, id int) error {
+	var promptID int
+	err := tx.QueryRow("SELECT id FROM prompts WHERE id = ? FOR UPDATE", id).Scan(&promptID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
 	return err
 }
 
-// GetDefaultCVPrompt gets the default CV generation prompt
-func GetDefaultCVPrompt() (*models.Prompt, error) {
-	var prompt models.Prompt
-	err := db.QueryRow(
-		"SELECT id, name, prompt, cvGenerationDefault, scoreGenerationDefault, coverGenerationDefault FROM prompts WHERE cvGenerationDefault = TRUE LIMIT 1",
-	).Scan(&prompt.Id, &prompt.Name, &prompt.Prompt, &prompt.CvGenerationDefault, &prompt.ScoreGenerationDefault, &prompt.CoverGenerationDefault)
+// checkPromptUnused locks a prompt and returns ErrPromptInUse while it is a default
+// or a variant of an active experiment
+func checkPromptUnused(tx *sql.Tx, id int) error {
+	var isDefault bool
+	err := tx.QueryRow("SELECT is_default FROM prompts WHERE id = ? FOR UPDATE", id).Scan(&isDefault)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if isDefault {
+		return fmt.Errorf("%w: prompt %d is the default of its kind", ErrPromptInUse, id)
+	}
 
+	var experiments int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM experiment_variants v
+		JOIN experiments e ON e.id = v.experiment_id
+		WHERE v.prompt_id = ? AND e.status = ?
+	`, id, models.ExperimentStatusActive).Scan(&experiments)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrNotFound
-		}
-		return nil, err
+		return err
+	}
+	if experiments > 0 {
+		return fmt.Errorf("%w: prompt %d is a variant of an active experiment", ErrPromptInUse, id)
 	}
 
-	return &prompt, nil
+	return nil
 }
 
-// GetDefaultScorePrompt gets the default score generation prompt
-func GetDefaultScorePrompt() (*models.Prompt, error) {
-	var prompt models.Prompt
-	err := db.QueryRow(
-		"SELECT id, name, prompt, cvGenerationDefault, scoreGenerationDefault, coverGenerationDefault FROM prompts WHERE scoreGenerationDefault = TRUE LIMIT 1",
-	).Scan(&prompt.Id, &prompt.Name, &prompt.Prompt, &prompt.CvGenerationDefault, &prompt.ScoreGenerationDefault, &prompt.CoverGenerationDefault)
+// clearDefaultPrompt unsets the current default of a kind, locking the rows of that kind
+// so concurrent changes cannot leave two defaults behind
+func clearDefaultPrompt(tx *sql.Tx, kind models.PromptKind) error {
+	rows, err := tx.Query("SELECT id FROM prompts WHERE kind = ? FOR UPDATE", kind)
+	if err != nil {
+		return err
+	}
+	rows.Close()
 
+	_, err = tx.Exec("UPDATE prompts SET is_default = FALSE WHERE kind = ? AND is_default", kind)
+	return err
+}
+
+// updatePrompt changes the name and text of a prompt and, when either changed, records a new version.
+// updateFlags runs inside the same transaction to apply the caller's default flags.
+func updatePrompt(id int, name, promptText string, updateFlags func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrNotFound
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := updatePromptContent(tx, id, name, promptText); err != nil {
+		return err
+	}
+
+	if err := updateFlags(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updatePromptContent locks a prompt, changes its name and text and, when either changed,
+// records a new version. It returns the version the prompt is at afterwards.
+func updatePromptContent(tx *sql.Tx, id int, name, promptText string) (int, error) {
+	var currentName, currentText string
+	var version int
+	err := tx.QueryRow("SELECT name, prompt, version FROM prompts WHERE id = ? FOR UPDATE", id).Scan(&currentName, &currentText, &version)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+
+	if currentName != name || currentText != promptText {
+		version++
+		if _, err := tx.Exec("UPDATE prompts SET name = ?, prompt = ?, version = ? WHERE id = ?", name, promptText, version, id); err != nil {
+			return 0, err
+		}
+		if err := insertPromptVersion(tx, id, version, name, promptText); err != nil {
+			return 0, err
 		}
-		return nil, err
 	}
 
-	return &prompt, nil
+	return version, nil
 }
 
-// GetDefaultCoverPrompt gets the default cover letter generation prompt
-func GetDefaultCoverPrompt() (*models.Prompt, error) {
-	var prompt models.Prompt
-	err := db.QueryRow(
-		"SELECT id, name, prompt, cvGenerationDefault, scoreGenerationDefault, coverGenerationDefault FROM prompts WHERE coverGenerationDefault = TRUE LIMIT 1",
-	).Scan(&prompt.Id, &prompt.Name, &prompt.Prompt, &prompt.CvGenerationDefault, &prompt.ScoreGenerationDefault, &prompt.CoverGenerationDefault)
+// UpdateJobCV updates the CV and cvGenerated status for a job along with the prompt version used
+func UpdateJobCV(jobID int, cv string, promptVersionID *int) error {
+	_, err := db.Exec("UPDATE jobs SET cv = ?, cvGenerated = TRUE, cv_prompt_version_id = ? WHERE id = ?", cv, promptVersionID, jobID)
+	return err
+}
 
+// UpdateJobCoverLetter updates the cover letter for a job along with the prompt version used
+func UpdateJobCoverLetter(jobID int, coverLetter string, promptVersionID *int) error {
+	_, err := db.Exec("UPDATE jobs SET cover_letter = ?, cover_prompt_version_id = ? WHERE id = ?", coverLetter, promptVersionID, jobID)
+	return err
+}
+
+// GetDefaultPrompt gets the default prompt of a kind
+func GetDefaultPrompt(kind models.PromptKind) (*models.Prompt, error) {
+	prompt, err := scanPrompt(db.QueryRow("SELECT "+promptColumns+" FROM prompts WHERE kind = ? AND is_default LIMIT 1", kind))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
@@ -652,11 +1506,26 @@ func GetDefaultCoverPrompt() (*models.Prompt, error) {
 		return nil, err
 	}
 
-	return &prompt, nil
+	return prompt, nil
+}
+
+// GetDefaultCVPrompt gets the default CV generation prompt
+func GetDefaultCVPrompt() (*models.Prompt, error) {
+	return GetDefaultPrompt(models.PromptKindCV)
+}
+
+// GetDefaultScorePrompt gets the default score generation prompt
+func GetDefaultScorePrompt() (*models.Prompt, error) {
+	return GetDefaultPrompt(models.PromptKindScore)
+}
+
+// GetDefaultCoverPrompt gets the default cover letter generation prompt
+func GetDefaultCoverPrompt() (*models.Prompt, error) {
+	return GetDefaultPrompt(models.PromptKindCover)
 }
 
-// UpdateJobScore updates the score for a job
-func UpdateJobScore(jobID int, score string) error {
-	_, err := db.Exec("UPDATE jobs SET score = ? WHERE id = ?", score, jobID)
+// UpdateJobScore updates the score for a job along with the prompt version used
+func UpdateJobScore(jobID int, score string, promptVersionID *int) error {
+	_, err := db.Exec("UPDATE jobs SET score = ?, score_prompt_version_id = ? WHERE id = ?", score, promptVersionID, jobID)
 	return err
 }
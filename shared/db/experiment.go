@@ -0,0 +1,275 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Prompt experiment operations
+
+// InsertExperiment creates an active experiment with its variants.
+// Only one experiment per prompt kind can be active, otherwise ErrDuplicate is returned.
+func InsertExperiment(experiment models.Experiment) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var active int
+	err = tx.QueryRow("SELECT COUNT(*) FROM experiments WHERE kind = ? AND status = ? FOR UPDATE", experiment.Kind, models.ExperimentStatusActive).Scan(&active)
+	if err != nil {
+		return 0, err
+	}
+	if active > 0 {
+		return 0, ErrDuplicate
+	}
+
+	result, err := tx.Exec(
+		"INSERT INTO experiments (name, kind, status, created_at) VALUES (?, ?, ?, ?)",
+		experiment.Name, experiment.Kind, models.ExperimentStatusActive, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, variant := range experiment.Variants {
+		_, err := tx.Exec(
+			"INSERT INTO experiment_variants (experiment_id, prompt_id, weight) VALUES (?, ?, ?)",
+			id, variant.PromptId, variant.Weight,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func scanExperiment(row rowScanner) (*models.Experiment, error) {
+	var experiment models.Experiment
+	var createdAtStr string
+	var stoppedAtStr sql.NullString
+
+	if err := row.Scan(&experiment.Id, &experiment.Name, &experiment.Kind, &experiment.Status, &createdAtStr, &stoppedAtStr); err != nil {
+		return nil, err
+	}
+	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+		experiment.CreatedAt = createdAt
+	}
+	if stoppedAtStr.Valid {
+		if stoppedAt, err := time.Parse("2006-01-02 15:04:05", stoppedAtStr.String); err == nil {
+			experiment.StoppedAt = &stoppedAt
+		}
+	}
+
+	return &experiment, nil
+}
+
+// loadExperimentVariants fills in the variants of an experiment
+func loadExperimentVariants(experiment *models.Experiment) error {
+	rows, err := db.Query("SELECT id, experiment_id, prompt_id, weight FROM experiment_variants WHERE experiment_id = ? ORDER BY id", experiment.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var variant models.ExperimentVariant
+		if err := rows.Scan(&variant.Id, &variant.ExperimentId, &variant.PromptId, &variant.Weight); err != nil {
+			return err
+		}
+		experiment.Variants = append(experiment.Variants, variant)
+	}
+
+	return rows.Err()
+}
+
+// GetExperiments lists all experiments with their variants, newest first
+func GetExperiments() ([]models.Experiment, error) {
+	rows, err := db.Query("SELECT id, name, kind, status, created_at, stopped_at FROM experiments ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	var experiments []models.Experiment
+	for rows.Next() {
+		experiment, err := scanExperiment(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		experiments = append(experiments, *experiment)
+	}
+	rows.Close()
+
+	for i := range experiments {
+		if err := loadExperimentVariants(&experiments[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return experiments, nil
+}
+
+// GetExperimentByID gets an experiment with its variants
+func GetExperimentByID(id int) (*models.Experiment, error) {
+	experiment, err := scanExperiment(db.QueryRow("SELECT id, name, kind, status, created_at, stopped_at FROM experiments WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err := loadExperimentVariants(experiment); err != nil {
+		return nil, err
+	}
+
+	return experiment, nil
+}
+
+// GetActiveExperiment gets the running experiment for a prompt kind
+func GetActiveExperiment(kind models.PromptKind) (*models.Experiment, error) {
+	experiment, err := scanExperiment(db.QueryRow(
+		"SELECT id, name, kind, status, created_at, stopped_at FROM experiments WHERE kind = ? AND status = ? ORDER BY id DESC LIMIT 1",
+		kind, models.ExperimentStatusActive,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err := loadExperimentVariants(experiment); err != nil {
+		return nil, err
+	}
+
+	return experiment, nil
+}
+
+// StopExperiment ends an experiment; jobs keep their recorded assignments
+func StopExperiment(id int) error {
+	result, err := db.Exec(
+		"UPDATE experiments SET status = ?, stopped_at = CURRENT_TIMESTAMP() WHERE id = ? AND status = ?",
+		models.ExperimentStatusStopped, id, models.ExperimentStatusActive,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AssignExperimentVariant records the variant chosen for a job.
+// A job keeps its first assignment, so redelivered messages reuse the original variant.
+func AssignExperimentVariant(experimentID, variantID, jobID int) (*models.ExperimentAssignment, error) {
+	_, err := db.Exec(
+		"INSERT IGNORE INTO experiment_assignments (experiment_id, variant_id, job_id, created_at) VALUES (?, ?, ?, ?)",
+		experimentID, variantID, jobID, time.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetExperimentAssignment(experimentID, jobID)
+}
+
+// GetExperimentAssignment gets the variant assignment of a job in an experiment
+func GetExperimentAssignment(experimentID, jobID int) (*models.ExperimentAssignment, error) {
+	var assignment models.ExperimentAssignment
+	var createdAtStr string
+
+	err := db.QueryRow(
+		"SELECT id, experiment_id, variant_id, job_id, input_tokens, output_tokens, created_at FROM experiment_assignments WHERE experiment_id = ? AND job_id = ?",
+		experimentID, jobID,
+	).Scan(&assignment.Id, &assignment.ExperimentId, &assignment.VariantId, &assignment.JobId, &assignment.InputTokens, &assignment.OutputTokens, &createdAtStr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+		assignment.CreatedAt = createdAt
+	}
+
+	return &assignment, nil
+}
+
+// RecordExperimentUsage stores the token usage of the generation made for an assignment
+func RecordExperimentUsage(assignmentID, inputTokens, outputTokens int) error {
+	_, err := db.Exec(
+		"UPDATE experiment_assignments SET input_tokens = ?, output_tokens = ? WHERE id = ?",
+		inputTokens, outputTokens, assignmentID,
+	)
+	return err
+}
+
+// GetExperimentReport aggregates match score, interview rate and token usage per variant.
+// A job counts as interviewed once it had an interview or reached the interviewing status,
+// even if it was rejected or withdrawn later. Costs are left for the caller to derive from
+// the token counts.
+func GetExperimentReport(experimentID int) ([]models.VariantReport, error) {
+	rows, err := db.Query(`
+		SELECT v.id, v.prompt_id, COALESCE(p.name, ''), v.weight,
+			COUNT(a.id), COUNT(j.score), AVG(j.score),
+			COALESCE(SUM(j.applied_at IS NOT NULL), 0),
+			COALESCE(SUM(
+				EXISTS (SELECT 1 FROM interviews i WHERE i.job_id = j.id) OR
+				EXISTS (SELECT 1 FROM job_status_history h WHERE h.job_id = j.id AND h.to_status IN (?, ?, ?))
+			), 0),
+			COALESCE(SUM(a.input_tokens), 0), COALESCE(SUM(a.output_tokens), 0)
+		FROM experiment_variants v
+		LEFT JOIN prompts p ON p.id = v.prompt_id
+		LEFT JOIN experiment_assignments a ON a.variant_id = v.id
+		LEFT JOIN jobs j ON j.id = a.job_id
+		WHERE v.experiment_id = ?
+		GROUP BY v.id, v.prompt_id, p.name, v.weight
+		ORDER BY v.id
+	`, models.JobStatusInterviewing, models.JobStatusOffer, models.JobStatusAccepted, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []models.VariantReport
+	for rows.Next() {
+		var report models.VariantReport
+		var avgScore sql.NullFloat64
+		err := rows.Scan(&report.VariantId, &report.PromptId, &report.PromptName, &report.Weight,
+			&report.Jobs, &report.ScoredJobs, &avgScore, &report.Applied, &report.Interviewed,
+			&report.InputTokens, &report.OutputTokens)
+		if err != nil {
+			return nil, err
+		}
+		if avgScore.Valid {
+			report.AvgScore = &avgScore.Float64
+		}
+		if report.Applied > 0 {
+			report.InterviewRate = float64(report.Interviewed) / float64(report.Applied)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Job expiry policy operations
+
+func scanExpiryPolicy(row rowScanner) (*models.ExpiryPolicy, error) {
+	var policy models.ExpiryPolicy
+	var updatedAtStr string
+	if err := row.Scan(&policy.Kind, &policy.AfterDays, &policy.Enabled, &updatedAtStr); err != nil {
+		return nil, err
+	}
+	policy.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAtStr)
+	return &policy, nil
+}
+
+// GetExpiryPolicies returns the job expiry policies, only the enabled ones if enabledOnly is set
+func GetExpiryPolicies(enabledOnly bool) ([]models.ExpiryPolicy, error) {
+	query := "SELECT kind, after_days, enabled, updated_at FROM job_expiry_policies"
+	if enabledOnly {
+		query += " WHERE enabled"
+	}
+	rows, err := db.Query(query + " ORDER BY kind")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []models.ExpiryPolicy{}
+	for rows.Next() {
+		policy, err := scanExpiryPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *policy)
+	}
+	return policies, rows.Err()
+}
+
+// GetExpiryPolicy returns the job expiry policy of a kind
+func GetExpiryPolicy(kind models.ExpiryPolicyKind) (*models.ExpiryPolicy, error) {
+	policy, err := scanExpiryPolicy(db.QueryRow("SELECT kind, after_days, enabled, updated_at FROM job_expiry_policies WHERE kind = ?", kind))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return policy, err
+}
+
+// UpdateExpiryPolicy changes the delay and the enabled flag of a job expiry policy
+func UpdateExpiryPolicy(policy models.ExpiryPolicy) error {
+	result, err := db.Exec(
+		"UPDATE job_expiry_policies SET after_days = ?, enabled = ? WHERE kind = ?",
+		policy.AfterDays, policy.Enabled, policy.Kind,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetExpiryPolicy(policy.Kind)
+		return err
+	})
+}
+
+// GetExpiryCandidates returns the jobs the policy moves at now, whether it is enabled or not.
+// Open jobs qualify when they were never applied to, applications when no interview was
+// recorded for them.
+func GetExpiryCandidates(policy models.ExpiryPolicy, now time.Time) ([]models.ExpiryCandidate, error) {
+	from, to := policy.Kind.Transition()
+	condition := "j.applied_at IS NULL"
+	if policy.Kind == models.ExpiryPolicyGhostUnanswered {
+		condition = "NOT EXISTS (SELECT 1 FROM interviews i WHERE i.job_id = j.id)"
+	}
+
+	rows, err := db.Query(`
+		SELECT j.id, COALESCE(j.title, ''), COALESCE(j.company, ''), h.changed_at
+		FROM jobs j
+		JOIN job_status_history h ON h.id = (SELECT MAX(id) FROM job_status_history WHERE job_id = j.id)
+		WHERE j.status = ? AND h.changed_at <= ? AND `+condition+`
+		ORDER BY h.changed_at, j.id`,
+		from, now.AddDate(0, 0, -policy.AfterDays).UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []models.ExpiryCandidate{}
+	for rows.Next() {
+		candidate := models.ExpiryCandidate{Policy: policy.Kind, From: from, To: to}
+		var sinceStr string
+		if err := rows.Scan(&candidate.JobId, &candidate.JobTitle, &candidate.JobCompany, &sinceStr); err != nil {
+			return nil, err
+		}
+		candidate.Since, _ = time.Parse("2006-01-02 15:04:05", sinceStr)
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Job feed operations. Feed URLs and item GUIDs are unique by their SHA-1 hash, as
+// they can be longer than an index allows.
+
+// jobFeedColumns is the column list of job feed queries, in scanJobFeed order
+const jobFeedColumns = "id, url, name, company, fetch_description, enabled, COALESCE(etag, ''), COALESCE(last_modified, ''), last_polled_at, COALESCE(last_error, ''), last_created, created_at"
+
+func scanJobFeed(row rowScanner) (*models.JobFeed, error) {
+	var feed models.JobFeed
+	var lastPolledAt sql.NullString
+	var createdAtStr string
+	if err := row.Scan(&feed.Id, &feed.URL, &feed.Name, &feed.Company, &feed.FetchDescription, &feed.Enabled,
+		&feed.ETag, &feed.LastModified, &lastPolledAt, &feed.LastError, &feed.LastCreated, &createdAtStr); err != nil {
+		return nil, err
+	}
+	feed.LastPolledAt = nullTimePtr(lastPolledAt)
+	feed.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	return &feed, nil
+}
+
+// GetJobFeeds returns the job feeds, only the enabled ones if enabledOnly is set
+func GetJobFeeds(enabledOnly bool) ([]models.JobFeed, error) {
+	query := "SELECT " + jobFeedColumns + " FROM job_feeds"
+	if enabledOnly {
+		query += " WHERE enabled"
+	}
+	rows, err := db.Query(query + " ORDER BY name, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feeds := []models.JobFeed{}
+	for rows.Next() {
+		feed, err := scanJobFeed(rows)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, *feed)
+	}
+	return feeds, rows.Err()
+}
+
+// GetJobFeedByID returns a job feed
+func GetJobFeedByID(id int) (*models.JobFeed, error) {
+	feed, err := scanJobFeed(db.QueryRow("SELECT "+jobFeedColumns+" FROM job_feeds WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return feed, err
+}
+
+// InsertJobFeed stores a new job feed; ErrDuplicate if the URL is already watched
+func InsertJobFeed(feed models.JobFeed) (int64, error) {
+	result, err := db.Exec(
+		"INSERT INTO job_feeds (url, url_hash, name, company, fetch_description, enabled) VALUES (?, SHA1(?), ?, ?, ?, ?)",
+		feed.URL, feed.URL, feed.Name, feed.Company, feed.FetchDescription, feed.Enabled,
+	)
+	if isDuplicateEntry(err) {
+		return 0, ErrDuplicate
+	} else if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateJobFeed replaces the settings of a job feed. The validators of the last answer
+// are dropped, so the next poll reads the whole feed with the new settings.
+func UpdateJobFeed(feed models.JobFeed) error {
+	result, err := db.Exec(
+		`UPDATE job_feeds SET url = ?, url_hash = SHA1(?), name = ?, company = ?, fetch_description = ?, enabled = ?,
+			etag = NULL, last_modified = NULL WHERE id = ?`,
+		feed.URL, feed.URL, feed.Name, feed.Company, feed.FetchDescription, feed.Enabled, feed.Id,
+	)
+	if isDuplicateEntry(err) {
+		return ErrDuplicate
+	} else if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetJobFeedByID(feed.Id)
+		return err
+	})
+}
+
+// DeleteJobFeed removes a job feed and the record of its items; jobs created from it
+// are kept
+func DeleteJobFeed(id int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM job_feeds WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec("DELETE FROM job_feed_items WHERE feed_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordJobFeedPoll stores the outcome of a poll with the validators to send next
+// time; pollErr is empty for successful polls
+func RecordJobFeedPoll(id int, etag, lastModified string, created int, pollErr string) error {
+	_, err := db.Exec(
+		`UPDATE job_feeds SET etag = ?, last_modified = ?, last_polled_at = CURRENT_TIMESTAMP(),
+			last_created = ?, last_error = ? WHERE id = ?`,
+		nullString(etag), nullString(lastModified), created, nullString(truncateRunes(pollErr, 1000)), id,
+	)
+	return err
+}
+
+// GetSeenFeedItems returns the GUIDs of the items a feed has published already
+func GetSeenFeedItems(feedID int) (map[string]bool, error) {
+	rows, err := db.Query("SELECT guid FROM job_feed_items WHERE feed_id = ?", feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := map[string]bool{}
+	for rows.Next() {
+		var guid string
+		if err := rows.Scan(&guid); err != nil {
+			return nil, err
+		}
+		seen[guid] = true
+	}
+	return seen, rows.Err()
+}
+
+// MarkFeedItemSeen records that an item of a feed was published
+func MarkFeedItemSeen(feedID int, guid string) error {
+	_, err := db.Exec("INSERT IGNORE INTO job_feed_items (feed_id, guid, guid_hash) VALUES (?, ?, SHA1(?))", feedID, guid, guid)
+	return err
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Interview operations
+
+// interviewColumns is the column list of interview queries, in scanInterview order
+const interviewColumns = "i.id, i.job_id, i.round, i.scheduled_at, i.duration_minutes, i.interviewers, i.location, i.outcome, i.feedback, i.created_at, i.updated_at"
+
+func scanInterview(row rowScanner, extra ...interface{}) (*models.Interview, error) {
+	var interview models.Interview
+	var scheduledAtStr, createdAtStr, updatedAtStr string
+	var duration sql.NullInt64
+	var interviewers, location, feedback sql.NullString
+
+	dest := []interface{}{&interview.Id, &interview.JobId, &interview.Round, &scheduledAtStr, &duration,
+		&interviewers, &location, &interview.Outcome, &feedback, &createdAtStr, &updatedAtStr}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	interview.ScheduledAt, _ = time.Parse("2006-01-02 15:04:05", scheduledAtStr)
+	interview.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	interview.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAtStr)
+	interview.DurationMinutes = nullIntPtr(duration)
+	interview.Location = location.String
+	interview.Feedback = feedback.String
+
+	interview.Interviewers = []string{}
+	if interviewers.Valid && interviewers.String != "" {
+		if err := json.Unmarshal([]byte(interviewers.String), &interview.Interviewers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &interview, nil
+}
+
+// interviewArgs returns the writable columns of an interview, in insert/update order
+func interviewArgs(interview models.Interview) ([]interface{}, error) {
+	interviewers := interview.Interviewers
+	if interviewers == nil {
+		interviewers = []string{}
+	}
+	encoded, err := json.Marshal(interviewers)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		interview.Round,
+		interview.ScheduledAt.UTC().Format("2006-01-02 15:04:05"),
+		interview.DurationMinutes,
+		string(encoded),
+		nullString(interview.Location),
+		interview.Outcome,
+		nullString(interview.Feedback),
+	}, nil
+}
+
+// InsertInterview stores an interview round of an existing job
+func InsertInterview(interview models.Interview) (int64, error) {
+	args, err := interviewArgs(interview)
+	if err != nil {
+		return 0, err
+	}
+
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM jobs WHERE id = ?", interview.JobId).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		return 0, ErrNotFound
+	}
+
+	result, err := db.Exec(
+		"INSERT INTO interviews (job_id, round, scheduled_at, duration_minutes, interviewers, location, outcome, feedback) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		append([]interface{}{interview.JobId}, args...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateInterview replaces the editable fields of an interview; the job cannot change
+func UpdateInterview(interview models.Interview) error {
+	args, err := interviewArgs(interview)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.Exec(
+		"UPDATE interviews SET round = ?, scheduled_at = ?, duration_minutes = ?, interviewers = ?, location = ?, outcome = ?, feedback = ? WHERE id = ?",
+		append(args, interview.Id)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetInterviewByID(interview.Id)
+		return err
+	})
+}
+
+// DeleteInterview removes an interview
+func DeleteInterview(id int) error {
+	result, err := db.Exec("DELETE FROM interviews WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetInterviewByID returns an interview
+func GetInterviewByID(id int) (*models.Interview, error) {
+	interview, err := scanInterview(db.QueryRow("SELECT "+interviewColumns+" FROM interviews i WHERE i.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return interview, err
+}
+
+// GetInterviewsByJob returns the interview rounds of a job in schedule order
+func GetInterviewsByJob(jobID int) ([]models.Interview, error) {
+	rows, err := db.Query("SELECT "+interviewColumns+" FROM interviews i WHERE i.job_id = ? ORDER BY i.scheduled_at, i.id", jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interviews := []models.Interview{}
+	for rows.Next() {
+		interview, err := scanInterview(rows)
+		if err != nil {
+			return nil, err
+		}
+		interviews = append(interviews, *interview)
+	}
+	return interviews, rows.Err()
+}
+
+// GetUpcomingInterviews returns the pending interviews scheduled between now and until
+func GetUpcomingInterviews(until time.Time, limit int) ([]models.UpcomingInterview, error) {
+	rows, err := db.Query(
+		"SELECT "+interviewColumns+", COALESCE(j.title, ''), COALESCE(j.company, '') FROM interviews i JOIN jobs j ON j.id = i.job_id "+
+			"WHERE i.outcome = ? AND i.scheduled_at >= UTC_TIMESTAMP() AND i.scheduled_at <= ? ORDER BY i.scheduled_at, i.id LIMIT ?",
+		models.InterviewOutcomePending, until.UTC().Format("2006-01-02 15:04:05"), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	upcoming := []models.UpcomingInterview{}
+	for rows.Next() {
+		var entry models.UpcomingInterview
+		interview, err := scanInterview(rows, &entry.JobTitle, &entry.JobCompany)
+		if err != nil {
+			return nil, err
+		}
+		entry.Interview = *interview
+		upcoming = append(upcoming, entry)
+	}
+	return upcoming, rows.Err()
+}
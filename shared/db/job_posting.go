@@ -0,0 +1,344 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/normalize"
+	"github.com/hirepilot/shared/posting"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for unique key violations
+const mysqlDuplicateEntry = 1062
+
+// jobDetailColumns are the structured posting columns, in jobDetailScan order
+const jobDetailColumns = "location, workplace_type, employment_type, seniority, salary_min, salary_max, salary_currency, posted_at"
+
+// jobDetailScan holds the nullable scan destinations of jobDetailColumns
+type jobDetailScan struct {
+	location, workplaceType, employmentType, seniority sql.NullString
+	salaryMin, salaryMax                               sql.NullFloat64
+	salaryCurrency, postedAt                           sql.NullString
+}
+
+func (s *jobDetailScan) dest() []interface{} {
+	return []interface{}{&s.location, &s.workplaceType, &s.employmentType, &s.seniority,
+		&s.salaryMin, &s.salaryMax, &s.salaryCurrency, &s.postedAt}
+}
+
+func (s *jobDetailScan) apply(details *models.JobDetails) {
+	details.Location = s.location.String
+	details.WorkplaceType = models.WorkplaceType(s.workplaceType.String)
+	details.EmploymentType = models.EmploymentType(s.employmentType.String)
+	details.Seniority = models.Seniority(s.seniority.String)
+	if s.salaryMin.Valid {
+		details.SalaryMin = &s.salaryMin.Float64
+	}
+	if s.salaryMax.Valid {
+		details.SalaryMax = &s.salaryMax.Float64
+	}
+	details.SalaryCurrency = s.salaryCurrency.String
+	if s.postedAt.Valid {
+		if postedAt, err := time.Parse("2006-01-02 15:04:05", s.postedAt.String); err == nil {
+			details.PostedAt = &postedAt
+		}
+	}
+}
+
+// jobDetailArgs returns the values of jobDetailColumns, with NULL for empty attributes
+func jobDetailArgs(details models.JobDetails) []interface{} {
+	var postedAt interface{}
+	if details.PostedAt != nil {
+		postedAt = details.PostedAt.Format("2006-01-02 15:04:05")
+	}
+	return []interface{}{
+		nullString(details.Location),
+		nullString(string(details.WorkplaceType)),
+		nullString(string(details.EmploymentType)),
+		nullString(string(details.Seniority)),
+		details.SalaryMin,
+		details.SalaryMax,
+		nullString(details.SalaryCurrency),
+		postedAt,
+	}
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertJob stores a new job with its description normalised and as received
+func insertJob(ex execer, p models.JobPosting, source, externalID, link string) (int64, error) {
+	args := []interface{}{p.Title, p.Company, link, "open", false, "", normalize.Description(p.Description), nullString(p.Description),
+		time.Now(), nil, nullString(source), nullString(externalID)}
+	result, err := ex.Exec(
+		"INSERT INTO jobs (title, company, link, status, cvGenerated, cv, description, raw_description, created_at, creation_handled_at, source, external_id, "+jobDetailColumns+") "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		append(args, jobDetailArgs(p.JobDetails)...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := recordJobStatus(ex, int(id), "", models.JobStatusOpen, ""); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// JobUpsert reports what UpsertJob did with a job posting
+type JobUpsert struct {
+	ID                 int
+	Created            bool // False when the posting was already known
+	CreationHandled    bool // False until MarkJobCreationHandled, also for known postings
+	DescriptionUpdated bool
+	Description        string // Normalised description, when updated
+}
+
+// postingKey returns the key a posting is deduplicated by. Sources that know the
+// external ID, like the scraper, may send it; otherwise it is derived from the link.
+func postingKey(p models.JobPosting) posting.Key {
+	key := posting.Canonicalize(p.Link)
+	if p.Source != "" && p.ExternalId != "" {
+		key.Source, key.ExternalID = p.Source, p.ExternalId
+		if key.URL == "" {
+			key.URL = p.Link
+		}
+	}
+	return key
+}
+
+// UpsertJob stores a job unless its posting is already known. Known postings get
+// their description refreshed when it changed, and structured attributes they were
+// missing filled in; jobs without a usable link are always inserted. Descriptions
+// are stored as received and normalised; see normalize.Description.
+func UpsertJob(p models.JobPosting) (*JobUpsert, error) {
+	key := postingKey(p)
+	if key.IsZero() {
+		source := posting.SourceManual
+		if p.Link != "" {
+			source = posting.SourceOther
+		}
+		id, err := insertJob(db, p, source, "", p.Link)
+		if err != nil {
+			return nil, err
+		}
+		return &JobUpsert{ID: int(id), Created: true}, nil
+	}
+
+	result, err := upsertJobByKey(key, p)
+	if isDuplicateEntry(err) {
+		// A concurrent request inserted the same posting first, it is known now
+		result, err = upsertJobByKey(key, p)
+	}
+	return result, err
+}
+
+func upsertJobByKey(key posting.Key, p models.JobPosting) (*JobUpsert, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	var existing, existingRaw sql.NullString
+	var handled bool
+	err = tx.QueryRow(
+		"SELECT id, description, COALESCE(raw_description, description), creation_handled_at IS NOT NULL FROM jobs WHERE source = ? AND external_id = ? FOR UPDATE",
+		key.Source, key.ExternalID,
+	).Scan(&id, &existing, &existingRaw, &handled)
+
+	if err == sql.ErrNoRows {
+		newID, err := insertJob(tx, p, key.Source, key.ExternalID, key.URL)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return &JobUpsert{ID: int(newID), Created: true}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// A changed description that normalises the same, such as a moved "Show more"
+	// button, is stored but not reported as an update
+	result := &JobUpsert{ID: id, CreationHandled: handled}
+	if p.Description != "" && p.Description != existingRaw.String {
+		description := normalize.Description(p.Description)
+		if _, err := tx.Exec("UPDATE jobs SET description = ?, raw_description = ? WHERE id = ?", description, p.Description, id); err != nil {
+			return nil, err
+		}
+		if description != existing.String {
+			result.DescriptionUpdated, result.Description = true, description
+			// The requirements are read again from the new description
+			if _, err := tx.Exec("UPDATE jobs SET skills_extracted_at = NULL WHERE id = ?", id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Attributes already known are kept, a later scrape may only add missing ones
+	args := append(jobDetailArgs(p.JobDetails), id)
+	_, err = tx.Exec(`
+		UPDATE jobs SET
+			location = COALESCE(location, ?),
+			workplace_type = COALESCE(workplace_type, ?),
+			employment_type = COALESCE(employment_type, ?),
+			seniority = COALESCE(seniority, ?),
+			salary_min = COALESCE(salary_min, ?),
+			salary_max = COALESCE(salary_max, ?),
+			salary_currency = COALESCE(salary_currency, ?),
+			posted_at = COALESCE(posted_at, ?)
+		WHERE id = ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MarkJobCreationHandled records that the creation of a job is complete, its
+// generation requested or off. Until then a redelivered creation request finishes it.
+func MarkJobCreationHandled(id int) error {
+	_, err := db.Exec("UPDATE jobs SET creation_handled_at = CURRENT_TIMESTAMP WHERE id = ?", id)
+	return err
+}
+
+// GetJobByLink returns the job created from the posting a link points at
+func GetJobByLink(link string) (*models.Job, error) {
+	key := posting.Canonicalize(link)
+	if key.IsZero() {
+		return nil, ErrNotFound
+	}
+
+	job, err := scanJob(db.QueryRow(
+		"SELECT "+jobColumns+" FROM jobs WHERE source = ? AND external_id = ?",
+		key.Source, key.ExternalID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return job, err
+}
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// backfillJobPostingKeys derives the posting key of jobs stored before deduplication.
+// The oldest job of a posting keeps the key; later duplicates only get their source
+// so the unique index can be built and they are not reprocessed.
+func backfillJobPostingKeys() {
+	rows, err := db.Query("SELECT id, COALESCE(link, '') FROM jobs WHERE source IS NULL ORDER BY id")
+	if err != nil {
+		log.Fatalf("Job posting key backfill query error: %v", err)
+	}
+	type pending struct {
+		id   int
+		link string
+	}
+	var jobs []pending
+	for rows.Next() {
+		var job pending
+		if err := rows.Scan(&job.id, &job.link); err != nil {
+			rows.Close()
+			log.Fatalf("Job posting key backfill scan error: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+	rows.Close()
+
+	duplicates := 0
+	for _, job := range jobs {
+		key := posting.Canonicalize(job.link)
+		if key.IsZero() {
+			source := posting.SourceManual
+			if job.link != "" {
+				source = posting.SourceOther
+			}
+			if _, err := db.Exec("UPDATE jobs SET source = ? WHERE id = ?", source, job.id); err != nil {
+				log.Fatalf("Job posting key backfill update error for job %d: %v", job.id, err)
+			}
+			continue
+		}
+
+		var known int
+		if err := db.QueryRow(
+			"SELECT COUNT(*) FROM jobs WHERE source = ? AND external_id = ?",
+			key.Source, key.ExternalID,
+		).Scan(&known); err != nil {
+			log.Fatalf("Job posting key backfill lookup error: %v", err)
+		}
+
+		if known > 0 {
+			_, err = db.Exec("UPDATE jobs SET source = ? WHERE id = ?", key.Source, job.id)
+			duplicates++
+		} else {
+			_, err = db.Exec("UPDATE jobs SET source = ?, external_id = ?, link = ? WHERE id = ?", key.Source, key.ExternalID, key.URL, job.id)
+		}
+		if err != nil {
+			log.Fatalf("Job posting key backfill update error for job %d: %v", job.id, err)
+		}
+	}
+	if len(jobs) > 0 {
+		log.Printf("Backfilled posting keys of %d jobs, %d duplicates left unkeyed", len(jobs), duplicates)
+	}
+}
+
+// backfillNormalizedDescriptions normalises the descriptions of jobs stored before
+// normalisation, keeping the stored description as the one received
+func backfillNormalizedDescriptions() {
+	rows, err := db.Query("SELECT id, description FROM jobs WHERE raw_description IS NULL AND description IS NOT NULL AND description <> ''")
+	if err != nil {
+		log.Fatalf("Description backfill query error: %v", err)
+	}
+	type pending struct {
+		id          int
+		description string
+	}
+	var jobs []pending
+	for rows.Next() {
+		var job pending
+		if err := rows.Scan(&job.id, &job.description); err != nil {
+			rows.Close()
+			log.Fatalf("Description backfill scan error: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+	rows.Close()
+
+	for _, job := range jobs {
+		if _, err := db.Exec(
+			"UPDATE jobs SET description = ?, raw_description = ? WHERE id = ?",
+			normalize.Description(job.description), job.description, job.id,
+		); err != nil {
+			log.Fatalf("Description backfill update error for job %d: %v", job.id, err)
+		}
+	}
+	if len(jobs) > 0 {
+		log.Printf("Normalised the descriptions of %d jobs", len(jobs))
+	}
+}
@@ -0,0 +1,406 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Sort orders of the job list
+const (
+	JobSortCreated = "created"
+	JobSortApplied = "applied"
+	JobSortScore   = "score"
+)
+
+// Page sizes of the job list
+const (
+	DefaultJobPageSize = 50
+	MaxJobPageSize     = 200
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed or belong to another sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Jobs without an application date or score sort as if they had these values
+const (
+	noAppliedAt = "1000-01-01 00:00:00"
+	noScore     = -1.0
+)
+
+var jobSortColumns = map[string]string{
+	JobSortCreated: "created_at",
+	JobSortApplied: "COALESCE(applied_at, '" + noAppliedAt + "')",
+	// FLOAT scores are rounded so cursor values compare equal to the stored ones
+	JobSortScore: "ROUND(COALESCE(score, -1), 4)",
+}
+
+// summaryColumns is the column list of the job list projection, in scanJobSummary order
+const summaryColumns = "id, title, company, link, status, cvGenerated, COALESCE(cover_letter, '') <> '', score, created_at, applied_at, source, " + jobDetailColumns
+
+// JobQuery filters, sorts and pages the job list. Zero values disable a filter.
+type JobQuery struct {
+	Status        string
+	Company       string
+	MinScore      *float64
+	MaxScore      *float64
+	CreatedAfter  *time.Time // Inclusive
+	CreatedBefore *time.Time // Exclusive
+	HasCV         *bool
+	Search        string // Full-text search over title, company and description
+
+	Location       string // Substring of the location
+	WorkplaceType  models.WorkplaceType
+	EmploymentType models.EmploymentType
+	Seniority      models.Seniority
+	Source         string
+	MinSalary      *float64 // Jobs whose salary range reaches at least this amount
+	SalaryCurrency string
+	Tags           []string // Jobs with every one of these tags
+	Skill          string   // Jobs requiring this skill, language, certification or degree
+
+	Sort      string // One of the JobSort constants, newest first by default
+	Ascending bool
+	Limit     int
+	Cursor    string // NextCursor of the previous page
+}
+
+// jobCursor is the position after the last job of a page
+type jobCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+func (q *JobQuery) normalize() {
+	if _, ok := jobSortColumns[q.Sort]; !ok {
+		q.Sort = JobSortCreated
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultJobPageSize
+	}
+	if q.Limit > MaxJobPageSize {
+		q.Limit = MaxJobPageSize
+	}
+}
+
+// filters builds the WHERE clause shared by the page and count queries
+func (q *JobQuery) filters() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if q.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, q.Status)
+	}
+	if q.Company != "" {
+		conditions = append(conditions, "company = ?")
+		args = append(args, q.Company)
+	}
+	if q.MinScore != nil {
+		conditions = append(conditions, "score >= ?")
+		args = append(args, *q.MinScore)
+	}
+	if q.MaxScore != nil {
+		conditions = append(conditions, "score <= ?")
+		args = append(args, *q.MaxScore)
+	}
+	if q.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, q.CreatedAfter.Format("2006-01-02 15:04:05"))
+	}
+	if q.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, q.CreatedBefore.Format("2006-01-02 15:04:05"))
+	}
+	if q.HasCV != nil {
+		conditions = append(conditions, "cvGenerated = ?")
+		args = append(args, *q.HasCV)
+	}
+	if q.Location != "" {
+		conditions = append(conditions, "location LIKE ?")
+		args = append(args, "%"+escapeLike(q.Location)+"%")
+	}
+	if q.WorkplaceType != "" {
+		conditions = append(conditions, "workplace_type = ?")
+		args = append(args, q.WorkplaceType)
+	}
+	if q.EmploymentType != "" {
+		conditions = append(conditions, "employment_type = ?")
+		args = append(args, q.EmploymentType)
+	}
+	if q.Seniority != "" {
+		conditions = append(conditions, "seniority = ?")
+		args = append(args, q.Seniority)
+	}
+	if q.Source != "" {
+		conditions = append(conditions, "source = ?")
+		args = append(args, q.Source)
+	}
+	if q.MinSalary != nil {
+		conditions = append(conditions, "COALESCE(salary_max, salary_min) >= ?")
+		args = append(args, *q.MinSalary)
+	}
+	if q.SalaryCurrency != "" {
+		conditions = append(conditions, "salary_currency = ?")
+		args = append(args, q.SalaryCurrency)
+	}
+	for _, tag := range q.Tags {
+		conditions = append(conditions, "id IN (SELECT jt.job_id FROM job_tags jt JOIN tags t ON t.id = jt.tag_id WHERE t.name = ?)")
+		args = append(args, tag)
+	}
+	if q.Skill != "" {
+		conditions = append(conditions, "id IN (SELECT job_id FROM job_skills WHERE name = ?)")
+		args = append(args, q.Skill)
+	}
+	if search := fullTextQuery(q.Search); search != "" {
+		conditions = append(conditions, "MATCH(title, company, description) AGAINST (? IN BOOLEAN MODE)")
+		args = append(args, search)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// fullTextQuery turns free text into a boolean mode query requiring every word as a prefix
+func fullTextQuery(search string) string {
+	words := strings.FieldsFunc(search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, "+"+word+"*")
+	}
+	return strings.Join(terms, " ")
+}
+
+// page builds the full page query for the given column list
+func (q *JobQuery) page(columns string) (string, []interface{}, error) {
+	q.normalize()
+	where, args := q.filters()
+
+	sortColumn := jobSortColumns[q.Sort]
+	direction, comparison := "DESC", "<"
+	if q.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	if q.Cursor != "" {
+		cursor, err := decodeJobCursor(q.Cursor)
+		if err != nil || cursor.Sort != q.Sort {
+			return "", nil, ErrInvalidCursor
+		}
+		var value interface{} = cursor.Value
+		if q.Sort == JobSortScore {
+			score, err := strconv.ParseFloat(cursor.Value, 64)
+			if err != nil {
+				return "", nil, ErrInvalidCursor
+			}
+			value = score
+		}
+
+		condition := "(" + sortColumn + " " + comparison + " ? OR (" + sortColumn + " = ? AND id " + comparison + " ?))"
+		if where == "" {
+			where = " WHERE " + condition
+		} else {
+			where += " AND " + condition
+		}
+		args = append(args, value, value, cursor.ID)
+	}
+
+	// One extra row tells whether there is a next page
+	query := "SELECT " + columns + " FROM jobs" + where +
+		" ORDER BY " + sortColumn + " " + direction + ", id " + direction +
+		" LIMIT " + strconv.Itoa(q.Limit+1)
+	return query, args, nil
+}
+
+// nextCursor encodes the position after a job, or returns "" on the last page
+func (q *JobQuery) nextCursor(fetched int, id int, createdAt time.Time, appliedAt *time.Time, score *float64) string {
+	if fetched <= q.Limit {
+		return ""
+	}
+
+	cursor := jobCursor{Sort: q.Sort, ID: id}
+	switch q.Sort {
+	case JobSortApplied:
+		cursor.Value = noAppliedAt
+		if appliedAt != nil {
+			cursor.Value = appliedAt.Format("2006-01-02 15:04:05")
+		}
+	case JobSortScore:
+		value := noScore
+		if score != nil {
+			value = *score
+		}
+		cursor.Value = strconv.FormatFloat(value, 'f', 4, 64)
+	default:
+		cursor.Value = createdAt.Format("2006-01-02 15:04:05")
+	}
+
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeJobCursor(s string) (*jobCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor jobCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// QueryJobs returns one page of full jobs and the cursor of the next page
+func QueryJobs(q JobQuery) ([]models.Job, string, error) {
+	query, args, err := q.page(jobColumns)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	jobs := []models.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	fetched := len(jobs)
+	if fetched > q.Limit {
+		jobs = jobs[:q.Limit]
+	}
+	ids := make([]int, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.Id
+	}
+	tags, err := getJobTags(ids)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range jobs {
+		jobs[i].Tags = tags[jobs[i].Id]
+	}
+	next := ""
+	if len(jobs) > 0 {
+		last := jobs[len(jobs)-1]
+		next = q.nextCursor(fetched, last.Id, last.CreatedAt, last.AppliedAt, last.Score)
+	}
+	return jobs, next, nil
+}
+
+// QueryJobSummaries returns one page of the job list projection and the cursor of the next page
+func QueryJobSummaries(q JobQuery) ([]models.JobSummary, string, error) {
+	query, args, err := q.page(summaryColumns)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	jobs := []models.JobSummary{}
+	for rows.Next() {
+		job, err := scanJobSummary(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	fetched := len(jobs)
+	if fetched > q.Limit {
+		jobs = jobs[:q.Limit]
+	}
+	ids := make([]int, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.Id
+	}
+	tags, err := getJobTags(ids)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range jobs {
+		jobs[i].Tags = tags[jobs[i].Id]
+	}
+	next := ""
+	if len(jobs) > 0 {
+		last := jobs[len(jobs)-1]
+		next = q.nextCursor(fetched, last.Id, last.CreatedAt, last.AppliedAt, last.Score)
+	}
+	return jobs, next, nil
+}
+
+// CountJobs returns the number of jobs matching the filters of a query, across all pages
+func CountJobs(q JobQuery) (int, error) {
+	where, args := q.filters()
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM jobs"+where, args...).Scan(&count)
+	return count, err
+}
+
+// scanJobSummary scans a row selected with summaryColumns into a JobSummary
+func scanJobSummary(row rowScanner) (*models.JobSummary, error) {
+	var job models.JobSummary
+	var titleStr, companyStr, linkStr, sourceStr sql.NullString
+	var createdAtStr string
+	var appliedAtStr sql.NullString
+	var details jobDetailScan
+
+	dest := []interface{}{&job.Id, &titleStr, &companyStr, &linkStr, &job.Status, &job.CvGenerated, &job.HasCoverLetter,
+		&job.Score, &createdAtStr, &appliedAtStr, &sourceStr}
+	err := row.Scan(append(dest, details.dest()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Title = titleStr.String
+	job.Company = companyStr.String
+	job.Link = linkStr.String
+	job.Source = sourceStr.String
+	details.apply(&job.JobDetails)
+
+	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+		job.CreatedAt = createdAt
+	}
+	if appliedAtStr.Valid {
+		if appliedAt, err := time.Parse("2006-01-02 15:04:05", appliedAtStr.String); err == nil {
+			job.AppliedAt = &appliedAt
+		}
+	}
+
+	return &job, nil
+}
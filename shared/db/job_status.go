@@ -0,0 +1,186 @@
+package db
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// TransitionJobStatus moves a job to a new status of the application pipeline and
+// records the change. It returns ErrInvalidStatus when the pipeline does not allow it.
+func TransitionJobStatus(id int, to models.JobStatus, note string) (*models.JobStatusChange, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var from models.JobStatus
+	err = tx.QueryRow("SELECT status FROM jobs WHERE id = ? FOR UPDATE", id).Scan(&from)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if !from.CanTransitionTo(to) {
+		return nil, ErrInvalidStatus
+	}
+
+	if _, err := tx.Exec("UPDATE jobs SET status = ? WHERE id = ?", to, id); err != nil {
+		return nil, err
+	}
+	// Keep the first application date when a ghosted application comes back
+	if to == models.JobStatusApplied {
+		if _, err := tx.Exec("UPDATE jobs SET applied_at = CURRENT_TIMESTAMP() WHERE id = ? AND applied_at IS NULL", id); err != nil {
+			return nil, err
+		}
+	}
+
+	changeID, err := recordJobStatus(tx, id, from, to, note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.JobStatusChange{
+		Id:        int(changeID),
+		JobId:     id,
+		From:      from,
+		To:        to,
+		Note:      note,
+		ChangedAt: time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// recordJobStatus appends a status change to the history of a job
+func recordJobStatus(ex execer, jobID int, from, to models.JobStatus, note string) (int64, error) {
+	result, err := ex.Exec(
+		"INSERT INTO job_status_history (job_id, from_status, to_status, note) VALUES (?, ?, ?, ?)",
+		jobID, nullString(string(from)), to, nullString(note),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetJobStatusHistory returns the status changes of a job, oldest first
+func GetJobStatusHistory(jobID int) ([]models.JobStatusChange, error) {
+	rows, err := db.Query(
+		"SELECT id, job_id, COALESCE(from_status, ''), to_status, COALESCE(note, ''), changed_at FROM job_status_history WHERE job_id = ? ORDER BY changed_at, id",
+		jobID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.JobStatusChange{}
+	for rows.Next() {
+		var change models.JobStatusChange
+		var changedAtStr string
+		if err := rows.Scan(&change.Id, &change.JobId, &change.From, &change.To, &change.Note, &changedAtStr); err != nil {
+			return nil, err
+		}
+		if changedAt, err := time.Parse("2006-01-02 15:04:05", changedAtStr); err == nil {
+			change.ChangedAt = changedAt
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+// GetJobStatusDates returns, by job ID, when each job first reached each status of its history
+func GetJobStatusDates(jobIDs []int) (map[int]map[models.JobStatus]time.Time, error) {
+	dates := make(map[int]map[models.JobStatus]time.Time, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return dates, nil
+	}
+
+	args := make([]interface{}, len(jobIDs))
+	for i, id := range jobIDs {
+		args[i] = id
+		dates[id] = map[models.JobStatus]time.Time{}
+	}
+	rows, err := db.Query(`
+		SELECT job_id, to_status, MIN(changed_at) FROM job_status_history
+		WHERE job_id IN (`+placeholders(len(jobIDs))+`)
+		GROUP BY job_id, to_status`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobID int
+		var status models.JobStatus
+		var changedAtStr string
+		if err := rows.Scan(&jobID, &status, &changedAtStr); err != nil {
+			return nil, err
+		}
+		if changedAt, err := time.Parse("2006-01-02 15:04:05", changedAtStr); err == nil {
+			dates[jobID][status] = changedAt
+		}
+	}
+	return dates, rows.Err()
+}
+
+// migrateJobStatusColumn turns the original status ENUM into a string column,
+// the allowed values are enforced by the pipeline instead
+func migrateJobStatusColumn() {
+	var columnType string
+	err := db.QueryRow(
+		"SELECT COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'jobs' AND COLUMN_NAME = 'status'",
+	).Scan(&columnType)
+	if err != nil {
+		log.Fatalf("Job status column lookup error: %v", err)
+	}
+	if !strings.HasPrefix(strings.ToLower(columnType), "enum") {
+		return
+	}
+
+	if _, err := db.Exec("ALTER TABLE jobs MODIFY status VARCHAR(32) NOT NULL DEFAULT 'open'"); err != nil {
+		log.Fatalf("Job status column migration error: %v", err)
+	}
+	log.Printf("Migrated jobs.status from %s to VARCHAR(32)", columnType)
+}
+
+// backfillJobStatusHistory gives jobs stored before the history existed their known
+// changes: creation as open, for applications the move to applied, and a final entry
+// when the current status differs from the last recorded one
+func backfillJobStatusHistory() {
+	_, err := db.Exec(`
+		INSERT INTO job_status_history (job_id, from_status, to_status, note, changed_at)
+		SELECT id, NULL, 'open', NULL, created_at FROM jobs j
+		WHERE NOT EXISTS (SELECT 1 FROM job_status_history h WHERE h.job_id = j.id)
+	`)
+	if err != nil {
+		log.Fatalf("Job status history backfill error: %v", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO job_status_history (job_id, from_status, to_status, note, changed_at)
+		SELECT id, 'open', 'applied', NULL, applied_at FROM jobs j
+		WHERE applied_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM job_status_history h WHERE h.job_id = j.id AND h.to_status = 'applied')
+	`)
+	if err != nil {
+		log.Fatalf("Job status history backfill error: %v", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO job_status_history (job_id, from_status, to_status, note)
+		SELECT j.id, h.to_status, j.status, 'Recorded when the status history was introduced' FROM jobs j
+		JOIN job_status_history h ON h.id = (SELECT MAX(id) FROM job_status_history WHERE job_id = j.id)
+		WHERE h.to_status <> j.status
+	`)
+	if err != nil {
+		log.Fatalf("Job status history backfill error: %v", err)
+	}
+}
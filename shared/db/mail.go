@@ -0,0 +1,197 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Mail message operations. Message IDs are unique by their SHA-1 hash, as they can be
+// longer than an index allows.
+
+// GetMailMessageIDs returns the IDs of the messages received or, lacking a date,
+// processed since a time
+func GetMailMessageIDs(since time.Time) (map[string]bool, error) {
+	rows, err := db.Query("SELECT message_id FROM mail_messages WHERE COALESCE(received_at, created_at) >= ?", since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// InsertMailMessage records a processed message and returns its ID. Recording it twice
+// is a no-op that returns 0.
+func InsertMailMessage(msg models.MailMessage) (int64, error) {
+	var receivedAt, jobID interface{}
+	if msg.ReceivedAt != nil {
+		receivedAt = msg.ReceivedAt.UTC().Format("2006-01-02 15:04:05")
+	}
+	if msg.JobId != nil {
+		jobID = *msg.JobId
+	}
+	result, err := db.Exec(
+		`INSERT IGNORE INTO mail_messages (message_id, message_key, sender, subject, kind, template, postings, job_id, received_at)
+			VALUES (?, SHA1(?), ?, ?, ?, ?, ?, ?, ?)`,
+		msg.MessageId, msg.MessageId, truncateRunes(msg.Sender, 255), truncateRunes(msg.Subject, 512),
+		msg.Kind, nullString(msg.Template), msg.Postings, jobID, receivedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ApplicationJob is a job with an application in progress, as matched against messages
+type ApplicationJob struct {
+	JobId         int
+	Title         string
+	Company       string
+	Status        models.JobStatus
+	ContactEmails []string // Lowercase emails of the contacts linked to the job
+}
+
+// GetApplicationJobs returns the jobs in one of statuses with the emails of their contacts
+func GetApplicationJobs(statuses []models.JobStatus) ([]ApplicationJob, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", ")
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+
+	rows, err := db.Query(`
+		SELECT j.id, COALESCE(j.title, ''), COALESCE(j.company, ''), j.status, LOWER(c.email)
+		FROM jobs j
+		LEFT JOIN job_contacts jc ON jc.job_id = j.id
+		LEFT JOIN contacts c ON c.id = jc.contact_id AND c.email IS NOT NULL AND c.email <> ''
+		WHERE j.status IN (`+placeholders+`)
+		ORDER BY j.id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []ApplicationJob
+	for rows.Next() {
+		var job ApplicationJob
+		var email sql.NullString
+		if err := rows.Scan(&job.JobId, &job.Title, &job.Company, &job.Status, &email); err != nil {
+			return nil, err
+		}
+		if n := len(jobs); n == 0 || jobs[n-1].JobId != job.JobId {
+			jobs = append(jobs, job)
+		}
+		if email.Valid {
+			last := &jobs[len(jobs)-1]
+			last.ContactEmails = append(last.ContactEmails, email.String)
+		}
+	}
+	return jobs, rows.Err()
+}
+
+// Status proposal operations
+
+const statusProposalColumns = `p.id, p.job_id, COALESCE(j.title, ''), COALESCE(j.company, ''), p.mail_message_id,
+	COALESCE(m.sender, ''), COALESCE(m.subject, ''), p.kind, p.from_status, p.to_status, p.confidence, p.reason,
+	p.state, p.automatic, p.created_at, p.decided_at`
+
+const statusProposalFrom = ` FROM status_proposals p
+	JOIN jobs j ON j.id = p.job_id
+	LEFT JOIN mail_messages m ON m.id = p.mail_message_id`
+
+func scanStatusProposal(row rowScanner) (*models.StatusProposal, error) {
+	var p models.StatusProposal
+	var createdAtStr string
+	var decidedAt sql.NullString
+	if err := row.Scan(&p.Id, &p.JobId, &p.JobTitle, &p.JobCompany, &p.MailMessageId, &p.Sender, &p.Subject,
+		&p.Kind, &p.From, &p.To, &p.Confidence, &p.Reason, &p.State, &p.Automatic, &createdAtStr, &decidedAt); err != nil {
+		return nil, err
+	}
+	p.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	p.DecidedAt = nullTimePtr(decidedAt)
+	return &p, nil
+}
+
+// GetStatusProposals returns the proposals in a state, newest first
+func GetStatusProposals(state models.StatusProposalState) ([]models.StatusProposal, error) {
+	rows, err := db.Query("SELECT "+statusProposalColumns+statusProposalFrom+" WHERE p.state = ? ORDER BY p.created_at DESC, p.id DESC", state)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	proposals := []models.StatusProposal{}
+	for rows.Next() {
+		p, err := scanStatusProposal(rows)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, *p)
+	}
+	return proposals, rows.Err()
+}
+
+// GetStatusProposalByID returns a proposal
+func GetStatusProposalByID(id int) (*models.StatusProposal, error) {
+	p, err := scanStatusProposal(db.QueryRow("SELECT "+statusProposalColumns+statusProposalFrom+" WHERE p.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return p, err
+}
+
+// InsertStatusProposal records a proposal; proposals that are not pending are recorded as decided now
+func InsertStatusProposal(p models.StatusProposal) (int64, error) {
+	var decidedAt interface{}
+	if p.State != models.StatusProposalPending {
+		decidedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	}
+	result, err := db.Exec(
+		`INSERT INTO status_proposals (job_id, mail_message_id, kind, from_status, to_status, confidence, reason, state, automatic, decided_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.JobId, p.MailMessageId, p.Kind, p.From, p.To, p.Confidence, truncateRunes(p.Reason, 512), p.State, p.Automatic, decidedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// DecideStatusProposal moves a pending proposal to state. It returns ErrNotFound when
+// there is no pending proposal with the ID.
+func DecideStatusProposal(id int, state models.StatusProposalState) error {
+	result, err := db.Exec(
+		"UPDATE status_proposals SET state = ?, decided_at = ? WHERE id = ? AND state = ?",
+		state, time.Now().UTC().Format("2006-01-02 15:04:05"), id, models.StatusProposalPending,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Prompt version history operations
+
+// insertPromptVersion records a snapshot of a prompt inside an open transaction
+func insertPromptVersion(tx *sql.Tx, promptID, version int, name, promptText string) error {
+	_, err := tx.Exec(
+		"INSERT INTO prompt_versions (prompt_id, version, name, prompt, created_at) VALUES (?, ?, ?, ?, ?)",
+		promptID, version, name, promptText, time.Now(),
+	)
+	return err
+}
+
+// scanPromptVersion scans a prompt_versions row into a PromptVersion
+func scanPromptVersion(row rowScanner) (*models.PromptVersion, error) {
+	var version models.PromptVersion
+	var promptText sql.NullString
+	var createdAtStr string
+
+	if err := row.Scan(&version.Id, &version.PromptId, &version.Version, &version.Name, &promptText, &createdAtStr); err != nil {
+		return nil, err
+	}
+
+	if promptText.Valid {
+		version.Prompt = promptText.String
+	}
+	if createdAt, err := time.Parse("2006-01-02 15:04:05", createdAtStr); err == nil {
+		version.CreatedAt = createdAt
+	}
+
+	return &version, nil
+}
+
+// GetPromptVersions lists all versions of a prompt, newest first
+func GetPromptVersions(promptID int) ([]models.PromptVersion, error) {
+	rows, err := db.Query(
+		"SELECT id, prompt_id, version, name, prompt, created_at FROM prompt_versions WHERE prompt_id = ? ORDER BY version DESC",
+		promptID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.PromptVersion
+	for rows.Next() {
+		version, err := scanPromptVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+
+	return versions, nil
+}
+
+// GetPromptVersion gets a single version of a prompt
+func GetPromptVersion(promptID, version int) (*models.PromptVersion, error) {
+	promptVersion, err := scanPromptVersion(db.QueryRow(
+		"SELECT id, prompt_id, version, name, prompt, created_at FROM prompt_versions WHERE prompt_id = ? AND version = ?",
+		promptID, version,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return promptVersion, nil
+}
+
+// GetPromptVersionID returns the prompt_versions id for the current version of a prompt,
+// so generated content can be traced back to the exact text that produced it
+func GetPromptVersionID(prompt *models.Prompt) (*int, error) {
+	version, err := GetPromptVersion(prompt.Id, prompt.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &version.Id, nil
+}
+
+// RollbackPrompt restores the name and text of an earlier version.
+// The restored content is recorded as a new version so the history is never rewritten.
+func RollbackPrompt(promptID, version int) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Lock the prompt first so a concurrent edit cannot land between reading and restoring
+	if err := lockPrompt(tx, promptID); err != nil {
+		return 0, err
+	}
+
+	target, err := scanPromptVersion(tx.QueryRow(
+		"SELECT id, prompt_id, version, name, prompt, created_at FROM prompt_versions WHERE prompt_id = ? AND version = ?",
+		promptID, version,
+	))
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	} else if err != nil {
+		return 0, err
+	}
+
+	current, err := updatePromptContent(tx, promptID, target.Name, target.Prompt)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return current, nil
+}
@@ -0,0 +1,357 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Reminder rule operations
+
+// reminderRuleColumns is the column list of rule queries, in scanReminderRule order
+const reminderRuleColumns = "id, name, kind, COALESCE(status, ''), delay_hours, enabled, created_at, updated_at"
+
+func scanReminderRule(row rowScanner) (*models.ReminderRule, error) {
+	var rule models.ReminderRule
+	var createdAtStr, updatedAtStr string
+	if err := row.Scan(&rule.Id, &rule.Name, &rule.Kind, &rule.Status, &rule.DelayHours, &rule.Enabled, &createdAtStr, &updatedAtStr); err != nil {
+		return nil, err
+	}
+	rule.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	rule.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAtStr)
+	return &rule, nil
+}
+
+// GetReminderRules returns the reminder rules, only the enabled ones if enabledOnly is set
+func GetReminderRules(enabledOnly bool) ([]models.ReminderRule, error) {
+	query := "SELECT " + reminderRuleColumns + " FROM reminder_rules"
+	if enabledOnly {
+		query += " WHERE enabled"
+	}
+	rows, err := db.Query(query + " ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.ReminderRule{}
+	for rows.Next() {
+		rule, err := scanReminderRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+// GetReminderRuleByID returns a reminder rule
+func GetReminderRuleByID(id int) (*models.ReminderRule, error) {
+	rule, err := scanReminderRule(db.QueryRow("SELECT "+reminderRuleColumns+" FROM reminder_rules WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return rule, err
+}
+
+// InsertReminderRule stores a new reminder rule
+func InsertReminderRule(rule models.ReminderRule) (int64, error) {
+	result, err := db.Exec(
+		"INSERT INTO reminder_rules (name, kind, status, delay_hours, enabled) VALUES (?, ?, ?, ?, ?)",
+		rule.Name, rule.Kind, nullString(string(rule.Status)), rule.DelayHours, rule.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// UpdateReminderRule replaces the fields of a reminder rule
+func UpdateReminderRule(rule models.ReminderRule) error {
+	result, err := db.Exec(
+		"UPDATE reminder_rules SET name = ?, kind = ?, status = ?, delay_hours = ?, enabled = ? WHERE id = ?",
+		rule.Name, rule.Kind, nullString(string(rule.Status)), rule.DelayHours, rule.Enabled, rule.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkUpdated(result, func() error {
+		_, err := GetReminderRuleByID(rule.Id)
+		return err
+	})
+}
+
+// DeleteReminderRule removes a reminder rule; its open reminders expire
+func DeleteReminderRule(id int) error {
+	result, err := db.Exec("DELETE FROM reminder_rules WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	_, err = ExpireReminders()
+	return err
+}
+
+// Reminder operations
+
+// maxReminderMessage is the length of the reminders.message column
+const maxReminderMessage = 512
+
+// openReminderStatuses are the statuses of reminders that still need attention
+const openReminderStatuses = "'pending', 'due', 'snoozed'"
+
+// reminderColumns is the column list of reminder queries, in scanReminder order
+const reminderColumns = "r.id, r.rule_id, r.kind, r.job_id, r.ref_id, r.message, r.due_at, r.status, r.snoozed_until, r.notified_at, r.created_at, COALESCE(j.title, ''), COALESCE(j.company, '')"
+
+func scanReminder(row rowScanner) (*models.Reminder, error) {
+	var reminder models.Reminder
+	var dueAtStr, createdAtStr string
+	var snoozedUntil, notifiedAt sql.NullString
+	if err := row.Scan(&reminder.Id, &reminder.RuleId, &reminder.Kind, &reminder.JobId, &reminder.RefId, &reminder.Message,
+		&dueAtStr, &reminder.Status, &snoozedUntil, &notifiedAt, &createdAtStr, &reminder.JobTitle, &reminder.JobCompany); err != nil {
+		return nil, err
+	}
+	reminder.DueAt, _ = time.Parse("2006-01-02 15:04:05", dueAtStr)
+	reminder.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	reminder.SnoozedUntil = nullTimePtr(snoozedUntil)
+	reminder.NotifiedAt = nullTimePtr(notifiedAt)
+	return &reminder, nil
+}
+
+// nullTimePtr parses a nullable DATETIME scanned as string
+func nullTimePtr(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func queryReminders(query string, args ...interface{}) ([]models.Reminder, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []models.Reminder{}
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, *reminder)
+	}
+	return reminders, rows.Err()
+}
+
+// InsertReminder stores a reminder unless its rule already created one for the same
+// source and due time. It reports whether the reminder is new.
+func InsertReminder(reminder models.Reminder) (bool, error) {
+	result, err := db.Exec(
+		"INSERT IGNORE INTO reminders (rule_id, kind, job_id, ref_id, message, due_at) VALUES (?, ?, ?, ?, ?, ?)",
+		reminder.RuleId, reminder.Kind, reminder.JobId, reminder.RefId, truncateRunes(reminder.Message, maxReminderMessage),
+		reminder.DueAt.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with an ellipsis
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(append(runes[:n-1], '…'))
+}
+
+// StaleStatusJob is a job that has been in the same status since ChangedAt
+type StaleStatusJob struct {
+	JobId      int
+	HistoryId  int // Latest status history entry of the job
+	ChangedAt  time.Time
+	JobTitle   string
+	JobCompany string
+}
+
+// GetStaleStatusJobs returns the jobs in status whose last status change happened at or before since
+func GetStaleStatusJobs(status models.JobStatus, since time.Time) ([]StaleStatusJob, error) {
+	rows, err := db.Query(`
+		SELECT j.id, h.id, h.changed_at, COALESCE(j.title, ''), COALESCE(j.company, '')
+		FROM jobs j
+		JOIN job_status_history h ON h.id = (SELECT MAX(id) FROM job_status_history WHERE job_id = j.id)
+		WHERE j.status = ? AND h.changed_at <= ?
+		ORDER BY h.changed_at, j.id`,
+		status, since.UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []StaleStatusJob
+	for rows.Next() {
+		var job StaleStatusJob
+		var changedAtStr string
+		if err := rows.Scan(&job.JobId, &job.HistoryId, &changedAtStr, &job.JobTitle, &job.JobCompany); err != nil {
+			return nil, err
+		}
+		job.ChangedAt, _ = time.Parse("2006-01-02 15:04:05", changedAtStr)
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// ExpireReminders closes open reminders that no longer apply: the job status changed,
+// the interview was rescheduled, decided or removed, or the rule was disabled or removed.
+// It returns the number of expired reminders.
+func ExpireReminders() (int64, error) {
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE reminders r SET r.status = 'expired'
+			WHERE r.kind = ? AND r.status IN (` + openReminderStatuses + `)
+			AND r.ref_id <> COALESCE((SELECT MAX(h.id) FROM job_status_history h WHERE h.job_id = r.job_id), 0)`,
+			[]interface{}{models.ReminderRuleStatusStale}},
+		{`UPDATE reminders r
+			LEFT JOIN interviews i ON i.id = r.ref_id
+			LEFT JOIN reminder_rules rr ON rr.id = r.rule_id
+			SET r.status = 'expired'
+			WHERE r.kind = ? AND r.status IN (` + openReminderStatuses + `)
+			AND (i.id IS NULL OR i.outcome <> ? OR i.scheduled_at < UTC_TIMESTAMP()
+				OR rr.id IS NULL OR r.due_at <> i.scheduled_at - INTERVAL rr.delay_hours HOUR)`,
+			[]interface{}{models.ReminderRuleInterviewUpcoming, models.InterviewOutcomePending}},
+		{`UPDATE reminders r
+			LEFT JOIN reminder_rules rr ON rr.id = r.rule_id
+			SET r.status = 'expired'
+			WHERE r.status IN (` + openReminderStatuses + `) AND (rr.id IS NULL OR NOT rr.enabled)`,
+			nil},
+	}
+
+	var expired int64
+	for _, statement := range statements {
+		result, err := db.Exec(statement.query, statement.args...)
+		if err != nil {
+			return expired, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return expired, err
+		}
+		expired += affected
+	}
+	return expired, nil
+}
+
+// GetRemindersToNotify returns the reminders whose due event has to be published:
+// new ones, and snoozed ones whose snooze ended
+func GetRemindersToNotify() ([]models.Reminder, error) {
+	return queryReminders(
+		"SELECT "+reminderColumns+" FROM reminders r LEFT JOIN jobs j ON j.id = r.job_id "+
+			"WHERE (r.status = ? AND r.due_at <= UTC_TIMESTAMP()) OR (r.status = ? AND r.snoozed_until <= UTC_TIMESTAMP()) "+
+			"ORDER BY r.due_at, r.id",
+		models.ReminderStatusPending, models.ReminderStatusSnoozed,
+	)
+}
+
+// MarkReminderDue records that the due event of a reminder was published
+func MarkReminderDue(id int) error {
+	_, err := db.Exec(
+		"UPDATE reminders SET status = ?, snoozed_until = NULL, notified_at = UTC_TIMESTAMP() WHERE id = ?",
+		models.ReminderStatusDue, id,
+	)
+	return err
+}
+
+// GetReminderByID returns a reminder
+func GetReminderByID(id int) (*models.Reminder, error) {
+	reminder, err := scanReminder(db.QueryRow("SELECT "+reminderColumns+" FROM reminders r LEFT JOIN jobs j ON j.id = r.job_id WHERE r.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return reminder, err
+}
+
+// GetReminders returns the reminders in one of statuses, all reminders without
+// statuses, optionally of one job only; the most recently due first
+func GetReminders(statuses []models.ReminderStatus, jobID int) ([]models.Reminder, error) {
+	var conditions []string
+	var args []interface{}
+	if len(statuses) > 0 {
+		placeholders := make([]string, len(statuses))
+		for i, status := range statuses {
+			placeholders[i] = "?"
+			args = append(args, status)
+		}
+		conditions = append(conditions, "r.status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if jobID > 0 {
+		conditions = append(conditions, "r.job_id = ?")
+		args = append(args, jobID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return queryReminders("SELECT "+reminderColumns+" FROM reminders r LEFT JOIN jobs j ON j.id = r.job_id"+where+" ORDER BY r.due_at DESC, r.id DESC LIMIT 200", args...)
+}
+
+// SnoozeReminder hides an open reminder until the given time, when it is due again
+func SnoozeReminder(id int, until time.Time) error {
+	return closeOrSnoozeReminder(id,
+		"UPDATE reminders SET status = ?, snoozed_until = ? WHERE id = ? AND status IN ("+openReminderStatuses+")",
+		models.ReminderStatusSnoozed, until.UTC().Format("2006-01-02 15:04:05"), id,
+	)
+}
+
+// DismissReminder marks an open reminder as handled
+func DismissReminder(id int) error {
+	return closeOrSnoozeReminder(id,
+		"UPDATE reminders SET status = ?, snoozed_until = NULL WHERE id = ? AND status IN ("+openReminderStatuses+")",
+		models.ReminderStatusDismissed, id,
+	)
+}
+
+// closeOrSnoozeReminder runs an update of an open reminder, telling a missing
+// reminder apart from one that is closed already
+func closeOrSnoozeReminder(id int, query string, args ...interface{}) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	reminder, err := GetReminderByID(id)
+	if err != nil {
+		return err
+	}
+	if reminder.Status == models.ReminderStatusDismissed || reminder.Status == models.ReminderStatusExpired {
+		return ErrReminderClosed
+	}
+	// Unchanged, e.g. snoozed again until the same time
+	return nil
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Job skill operations. The requirements of a job are replaced as a whole each time
+// they are read from its description.
+
+const jobSkillColumns = "id, job_id, kind, name, category, years, required, detail, source"
+
+func scanJobSkill(row rowScanner) (models.JobSkill, error) {
+	var skill models.JobSkill
+	var years sql.NullInt64
+	err := row.Scan(&skill.Id, &skill.JobId, &skill.Kind, &skill.Name, &skill.Category,
+		&years, &skill.Required, &skill.Detail, &skill.Source)
+	skill.Years = nullIntPtr(years)
+	return skill, err
+}
+
+// GetJobSkills returns the requirements of a job in the order they were found
+func GetJobSkills(jobID int) ([]models.JobSkill, error) {
+	rows, err := db.Query("SELECT "+jobSkillColumns+" FROM job_skills WHERE job_id = ? ORDER BY id", jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []models.JobSkill{}
+	for rows.Next() {
+		skill, err := scanJobSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	return skills, rows.Err()
+}
+
+// ReplaceJobSkills stores the requirements read from the description of a job in place
+// of the previous ones and marks the job as extracted
+func ReplaceJobSkills(jobID int, skills []models.JobSkill) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM job_skills WHERE job_id = ?", jobID); err != nil {
+		return err
+	}
+	for _, skill := range skills {
+		var years interface{}
+		if skill.Years != nil {
+			years = *skill.Years
+		}
+		if _, err := tx.Exec(`
+			INSERT IGNORE INTO job_skills (job_id, kind, name, category, years, required, detail, source)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			jobID, skill.Kind, truncateRunes(skill.Name, 128), skill.Category, years, skill.Required,
+			truncateRunes(skill.Detail, 255), skill.Source,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("UPDATE jobs SET skills_extracted_at = CURRENT_TIMESTAMP WHERE id = ?", jobID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PendingSkillsJob is a job whose requirements have not been read from its description
+type PendingSkillsJob struct {
+	JobId       int
+	Description string
+}
+
+// GetJobsPendingSkills returns up to limit jobs whose requirements have not been read
+// since their description last changed, oldest first
+func GetJobsPendingSkills(limit int) ([]PendingSkillsJob, error) {
+	rows, err := db.Query(`
+		SELECT id, COALESCE(description, '') FROM jobs
+		WHERE skills_extracted_at IS NULL
+		ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []PendingSkillsJob
+	for rows.Next() {
+		var job PendingSkillsJob
+		if err := rows.Scan(&job.JobId, &job.Description); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// GetSkillDemand returns the requirements asked for by the most jobs in one of
+// statuses, or in any status when statuses is empty, optionally of one kind only
+func GetSkillDemand(statuses []models.JobStatus, kind models.SkillKind, limit int) ([]models.SkillDemand, error) {
+	var conditions []string
+	var args []interface{}
+	if len(statuses) > 0 {
+		conditions = append(conditions, "j.status IN ("+placeholders(len(statuses))+")")
+		for _, status := range statuses {
+			args = append(args, status)
+		}
+	}
+	if kind != "" {
+		conditions = append(conditions, "js.kind = ?")
+		args = append(args, kind)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+
+	rows, err := db.Query(`
+		SELECT js.kind, js.name, MAX(js.category), COUNT(*), SUM(js.required)
+		FROM job_skills js
+		JOIN jobs j ON j.id = js.job_id
+		`+where+`
+		GROUP BY js.kind, js.name
+		ORDER BY COUNT(*) DESC, SUM(js.required) DESC, js.name
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	demand := []models.SkillDemand{}
+	for rows.Next() {
+		var d models.SkillDemand
+		if err := rows.Scan(&d.Kind, &d.Name, &d.Category, &d.Jobs, &d.RequiredJobs); err != nil {
+			return nil, err
+		}
+		demand = append(demand, d)
+	}
+	return demand, rows.Err()
+}
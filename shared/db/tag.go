@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Tag operations. Tag names are expected to be normalized with models.NormalizeTag.
+
+// placeholders returns n comma separated placeholders for an IN list
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// GetTags returns every tag with the number of jobs it labels, by name
+func GetTags() ([]models.Tag, error) {
+	rows, err := db.Query(`
+		SELECT t.id, t.name, COUNT(jt.job_id)
+		FROM tags t
+		LEFT JOIN job_tags jt ON jt.tag_id = t.id
+		GROUP BY t.id, t.name
+		ORDER BY t.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Id, &tag.Name, &tag.JobCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetJobTags returns the tag names of a job, sorted
+func GetJobTags(jobID int) ([]string, error) {
+	tags, err := getJobTags([]int{jobID})
+	if err != nil {
+		return nil, err
+	}
+	return tags[jobID], nil
+}
+
+// getJobTags returns the sorted tag names of several jobs by job ID, an empty list
+// for jobs without tags
+func getJobTags(jobIDs []int) (map[int][]string, error) {
+	tags := make(map[int][]string, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return tags, nil
+	}
+
+	args := make([]interface{}, len(jobIDs))
+	for i, id := range jobIDs {
+		args[i] = id
+		tags[id] = []string{}
+	}
+	rows, err := db.Query(`
+		SELECT jt.job_id, t.name
+		FROM job_tags jt
+		JOIN tags t ON t.id = jt.tag_id
+		WHERE jt.job_id IN (`+placeholders(len(jobIDs))+`)
+		ORDER BY jt.job_id, t.name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobID int
+		var name string
+		if err := rows.Scan(&jobID, &name); err != nil {
+			return nil, err
+		}
+		tags[jobID] = append(tags[jobID], name)
+	}
+	return tags, rows.Err()
+}
+
+// AddJobTags adds every tag to every job, creating missing tags. Tags a job has
+// already and job IDs that do not exist are skipped.
+func AddJobTags(jobIDs []int, names []string) error {
+	if len(jobIDs) == 0 || len(names) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	nameArgs := make([]interface{}, len(names))
+	for i, name := range names {
+		nameArgs[i] = name
+	}
+	values := strings.TrimSuffix(strings.Repeat("(?), ", len(names)), ", ")
+	if _, err := tx.Exec("INSERT IGNORE INTO tags (name) VALUES "+values, nameArgs...); err != nil {
+		return err
+	}
+
+	args := make([]interface{}, 0, len(jobIDs)+len(names))
+	for _, id := range jobIDs {
+		args = append(args, id)
+	}
+	args = append(args, nameArgs...)
+	if _, err := tx.Exec(`
+		INSERT IGNORE INTO job_tags (job_id, tag_id)
+		SELECT j.id, t.id FROM jobs j JOIN tags t
+		WHERE j.id IN (`+placeholders(len(jobIDs))+`) AND t.name IN (`+placeholders(len(names))+`)`,
+		args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveJobTags removes the tags from the jobs; tags left without jobs are deleted
+func RemoveJobTags(jobIDs []int, names []string) error {
+	if len(jobIDs) == 0 || len(names) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	args := make([]interface{}, 0, len(jobIDs)+len(names))
+	for _, id := range jobIDs {
+		args = append(args, id)
+	}
+	for _, name := range names {
+		args = append(args, name)
+	}
+	if _, err := tx.Exec(`
+		DELETE jt FROM job_tags jt
+		JOIN tags t ON t.id = jt.tag_id
+		WHERE jt.job_id IN (`+placeholders(len(jobIDs))+`) AND t.name IN (`+placeholders(len(names))+`)`,
+		args...); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		DELETE t FROM tags t
+		LEFT JOIN job_tags jt ON jt.tag_id = t.id
+		WHERE jt.tag_id IS NULL AND t.name IN (`+placeholders(len(names))+`)`,
+		args[len(jobIDs):]...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetJobPrompt returns the prompt of a kind to use for a job: the prompt tagged with
+// one of its tags if there is one, the default prompt of the kind otherwise
+func GetJobPrompt(kind models.PromptKind, jobID int) (*models.Prompt, error) {
+	prompt, err := GetTaggedPrompt(kind, jobID)
+	if err == ErrNotFound {
+		return GetDefaultPrompt(kind)
+	}
+	return prompt, err
+}
+
+// GetTaggedPrompt returns the oldest non-archived prompt of a kind tagged with one of
+// the job's tags, ErrNotFound if there is none
+func GetTaggedPrompt(kind models.PromptKind, jobID int) (*models.Prompt, error) {
+	prompt, err := scanPrompt(db.QueryRow(`
+		SELECT `+promptColumns+` FROM prompts
+		WHERE kind = ? AND archived = FALSE AND tag IN (
+			SELECT t.name FROM job_tags jt JOIN tags t ON t.id = jt.tag_id WHERE jt.job_id = ?
+		)
+		ORDER BY id LIMIT 1`, kind, jobID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return prompt, err
+}
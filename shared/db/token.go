@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// API token operations. Tokens are looked up by the SHA-256 hash of their secret.
+
+const apiTokenColumns = "id, name, prefix, created_at, last_used_at"
+
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+	var token models.APIToken
+	var createdAtStr string
+	var lastUsedAt sql.NullString
+	if err := row.Scan(&token.Id, &token.Name, &token.Prefix, &createdAtStr, &lastUsedAt); err != nil {
+		return nil, err
+	}
+	token.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr)
+	if lastUsedAt.Valid {
+		if t, err := time.Parse("2006-01-02 15:04:05", lastUsedAt.String); err == nil {
+			token.LastUsedAt = &t
+		}
+	}
+	return &token, nil
+}
+
+// InsertAPIToken stores a new token by the hash of its secret
+func InsertAPIToken(name, prefix, hash string) (*models.APIToken, error) {
+	result, err := db.Exec("INSERT INTO api_tokens (name, prefix, token_hash) VALUES (?, ?, ?)", name, prefix, hash)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return scanAPIToken(db.QueryRow("SELECT "+apiTokenColumns+" FROM api_tokens WHERE id = ?", id))
+}
+
+// GetAPITokens returns every token, newest first
+func GetAPITokens() ([]models.APIToken, error) {
+	rows, err := db.Query("SELECT " + apiTokenColumns + " FROM api_tokens ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []models.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// UseAPIToken returns the token with the given hash and records its use,
+// ErrNotFound for unknown or revoked tokens
+func UseAPIToken(hash string) (*models.APIToken, error) {
+	token, err := scanAPIToken(db.QueryRow("SELECT "+apiTokenColumns+" FROM api_tokens WHERE token_hash = ?", hash))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec("UPDATE api_tokens SET last_used_at = CURRENT_TIMESTAMP() WHERE id = ?", token.Id); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// DeleteAPIToken revokes a token
+func DeleteAPIToken(id int) error {
+	result, err := db.Exec("DELETE FROM api_tokens WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package evaluation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/render"
+)
+
+// Prompt kinds that can be evaluated against a benchmark set
+const (
+	KindCV    = string(models.PromptKindCV)
+	KindCover = string(models.PromptKindCover)
+)
+
+// Config holds the thresholds used by the automatic checks
+type Config struct {
+	MinWords         int
+	MaxWords         int
+	RequiredSections []string
+	ForbiddenPhrases []string
+	KeywordCount     int // Number of keywords derived from a description without explicit keywords
+}
+
+// DefaultConfig returns the checks for a prompt kind
+func DefaultConfig(kind string) Config {
+	forbidden := []string{
+		"Page 1 of 2",
+		"Page 2 of 2",
+		"as an AI",
+		"language model",
+		"[Your Name]",
+		"[Company Name]",
+		"Here is your",
+		"Here's a tailored",
+	}
+
+	switch kind {
+	case KindCover:
+		return Config{
+			MinWords:         150,
+			MaxWords:         450,
+			RequiredSections: []string{"regards"},
+			ForbiddenPhrases: forbidden,
+			KeywordCount:     10,
+		}
+	default:
+		return Config{
+			MinWords:         450,
+			MaxWords:         1300,
+			RequiredSections: []string{"summary", "experience", "education", "skills", "certificate"},
+			ForbiddenPhrases: forbidden,
+			KeywordCount:     15,
+		}
+	}
+}
+
+// Check is the outcome of a single automatic check on one output
+type Check struct {
+	Name   string  `json:"name"`
+	Passed bool    `json:"passed"`
+	Value  float64 `json:"value"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// JobResult holds the output and checks for one benchmark job
+type JobResult struct {
+	Title   string   `json:"title"`
+	Company string   `json:"company"`
+	Output  string   `json:"output"`
+	Checks  []Check  `json:"checks"`
+	Score   *float64 `json:"score"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Summary aggregates the results of one prompt over a benchmark set
+type Summary struct {
+	PromptId           int         `json:"promptId"`
+	PromptName         string      `json:"promptName"`
+	PromptVersion      int         `json:"promptVersion"`
+	Jobs               int         `json:"jobs"`
+	Failures           int         `json:"failures"`
+	ChecksPassed       int         `json:"checksPassed"`
+	ChecksTotal        int         `json:"checksTotal"`
+	AvgKeywordCoverage float64     `json:"avgKeywordCoverage"`
+	AvgWords           float64     `json:"avgWords"`
+	ForbiddenHits      int         `json:"forbiddenHits"`
+	MissingSections    int         `json:"missingSections"`
+	AvgScore           *float64    `json:"avgScore"`
+	Results            []JobResult `json:"results"`
+}
+
+// Report compares a candidate prompt with the current default of the same kind
+type Report struct {
+	Kind           string             `json:"kind"`
+	BenchmarkSetId int                `json:"benchmarkSetId"`
+	BenchmarkName  string             `json:"benchmarkName"`
+	GeneratedAt    time.Time          `json:"generatedAt"`
+	Candidate      Summary            `json:"candidate"`
+	Baseline       *Summary           `json:"baseline,omitempty"`
+	Delta          map[string]float64 `json:"delta,omitempty"`
+}
+
+// Evaluator runs prompts against benchmark jobs with the configured AI client
+type Evaluator struct {
+	Client      sharedAI.AIClient
+	ScorePrompt string // Score prompt used to rate each output; scoring is skipped when empty
+	Config      Config
+}
+
+// Compare evaluates the candidate and, when given, the baseline prompt over the same benchmark set
+func (e *Evaluator) Compare(kind string, candidate, baseline *models.Prompt, set *models.BenchmarkSet) (*Report, error) {
+	if kind != KindCV && kind != KindCover {
+		return nil, fmt.Errorf("unsupported prompt kind for evaluation: %s", kind)
+	}
+	if len(set.Jobs) == 0 {
+		return nil, fmt.Errorf("benchmark set %d has no jobs", set.Id)
+	}
+
+	report := &Report{
+		Kind:           kind,
+		BenchmarkSetId: set.Id,
+		BenchmarkName:  set.Name,
+		GeneratedAt:    time.Now(),
+		Candidate:      e.Run(candidate, set.Jobs),
+	}
+
+	if baseline != nil {
+		baselineSummary := e.Run(baseline, set.Jobs)
+		report.Baseline = &baselineSummary
+		report.Delta = delta(report.Candidate, baselineSummary)
+	}
+
+	return report, nil
+}
+
+// Run generates and checks an output for every benchmark job
+func (e *Evaluator) Run(prompt *models.Prompt, jobs []models.BenchmarkJob) Summary {
+	summary := Summary{
+		PromptId:      prompt.Id,
+		PromptName:    prompt.Name,
+		PromptVersion: prompt.Version,
+		Jobs:          len(jobs),
+	}
+
+	var coverageTotal, wordsTotal, scoreTotal float64
+	var evaluated, scored int
+
+	for _, job := range jobs {
+		result := JobResult{Title: job.Title, Company: job.Company}
+
+		output, err := e.Client.Generate(BuildPrompt(prompt.Prompt, job))
+		if err != nil {
+			result.Error = err.Error()
+			summary.Failures++
+			summary.Results = append(summary.Results, result)
+			continue
+		}
+		result.Output = output
+		result.Checks = RunChecks(e.Config, output, job)
+
+		for _, check := range result.Checks {
+			summary.ChecksTotal++
+			if check.Passed {
+				summary.ChecksPassed++
+			}
+			switch check.Name {
+			case "keyword_coverage":
+				coverageTotal += check.Value
+			case "length":
+				wordsTotal += check.Value
+			case "forbidden_phrases":
+				summary.ForbiddenHits += int(check.Value)
+			case "required_sections":
+				summary.MissingSections += int(check.Value)
+			}
+		}
+		evaluated++
+
+		if e.ScorePrompt != "" {
+			score, err := e.score(output, job)
+			if err != nil {
+				result.Error = "scoring failed: " + err.Error()
+			} else {
+				result.Score = &score
+				scoreTotal += score
+				scored++
+			}
+		}
+
+		summary.Results = append(summary.Results, result)
+	}
+
+	if evaluated > 0 {
+		summary.AvgKeywordCoverage = coverageTotal / float64(evaluated)
+		summary.AvgWords = wordsTotal / float64(evaluated)
+	}
+	if scored > 0 {
+		avg := scoreTotal / float64(scored)
+		summary.AvgScore = &avg
+	}
+
+	return summary
+}
+
+// score rates an output with the score prompt, in the same format as ScoreGenerator
+func (e *Evaluator) score(output string, job models.BenchmarkJob) (float64, error) {
+	raw, err := e.Client.Generate(render.Score(e.ScorePrompt, job.Description, output))
+	if err != nil {
+		return 0, err
+	}
+	return ParseScore(raw)
+}
+
+// BuildPrompt assembles the generation prompt in the same format as the generators
+func BuildPrompt(promptText string, job models.BenchmarkJob) string {
+	return render.Generation(promptText, job.Title, job.Company, job.Description)
+}
+
+var scorePattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// ParseScore extracts the numeric score from a score prompt response
+func ParseScore(raw string) (float64, error) {
+	match := scorePattern.FindString(raw)
+	if match == "" {
+		return 0, fmt.Errorf("no numeric score in response: %q", raw)
+	}
+	return strconv.ParseFloat(match, 64)
+}
+
+// RunChecks applies all automatic checks to one output
+func RunChecks(config Config, output string, job models.BenchmarkJob) []Check {
+	lowerOutput := strings.ToLower(output)
+	checks := []Check{}
+
+	// Keyword coverage
+	keywords := job.Keywords
+	if len(keywords) == 0 {
+		keywords = ExtractKeywords(job.Description, config.KeywordCount)
+	}
+	if len(keywords) > 0 {
+		var missing []string
+		for _, keyword := range keywords {
+			if !strings.Contains(lowerOutput, strings.ToLower(keyword)) {
+				missing = append(missing, keyword)
+			}
+		}
+		coverage := float64(len(keywords)-len(missing)) / float64(len(keywords))
+		check := Check{Name: "keyword_coverage", Passed: coverage >= 0.6, Value: coverage}
+		if len(missing) > 0 {
+			check.Detail = "missing: " + strings.Join(missing, ", ")
+		}
+		checks = append(checks, check)
+	}
+
+	// Length in words
+	words := len(strings.Fields(output))
+	checks = append(checks, Check{
+		Name:   "length",
+		Passed: words >= config.MinWords && words <= config.MaxWords,
+		Value:  float64(words),
+		Detail: fmt.Sprintf("expected %d-%d words", config.MinWords, config.MaxWords),
+	})
+
+	// Forbidden phrases
+	var hits []string
+	for _, phrase := range config.ForbiddenPhrases {
+		if strings.Contains(lowerOutput, strings.ToLower(phrase)) {
+			hits = append(hits, phrase)
+		}
+	}
+	checks = append(checks, Check{
+		Name:   "forbidden_phrases",
+		Passed: len(hits) == 0,
+		Value:  float64(len(hits)),
+		Detail: strings.Join(hits, ", "),
+	})
+
+	// Required sections
+	if len(config.RequiredSections) > 0 {
+		var missing []string
+		for _, section := range config.RequiredSections {
+			if !strings.Contains(lowerOutput, strings.ToLower(section)) {
+				missing = append(missing, section)
+			}
+		}
+		checks = append(checks, Check{
+			Name:   "required_sections",
+			Passed: len(missing) == 0,
+			Value:  float64(len(missing)),
+			Detail: strings.Join(missing, ", "),
+		})
+	}
+
+	return checks
+}
+
+var wordPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+#.\-]*[A-Za-z0-9+#]`)
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "you": true, "your": true, "our": true, "are": true,
+	"will": true, "this": true, "that": true, "have": true, "has": true, "from": true, "who": true, "all": true,
+	"their": true, "they": true, "can": true, "not": true, "but": true, "more": true, "other": true, "into": true,
+	"about": true, "work": true, "team": true, "role": true, "what": true, "also": true, "such": true, "well": true,
+	"within": true, "across": true, "able": true, "join": true, "help": true, "new": true, "use": true, "using": true,
+	"experience": true, "years": true, "strong": true, "including": true, "based": true, "one": true, "its": true,
+	"we're": true, "you'll": true, "how": true, "who's": true, "plus": true, "etc": true, "skills": true, "ability": true, "working": true,
+}
+
+// ExtractKeywords derives the most frequent meaningful terms of a job description
+func ExtractKeywords(description string, count int) []string {
+	frequency := map[string]int{}
+	firstSeen := map[string]int{}
+
+	for i, word := range wordPattern.FindAllString(description, -1) {
+		word = strings.ToLower(word)
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		if _, ok := firstSeen[word]; !ok {
+			firstSeen[word] = i
+		}
+		frequency[word]++
+	}
+
+	keywords := make([]string, 0, len(frequency))
+	for word := range frequency {
+		keywords = append(keywords, word)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if frequency[keywords[i]] != frequency[keywords[j]] {
+			return frequency[keywords[i]] > frequency[keywords[j]]
+		}
+		return firstSeen[keywords[i]] < firstSeen[keywords[j]]
+	})
+
+	if len(keywords) > count {
+		keywords = keywords[:count]
+	}
+	return keywords
+}
+
+// Fixture strips database identifiers from a benchmark set so it can be exported and imported elsewhere
+func Fixture(set *models.BenchmarkSet) *models.BenchmarkSet {
+	exported := *set
+	exported.Id = 0
+	exported.Jobs = make([]models.BenchmarkJob, len(set.Jobs))
+	for i, job := range set.Jobs {
+		job.Id = 0
+		job.SetId = 0
+		exported.Jobs[i] = job
+	}
+	return &exported
+}
+
+// delta reports candidate minus baseline for the headline metrics
+func delta(candidate, baseline Summary) map[string]float64 {
+	d := map[string]float64{
+		"avgKeywordCoverage": candidate.AvgKeywordCoverage - baseline.AvgKeywordCoverage,
+		"avgWords":           candidate.AvgWords - baseline.AvgWords,
+		"forbiddenHits":      float64(candidate.ForbiddenHits - baseline.ForbiddenHits),
+		"missingSections":    float64(candidate.MissingSections - baseline.MissingSections),
+		"checksPassed":       float64(candidate.ChecksPassed - baseline.ChecksPassed),
+		"failures":           float64(candidate.Failures - baseline.Failures),
+	}
+	if candidate.AvgScore != nil && baseline.AvgScore != nil {
+		d["avgScore"] = *candidate.AvgScore - *baseline.AvgScore
+	}
+	return d
+}
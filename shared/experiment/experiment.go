@@ -0,0 +1,86 @@
+package experiment
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	sharedDB "github.com/hirepilot/shared/db"
+	"github.com/hirepilot/shared/models"
+)
+
+// ResolvePrompt picks the prompt used to generate content of a new job.
+// A prompt tagged with one of the job's tags is used first and keeps the job out of
+// experiments. Otherwise, while an experiment of the kind is active, the job is assigned
+// one of its variants, or else the default prompt of the kind is used. The assignment
+// is nil unless a variant was picked.
+func ResolvePrompt(kind models.PromptKind, jobID int) (*models.Prompt, *models.ExperimentAssignment, error) {
+	tagged, err := sharedDB.GetTaggedPrompt(kind, jobID)
+	if err == nil {
+		return tagged, nil, nil
+	} else if err != sharedDB.ErrNotFound {
+		return nil, nil, err
+	}
+
+	experiment, err := sharedDB.GetActiveExperiment(kind)
+	if err != nil && err != sharedDB.ErrNotFound {
+		return nil, nil, err
+	}
+	if err == nil && len(experiment.Variants) > 0 {
+		prompt, assignment, err := assign(experiment, jobID)
+		if err == nil {
+			return prompt, assignment, nil
+		}
+		// A broken experiment must not block generation for live jobs
+		log.Printf("Experiment %d could not assign job %d, using default %s prompt: %v", experiment.Id, jobID, kind, err)
+	}
+
+	prompt, err := sharedDB.GetDefaultPrompt(kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prompt, nil, nil
+}
+
+// assign reuses the variant a job already got or draws a new one by weight
+func assign(experiment *models.Experiment, jobID int) (*models.Prompt, *models.ExperimentAssignment, error) {
+	assignment, err := sharedDB.GetExperimentAssignment(experiment.Id, jobID)
+	if err == sharedDB.ErrNotFound {
+		variant := pickVariant(experiment.Variants)
+		assignment, err = sharedDB.AssignExperimentVariant(experiment.Id, variant.Id, jobID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, variant := range experiment.Variants {
+		if variant.Id == assignment.VariantId {
+			prompt, err := sharedDB.GetPromptByID(variant.PromptId)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load prompt %d of variant %d: %w", variant.PromptId, variant.Id, err)
+			}
+			return prompt, assignment, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("variant %d not found in experiment %d", assignment.VariantId, experiment.Id)
+}
+
+// pickVariant draws a variant with probability proportional to its weight
+func pickVariant(variants []models.ExperimentVariant) models.ExperimentVariant {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return variants[rand.Intn(len(variants))]
+	}
+
+	n := rand.Intn(total)
+	for _, variant := range variants {
+		if n < variant.Weight {
+			return variant
+		}
+		n -= variant.Weight
+	}
+	return variants[len(variants)-1]
+}
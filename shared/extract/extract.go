@@ -0,0 +1,419 @@
+// Package extract reads job postings from the HTML of posting pages
+package extract
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNoPosting is returned for pages without a recognizable job title
+var ErrNoPosting = errors.New("no job posting found on the page")
+
+// page holds the parts of a document the extractors look at
+type page struct {
+	jsonLD []string          // Contents of the application/ld+json scripts
+	meta   map[string]string // Meta tag contents by lower case property or name, first one wins
+	title  string            // Document title
+	h1     string            // First top-level heading
+	main   *html.Node        // The article or main element, if any
+}
+
+// Page extracts a job posting from the HTML of the page at pageURL. Pages of known job
+// sites go through their site parser first. Structured data fills the gaps: a
+// schema.org JobPosting in JSON-LD, then OpenGraph and other meta tags, and the
+// document title and headings last. Link is always pageURL.
+func Page(r io.Reader, pageURL string) (models.JobPosting, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return models.JobPosting{}, err
+	}
+	return fromDocument(doc, pageURL)
+}
+
+func fromDocument(doc *html.Node, pageURL string) (models.JobPosting, error) {
+	pg := readPage(doc)
+
+	p := models.JobPosting{Link: pageURL}
+	for _, script := range pg.jsonLD {
+		if ld := findJobPosting(script); ld != nil {
+			p = fromJSONLD(ld, pageURL)
+			break
+		}
+	}
+	if s := siteFor(pageURL); s != nil {
+		if u, err := url.Parse(pageURL); err == nil {
+			p = Merge(s.parse(doc, u), p)
+			p.Link = pageURL
+		}
+	}
+	fromMeta(&p, pg)
+
+	if p.Title == "" {
+		return p, ErrNoPosting
+	}
+	if p.Seniority == "" {
+		p.Seniority = posting.ParseSeniority(p.Title)
+	}
+	if p.WorkplaceType == "" {
+		p.WorkplaceType = posting.ParseWorkplaceType(p.Location)
+	}
+	return p, nil
+}
+
+func readPage(doc *html.Node) page {
+	pg := page{meta: map[string]string{}}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Script:
+				if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+					pg.jsonLD = append(pg.jsonLD, n.FirstChild.Data)
+				}
+				return
+			case atom.Style, atom.Noscript, atom.Template:
+				return
+			case atom.Meta:
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				if content := strings.TrimSpace(attr(n, "content")); key != "" && content != "" {
+					if _, ok := pg.meta[key]; !ok {
+						pg.meta[key] = content
+					}
+				}
+			case atom.Title:
+				if pg.title == "" {
+					pg.title = collapse(nodeText(n))
+				}
+			case atom.H1:
+				if pg.h1 == "" {
+					pg.h1 = collapse(nodeText(n))
+				}
+			case atom.Article, atom.Main:
+				if pg.main == nil {
+					pg.main = n
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return pg
+}
+
+// fromMeta fills the fields structured data left empty from meta tags and headings
+func fromMeta(p *models.JobPosting, pg page) {
+	site := firstOf(pg.meta["og:site_name"], pg.meta["application-name"])
+
+	if p.Title == "" {
+		title := firstOf(pg.meta["og:title"], pg.meta["twitter:title"], pg.title)
+		title, company, location := splitTitle(title, site)
+		if title == "" {
+			title = pg.h1
+		}
+		p.Title = title
+		if p.Company == "" {
+			p.Company = company
+		}
+		if p.Location == "" {
+			p.Location = location
+		}
+	}
+	if p.Company == "" {
+		p.Company = site
+	}
+	if p.Description == "" && pg.main != nil {
+		p.Description = Text(pg.main)
+	}
+	if p.Description == "" {
+		p.Description = firstOf(pg.meta["og:description"], pg.meta["twitter:description"], pg.meta["description"])
+	}
+}
+
+// splitTitle reads page titles such as "Backend Engineer at Acme | Site" or
+// "Acme hiring Backend Engineer in Berlin | LinkedIn". The site name suffix is
+// dropped; parts that cannot be told apart are returned as the title.
+func splitTitle(text, site string) (title, company, location string) {
+	text = collapse(text)
+	for _, sep := range titleSeparators {
+		if i := strings.LastIndex(text, sep); i > 0 {
+			suffix := strings.TrimSpace(text[i+len(sep):])
+			if site != "" && strings.EqualFold(suffix, site) || strings.EqualFold(suffix, "LinkedIn") {
+				text = strings.TrimSpace(text[:i])
+				break
+			}
+		}
+	}
+
+	if company, rest, ok := strings.Cut(text, " hiring "); ok {
+		title, location, _ = strings.Cut(rest, " in ")
+		return strings.TrimSpace(title), strings.TrimSpace(company), strings.TrimSpace(location)
+	}
+	if i := strings.LastIndex(text, " at "); i > 0 {
+		// Anything after the company is a page or site name, e.g. "Acme - Careers"
+		company = text[i+len(" at "):]
+		for _, sep := range titleSeparators {
+			company, _, _ = strings.Cut(company, sep)
+		}
+		return strings.TrimSpace(text[:i]), strings.TrimSpace(company), ""
+	}
+	return text, "", ""
+}
+
+var titleSeparators = []string{" | ", " - ", " – ", " — "}
+
+// findJobPosting returns the first JobPosting object of a JSON-LD script, looking
+// into arrays and @graph lists
+func findJobPosting(script string) map[string]interface{} {
+	var data interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(script)), &data); err != nil {
+		return nil
+	}
+	var find func(v interface{}) map[string]interface{}
+	find = func(v interface{}) map[string]interface{} {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				if found := find(item); found != nil {
+					return found
+				}
+			}
+		case map[string]interface{}:
+			if hasType(v, "JobPosting") {
+				return v
+			}
+			if graph, ok := v["@graph"]; ok {
+				return find(graph)
+			}
+		}
+		return nil
+	}
+	return find(data)
+}
+
+func hasType(obj map[string]interface{}, name string) bool {
+	for _, t := range ldStrings(obj["@type"]) {
+		if t == name || strings.HasSuffix(t, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// fromJSONLD maps a schema.org JobPosting to a posting
+func fromJSONLD(ld map[string]interface{}, pageURL string) models.JobPosting {
+	p := models.JobPosting{
+		Link:  pageURL,
+		Title: collapse(html.UnescapeString(ldString(ld["title"]))),
+	}
+	if org, ok := ld["hiringOrganization"].(map[string]interface{}); ok {
+		p.Company = collapse(ldString(org["name"]))
+	} else {
+		p.Company = collapse(ldString(ld["hiringOrganization"]))
+	}
+	if description := ldString(ld["description"]); description != "" {
+		p.Description = FragmentText(description)
+	}
+
+	p.Location = ldLocation(ld["jobLocation"])
+	for _, locationType := range ldStrings(ld["jobLocationType"]) {
+		if strings.EqualFold(locationType, "TELECOMMUTE") {
+			p.WorkplaceType = models.WorkplaceRemote
+		}
+	}
+	for _, employmentType := range ldStrings(ld["employmentType"]) {
+		if t := ldEmploymentTypes[strings.ToUpper(strings.ReplaceAll(employmentType, "-", "_"))]; t != "" {
+			p.EmploymentType = t
+			break
+		}
+	}
+	if posted := ldDate(ldString(ld["datePosted"])); posted != nil {
+		p.PostedAt = posted
+	}
+	p.SalaryMin, p.SalaryMax, p.SalaryCurrency = ldSalary(ld["baseSalary"])
+	return p
+}
+
+var ldEmploymentTypes = map[string]models.EmploymentType{
+	"FULL_TIME":  models.EmploymentFullTime,
+	"PART_TIME":  models.EmploymentPartTime,
+	"CONTRACTOR": models.EmploymentContract,
+	"CONTRACT":   models.EmploymentContract,
+	"TEMPORARY":  models.EmploymentTemporary,
+	"INTERN":     models.EmploymentInternship,
+	"INTERNSHIP": models.EmploymentInternship,
+}
+
+// ldLocation joins the locality, region and country of each job location
+func ldLocation(v interface{}) string {
+	var places []string
+	var add func(v interface{})
+	add = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				add(item)
+			}
+		case string:
+			if s := collapse(v); s != "" {
+				places = append(places, s)
+			}
+		case map[string]interface{}:
+			address, ok := v["address"].(map[string]interface{})
+			if !ok {
+				add(v["address"])
+				return
+			}
+			var parts []string
+			for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+				part := ldString(address[key])
+				if country, ok := address[key].(map[string]interface{}); ok {
+					part = ldString(country["name"])
+				}
+				if part = collapse(part); part != "" && !contains(parts, part) {
+					parts = append(parts, part)
+				}
+			}
+			if len(parts) > 0 {
+				places = append(places, strings.Join(parts, ", "))
+			}
+		}
+	}
+	add(v)
+	return strings.Join(places, "; ")
+}
+
+// ldSalary reads a MonetaryAmount with a single value or a range. Amounts per hour,
+// day, week or month are left out since salaries are stored per year.
+func ldSalary(v interface{}) (min, max *float64, currency string) {
+	amount, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil, ""
+	}
+	value, ok := amount["value"].(map[string]interface{})
+	if !ok {
+		value = map[string]interface{}{"value": amount["value"]}
+	}
+	if unit := strings.ToUpper(ldString(value["unitText"])); unit != "" && unit != "YEAR" {
+		return nil, nil, ""
+	}
+
+	min, max = ldNumber(value["minValue"]), ldNumber(value["maxValue"])
+	if single := ldNumber(value["value"]); single != nil && min == nil && max == nil {
+		min, max = single, single
+	}
+	if min == nil && max == nil {
+		return nil, nil, ""
+	}
+	currency = strings.ToUpper(ldString(amount["currency"]))
+	if len(currency) != 3 {
+		currency = ""
+	}
+	return min, max, currency
+}
+
+func ldNumber(v interface{}) *float64 {
+	switch v := v.(type) {
+	case float64:
+		return &v
+	case string:
+		if f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64); err == nil {
+			return &f
+		}
+	}
+	return nil
+}
+
+func ldDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	if len(s) >= len("2006-01-02") {
+		if t, err := time.Parse("2006-01-02", s[:len("2006-01-02")]); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// ldString returns a JSON-LD value as text: strings as is, numbers formatted, and the
+// first item of lists
+func ldString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		if len(v) > 0 {
+			return ldString(v[0])
+		}
+	}
+	return ""
+}
+
+// ldStrings returns a JSON-LD value that may be a single string or a list of them
+func ldStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{strings.TrimSpace(v)}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v = collapse(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// collapse trims s and replaces runs of white space with a single space
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,43 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// maxPageSize bounds the HTML read from a posting page
+const maxPageSize = 5 << 20
+
+// Fetch downloads a posting page and extracts the posting from its HTML. Pages at
+// URLs given by users must be downloaded with a client from NewClient.
+func Fetch(ctx context.Context, client *http.Client, pageURL string) (job models.JobPosting, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return job, err
+	}
+	if err := checkScheme(req.URL); err != nil {
+		return job, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; HirePilot/1.0)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return job, fmt.Errorf("could not download the page: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return job, fmt.Errorf("the page answered with status %d", resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "" && !strings.Contains(mediaType, "html") {
+		return job, fmt.Errorf("the page is %s, not HTML", mediaType)
+	}
+
+	return Page(io.LimitReader(resp.Body, maxPageSize), pageURL)
+}
@@ -0,0 +1,161 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// newFixtureServer serves the saved posting pages in testdata
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle("/jobs/", http.StripPrefix("/jobs/", http.FileServer(http.Dir("testdata"))))
+	mux.HandleFunc("/feed.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jobs":[]}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetch(t *testing.T) {
+	server := newFixtureServer(t)
+	salaryMin, salaryMax := 70000.0, 90000.0
+	posted := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		page string
+		want models.JobPosting
+	}{
+		{
+			page: "jsonld.html",
+			want: models.JobPosting{
+				Title:       "Senior Go Engineer",
+				Company:     "Acme B.V.",
+				Description: "Build APIs in Go.\n\n- Kubernetes\n- MySQL",
+				JobDetails: models.JobDetails{
+					Location:       "Amsterdam, North Holland, NL",
+					EmploymentType: models.EmploymentFullTime,
+					SalaryMin:      &salaryMin,
+					SalaryMax:      &salaryMax,
+					SalaryCurrency: "EUR",
+					PostedAt:       &posted,
+				},
+			},
+		},
+		{
+			page: "opengraph.html",
+			want: models.JobPosting{
+				Title:       "Product Designer",
+				Company:     "Globex",
+				Description: "Design the tools our customers use every day. Remote within Europe.",
+			},
+		},
+		{
+			page: "heuristic.html",
+			want: models.JobPosting{
+				Title:       "Data Analyst",
+				Company:     "Initech",
+				Description: "Data Analyst\n\nTurn our TPS reports into insight.\n\n- SQL\n- Excel",
+				JobDetails:  models.JobDetails{Location: "Austin, TX"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			pageURL := server.URL + "/jobs/" + tt.page
+			got, err := Fetch(context.Background(), server.Client(), pageURL)
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+
+			tt.want.Link = pageURL
+			if got.Title != tt.want.Title || got.Company != tt.want.Company || got.Link != tt.want.Link {
+				t.Errorf("got %q at %q (%s), want %q at %q (%s)", got.Title, got.Company, got.Link, tt.want.Title, tt.want.Company, tt.want.Link)
+			}
+			if got.Description != tt.want.Description {
+				t.Errorf("description = %q, want %q", got.Description, tt.want.Description)
+			}
+			if got.Location != tt.want.Location || got.EmploymentType != tt.want.EmploymentType || got.SalaryCurrency != tt.want.SalaryCurrency {
+				t.Errorf("details = %+v, want %+v", got.JobDetails, tt.want.JobDetails)
+			}
+			if !equalFloat(got.SalaryMin, tt.want.SalaryMin) || !equalFloat(got.SalaryMax, tt.want.SalaryMax) {
+				t.Errorf("salary = %v-%v, want %v-%v", got.SalaryMin, got.SalaryMax, tt.want.SalaryMin, tt.want.SalaryMax)
+			}
+			if (got.PostedAt == nil) != (tt.want.PostedAt == nil) || got.PostedAt != nil && !got.PostedAt.Equal(*tt.want.PostedAt) {
+				t.Errorf("postedAt = %v, want %v", got.PostedAt, tt.want.PostedAt)
+			}
+		})
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	server := newFixtureServer(t)
+
+	tests := []struct {
+		name    string
+		client  *http.Client
+		pageURL string
+		wantErr error
+	}{
+		{"missing page", server.Client(), server.URL + "/jobs/missing.html", nil},
+		{"not html", server.Client(), server.URL + "/feed.json", nil},
+		{"unsupported scheme", server.Client(), "file:///etc/passwd", nil},
+		{"loopback", NewClient(5 * time.Second), server.URL + "/jobs/jsonld.html", ErrBlockedAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Fetch(context.Background(), tt.client, tt.pageURL)
+			if err == nil {
+				t.Fatal("Fetch succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Fetch error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.18.0.5", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func equalFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
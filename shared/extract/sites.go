@@ -0,0 +1,280 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Names of the page parsers, as reported by Parser
+const (
+	ParserLinkedIn   = "linkedin"
+	ParserIndeed     = "indeed"
+	ParserGreenhouse = "greenhouse"
+	ParserLever      = "lever"
+	ParserGeneric    = "generic" // JSON-LD, meta tags and headings only
+)
+
+// site is a parser for the posting pages of one job site. Fields it leaves empty are
+// filled by the generic parser.
+type site struct {
+	name  string
+	match func(host string) bool
+	parse func(doc *html.Node, pageURL *url.URL) models.JobPosting
+}
+
+var sites = []site{
+	{ParserLinkedIn, hostIs("linkedin.com"), parseLinkedIn},
+	{ParserIndeed, hostIs("indeed.com"), parseIndeed},
+	{ParserGreenhouse, hostIs("greenhouse.io"), parseGreenhouse},
+	{ParserLever, hostIs("lever.co"), parseLever},
+}
+
+// Parser names the parser used for pages at pageURL
+func Parser(pageURL string) string {
+	if s := siteFor(pageURL); s != nil {
+		return s.name
+	}
+	return ParserGeneric
+}
+
+func siteFor(pageURL string) *site {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for i := range sites {
+		if sites[i].match(host) {
+			return &sites[i]
+		}
+	}
+	return nil
+}
+
+// hostIs matches a domain and its subdomains, including country domains such as
+// uk.indeed.com or de.linkedin.com
+func hostIs(domain string) func(host string) bool {
+	return func(host string) bool {
+		return host == domain || strings.HasSuffix(host, "."+domain)
+	}
+}
+
+func parseLinkedIn(doc *html.Node, pageURL *url.URL) models.JobPosting {
+	p := models.JobPosting{
+		Title: findText(doc,
+			byClass("top-card-layout__title"), byClass("topcard__title"),
+			byClass("job-details-jobs-unified-top-card__job-title"), byClass("jobs-unified-top-card__job-title")),
+		Company: findText(doc,
+			byClass("topcard__org-name-link"), byClass("job-details-jobs-unified-top-card__company-name"),
+			byClass("jobs-unified-top-card__company-name")),
+		Description: findLayout(doc,
+			byClass("show-more-less-html__markup"), byClass("jobs-description__content"),
+			byClass("jobs-description-content__text"), byID("job-details")),
+	}
+	p.Location = findText(doc,
+		byClass("topcard__flavor--bullet"), byClass("job-details-jobs-unified-top-card__bullet"),
+		byClass("jobs-unified-top-card__bullet"))
+
+	// The newer top card lists "Berlin, Germany · 2 weeks ago · 80 applicants"
+	primary := findText(doc, byClass("job-details-jobs-unified-top-card__primary-description-container"),
+		byClass("job-details-jobs-unified-top-card__primary-description"))
+	if p.Location == "" && primary != "" {
+		p.Location, _, _ = strings.Cut(primary, " · ")
+		p.Location = strings.TrimSpace(p.Location)
+	}
+
+	// Job insights and criteria hold salary, workplace, employment type and seniority
+	insights := []string{primary}
+	for _, n := range findAll(doc, byClass("job-details-jobs-unified-top-card__job-insight"),
+		byClass("job-details-preferences-and-skills"), byClass("description__job-criteria-list"),
+		byClass("posted-time-ago__text")) {
+		insights = append(insights, Text(n))
+	}
+	p.JobDetails = mergeDetails(p.JobDetails, posting.ParseDetails(strings.Join(insights, " · "), time.Now()))
+	return p
+}
+
+func parseIndeed(doc *html.Node, pageURL *url.URL) models.JobPosting {
+	title := findText(doc, byAttr("data-testid", "jobsearch-JobInfoHeader-title"), byClass("jobsearch-JobInfoHeader-title"))
+	p := models.JobPosting{
+		Title: strings.TrimSpace(strings.TrimSuffix(title, "- job post")),
+		Company: findText(doc,
+			byAttr("data-testid", "inlineHeader-companyName"), byAttr("data-company-name", "true"),
+			byClass("jobsearch-CompanyInfoContainer")),
+		Description: findLayout(doc, byID("jobDescriptionText")),
+	}
+	p.Location = findText(doc,
+		byAttr("data-testid", "inlineHeader-companyLocation"), byAttr("data-testid", "job-location"),
+		byAttr("data-testid", "jobsearch-JobInfoHeader-companyLocation"))
+	details := findText(doc, byID("salaryInfoAndJobType"), byAttr("data-testid", "jobsearch-OtherJobDetailsContainer"))
+	p.JobDetails = mergeDetails(p.JobDetails, posting.ParseDetails(details, time.Now()))
+	return p
+}
+
+func parseGreenhouse(doc *html.Node, pageURL *url.URL) models.JobPosting {
+	p := models.JobPosting{
+		Title:       findText(doc, byClass("app-title"), byClass("job__title"), byClass("section-header")),
+		Company:     strings.TrimPrefix(findText(doc, byClass("company-name")), "at "),
+		Description: findLayout(doc, byClass("job__description"), byClass("job-post-content"), byID("content")),
+	}
+	p.Location = findText(doc, byClass("location"), byClass("job__location"))
+	if p.Company == "" {
+		// Board URLs name the company: boards.greenhouse.io/{company}/jobs/{id}
+		p.Company = pathCompany(pageURL)
+	}
+	return p
+}
+
+func parseLever(doc *html.Node, pageURL *url.URL) models.JobPosting {
+	var p models.JobPosting
+	if headline := find(doc, byClass("posting-headline")); headline != nil {
+		p.Title = findText(headline, byTag(atom.H2), byTag(atom.H1))
+	}
+	p.Location = findText(doc, byClass("sort-by-location"), byClass("location"))
+	p.EmploymentType = posting.ParseEmploymentType(findText(doc, byClass("sort-by-commitment"), byClass("commitment")))
+	p.WorkplaceType = posting.ParseWorkplaceType(findText(doc, byClass("workplaceTypes")))
+	if description := find(doc, byAttr("data-qa", "job-description")); description != nil {
+		p.Description = Text(description.Parent)
+	}
+
+	// Page titles read "Acme - Backend Engineer"
+	if title := find(doc, byTag(atom.Title)); title != nil {
+		if company, _, ok := strings.Cut(collapse(nodeText(title)), " - "); ok {
+			p.Company = company
+		}
+	}
+	if p.Company == "" {
+		p.Company = pathCompany(pageURL)
+	}
+	return p
+}
+
+// pathCompany returns the company named by the first path segment of a job board URL
+func pathCompany(u *url.URL) string {
+	segment, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	return strings.TrimSpace(strings.ReplaceAll(segment, "-", " "))
+}
+
+// Merge fills the empty fields of p from other
+func Merge(p, other models.JobPosting) models.JobPosting {
+	if p.Title == "" {
+		p.Title = other.Title
+	}
+	if p.Company == "" {
+		p.Company = other.Company
+	}
+	if p.Description == "" {
+		p.Description = other.Description
+	}
+	p.JobDetails = mergeDetails(p.JobDetails, other.JobDetails)
+	return p
+}
+
+// mergeDetails fills the empty attributes of d from other
+func mergeDetails(d, other models.JobDetails) models.JobDetails {
+	if d.Location == "" {
+		d.Location = other.Location
+	}
+	if d.WorkplaceType == "" {
+		d.WorkplaceType = other.WorkplaceType
+	}
+	if d.EmploymentType == "" {
+		d.EmploymentType = other.EmploymentType
+	}
+	if d.Seniority == "" {
+		d.Seniority = other.Seniority
+	}
+	if d.SalaryMin == nil && d.SalaryMax == nil {
+		d.SalaryMin, d.SalaryMax, d.SalaryCurrency = other.SalaryMin, other.SalaryMax, other.SalaryCurrency
+	}
+	if d.PostedAt == nil {
+		d.PostedAt = other.PostedAt
+	}
+	return d
+}
+
+// matcher selects elements of a page
+type matcher func(n *html.Node) bool
+
+func byClass(class string) matcher {
+	return func(n *html.Node) bool {
+		for _, c := range strings.Fields(attr(n, "class")) {
+			if c == class {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func byID(id string) matcher {
+	return func(n *html.Node) bool { return attr(n, "id") == id }
+}
+
+func byAttr(name, value string) matcher {
+	return func(n *html.Node) bool { return attr(n, name) == value }
+}
+
+func byTag(tag atom.Atom) matcher {
+	return func(n *html.Node) bool { return n.DataAtom == tag }
+}
+
+// find returns the first element below root for the first matcher that matches any
+func find(root *html.Node, matchers ...matcher) *html.Node {
+	for _, match := range matchers {
+		if found := findAll(root, match); len(found) > 0 {
+			return found[0]
+		}
+	}
+	return nil
+}
+
+// findAll returns the elements below root that match any of the matchers, in
+// document order, without descending into matched elements
+func findAll(root *html.Node, matchers ...matcher) []*html.Node {
+	var found []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			matched := false
+			for _, match := range matchers {
+				if match(c) {
+					matched = true
+					break
+				}
+			}
+			if matched {
+				found = append(found, c)
+			} else {
+				walk(c)
+			}
+		}
+	}
+	walk(root)
+	return found
+}
+
+// findText returns the text of the first element found, on one line
+func findText(root *html.Node, matchers ...matcher) string {
+	if n := find(root, matchers...); n != nil {
+		return collapse(Text(n))
+	}
+	return ""
+}
+
+// findLayout returns the text of the first element found with its paragraphs and lists
+func findLayout(root *html.Node, matchers ...matcher) string {
+	if n := find(root, matchers...); n != nil {
+		return Text(n)
+	}
+	return ""
+}
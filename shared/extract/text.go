@@ -0,0 +1,165 @@
+package extract
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// lineElements start on a new line of text, paragraphElements after an empty line
+var (
+	lineElements = map[atom.Atom]bool{
+		atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true, atom.Header: true,
+		atom.Footer: true, atom.Li: true, atom.Tr: true, atom.Dt: true, atom.Dd: true, atom.Br: true,
+	}
+	paragraphElements = map[atom.Atom]bool{
+		atom.P: true, atom.Ul: true, atom.Ol: true, atom.Dl: true, atom.Table: true,
+		atom.Blockquote: true, atom.Pre: true, atom.Hr: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	}
+)
+
+// skippedElements hold no readable text
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Svg: true, atom.Button: true, atom.Form: true, atom.Nav: true,
+}
+
+// FragmentText turns an HTML fragment, such as a JSON-LD description, into plain text.
+// Fragments that were HTML escaped once more are unescaped first, plain text keeps
+// its lines.
+func FragmentText(fragment string) string {
+	if !strings.Contains(fragment, "<") {
+		if !strings.Contains(fragment, "&lt;") {
+			return plainText(fragment)
+		}
+		fragment = html.UnescapeString(fragment)
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return collapse(fragment)
+	}
+	var w textWriter
+	for _, n := range nodes {
+		w.node(n)
+	}
+	return w.String()
+}
+
+// Text returns the readable text of a node: paragraphs separated by empty lines, list
+// items prefixed with a dash, and navigation, forms and scripts left out
+func Text(n *html.Node) string {
+	var w textWriter
+	w.node(n)
+	return w.String()
+}
+
+// textWriter lays out text, holding line breaks back until the next text so that
+// white space between elements does not add empty lines
+type textWriter struct {
+	b      strings.Builder
+	breaks int    // Pending line breaks: 1 for a new line, 2 for a new paragraph
+	prefix string // Written before the next text, e.g. a list item dash
+	space  bool   // Pending space between words
+}
+
+func (w *textWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] {
+			return
+		}
+	}
+
+	breaks := 0
+	if n.Type == html.ElementNode {
+		if paragraphElements[n.DataAtom] {
+			breaks = 2
+		} else if lineElements[n.DataAtom] {
+			breaks = 1
+		}
+	}
+	w.lineBreak(breaks)
+	if n.DataAtom == atom.Li {
+		w.prefix = "- "
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+	w.lineBreak(breaks)
+	if n.DataAtom == atom.Td || n.DataAtom == atom.Th {
+		w.space = true
+	}
+}
+
+func (w *textWriter) lineBreak(n int) {
+	if n > w.breaks {
+		w.breaks = n
+	}
+}
+
+func (w *textWriter) text(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		w.space = w.space || s != ""
+		return
+	}
+	if w.b.Len() > 0 {
+		if w.breaks > 0 {
+			w.b.WriteString(strings.Repeat("\n", w.breaks))
+		} else if w.space || startsWithSpace(s) {
+			w.b.WriteString(" ")
+		}
+	}
+	w.b.WriteString(w.prefix)
+	w.b.WriteString(strings.Join(words, " "))
+	w.breaks, w.prefix = 0, ""
+	w.space = endsWithSpace(s)
+}
+
+func (w *textWriter) String() string {
+	return w.b.String()
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n\f") != s
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n\f") != s
+}
+
+// nodeText returns the text below a node without any layout
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// plainText collapses the white space within the lines of text and drops empty lines
+// beyond the first between paragraphs
+func plainText(text string) string {
+	var w textWriter
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			w.lineBreak(2)
+			continue
+		}
+		w.lineBreak(1)
+		w.text(line)
+	}
+	return w.String()
+}
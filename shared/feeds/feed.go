@@ -0,0 +1,170 @@
+// Package feeds reads RSS and Atom feeds of job openings
+package feeds
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ErrNotAFeed is returned for documents that are neither RSS nor Atom
+var ErrNotAFeed = errors.New("not an RSS or Atom feed")
+
+// Item is an entry of a feed. GUID is never empty: items without an ID are identified
+// by their link, or by their title if they have no link either. Description is HTML
+// or text as published.
+type Item struct {
+	GUID        string
+	Title       string
+	Link        string
+	Description string
+	Author      string
+	Categories  []string
+	Published   *time.Time
+}
+
+// document covers RSS 2.0, RSS 1.0 (RDF), whose items follow the channel, and Atom.
+// Elements are matched by local name, whatever their namespace.
+type document struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	About       string   `xml:"about,attr"` // RSS 1.0 item ID
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Author    struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+// Parse reads the items of an RSS or Atom feed. Feeds in other encodings than UTF-8
+// and feeds using HTML entities are accepted.
+func Parse(r io.Reader) ([]Item, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	var doc document
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss", "rdf":
+		for _, it := range append(doc.Channel.Items, doc.Items...) {
+			items = append(items, it.item())
+		}
+	case "feed":
+		for _, entry := range doc.Entries {
+			items = append(items, entry.item())
+		}
+	default:
+		return nil, ErrNotAFeed
+	}
+	return items, nil
+}
+
+func (it rssItem) item() Item {
+	item := Item{
+		GUID:        firstOf(it.GUID, it.About, it.Link),
+		Title:       strings.TrimSpace(it.Title),
+		Link:        strings.TrimSpace(it.Link),
+		Description: firstOf(it.Content, it.Description),
+		Author:      firstOf(it.Creator, it.Author),
+		Published:   parseDate(firstOf(it.PubDate, it.Date)),
+	}
+	for _, category := range it.Categories {
+		if category = strings.TrimSpace(category); category != "" {
+			item.Categories = append(item.Categories, category)
+		}
+	}
+	if item.GUID == "" {
+		item.GUID = item.Title
+	}
+	return item
+}
+
+func (e atomEntry) item() Item {
+	item := Item{
+		Title:       strings.TrimSpace(e.Title),
+		Description: firstOf(e.Content, e.Summary),
+		Author:      strings.TrimSpace(e.Author.Name),
+		Published:   parseDate(firstOf(e.Published, e.Updated)),
+	}
+	for _, link := range e.Links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			item.Link = strings.TrimSpace(link.Href)
+			break
+		}
+	}
+	for _, category := range e.Categories {
+		if term := strings.TrimSpace(category.Term); term != "" {
+			item.Categories = append(item.Categories, term)
+		}
+	}
+	item.GUID = firstOf(e.ID, item.Link, item.Title)
+	return item
+}
+
+// dateLayouts are the RFC 822 dates of RSS, with and without weekday or seconds, and
+// the RFC 3339 dates of Atom and Dublin Core
+var dateLayouts = []string{
+	time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04 -0700", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02",
+}
+
+func parseDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// firstOf returns the first value that is not blank, trimmed
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
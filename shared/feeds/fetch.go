@@ -0,0 +1,61 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxFeedSize bounds the XML read from a feed
+const maxFeedSize = 10 << 20
+
+// Result is the answer to a conditional feed request. Items is empty when the feed
+// did not change since the validators sent; the validators to send next time are set
+// in either case.
+type Result struct {
+	NotModified  bool
+	Items        []Item
+	ETag         string
+	LastModified string
+}
+
+// Fetch downloads and parses a feed. The etag and lastModified of the previous answer
+// make the request conditional, so an unchanged feed is not downloaded again.
+func Fetch(ctx context.Context, client *http.Client, feedURL, etag, lastModified string) (Result, error) {
+	result := Result{ETag: etag, LastModified: lastModified}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("User-Agent", "HirePilot/1.0")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		return result, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("the feed answered with status %d", resp.StatusCode)
+	}
+
+	items, err := Parse(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return result, fmt.Errorf("could not read the feed: %w", err)
+	}
+	result.Items = items
+	result.ETag = resp.Header.Get("ETag")
+	result.LastModified = resp.Header.Get("Last-Modified")
+	return result, nil
+}
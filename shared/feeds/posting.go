@@ -0,0 +1,42 @@
+package feeds
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/models"
+	"github.com/hirepilot/shared/posting"
+)
+
+// Posting turns an item into a job posting. Job feeds name the company in the title
+// ("Backend Engineer at Acme", "Acme: Backend Engineer") or as the author; company,
+// when set, is used instead. Company is empty when none of these name one.
+func (it Item) Posting(company string) models.JobPosting {
+	title, titleCompany := splitItemTitle(extract.FragmentText(it.Title))
+	p := models.JobPosting{
+		Title:       title,
+		Company:     firstOf(company, titleCompany, it.Author),
+		Link:        it.Link,
+		Description: extract.FragmentText(it.Description),
+	}
+	// Titles and categories often carry the workplace, employment type or salary
+	p.JobDetails = posting.ParseDetails(strings.Join(append([]string{it.Title}, it.Categories...), " · "), time.Now())
+	if it.Published != nil {
+		p.PostedAt = it.Published
+	}
+	return p
+}
+
+// splitItemTitle separates the company from a title of the form "Title at Company" or
+// "Company: Title"
+func splitItemTitle(text string) (title, company string) {
+	text = strings.Join(strings.Fields(text), " ")
+	if i := strings.LastIndex(text, " at "); i > 0 {
+		return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+len(" at "):])
+	}
+	if company, title, ok := strings.Cut(text, ": "); ok && company != "" && title != "" {
+		return strings.TrimSpace(title), strings.TrimSpace(company)
+	}
+	return text, ""
+}
@@ -0,0 +1,239 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IMAP reads a mailbox on an IMAP server. The mailbox is opened read-only and bodies
+// are fetched with BODY.PEEK, so no flags change. Only the commands needed to search
+// and fetch messages are spoken.
+type IMAP struct {
+	Addr     string // host:port
+	Username string
+	Password string
+	Mailbox  string        // INBOX when empty
+	TLS      bool          // Implicit TLS, usually on port 993
+	Timeout  time.Duration // Bounds a whole Messages call, one minute when zero
+}
+
+func (m *IMAP) Messages(since time.Time, known func(id string) bool) ([]Message, error) {
+	c, err := m.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	if _, err := c.command("LOGIN %s %s", quote(m.Username), quote(m.Password)); err != nil {
+		return nil, err
+	}
+	mailbox := m.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if _, err := c.command("EXAMINE %s", quote(mailbox)); err != nil {
+		return nil, err
+	}
+
+	// SEARCH SINCE compares dates only, the exact time is checked on the headers
+	responses, err := c.command("UID SEARCH SINCE %s", since.Format("02-Jan-2006"))
+	if err != nil {
+		return nil, err
+	}
+	var uids []string
+	for _, resp := range responses {
+		if fields := strings.Fields(resp.text); len(fields) > 1 && strings.EqualFold(fields[0], "SEARCH") {
+			uids = append(uids, fields[1:]...)
+		}
+	}
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	// Headers first, so only the bodies of unknown messages are downloaded
+	headers, err := c.fetch(uids, "BODY.PEEK[HEADER]")
+	if err != nil {
+		return nil, err
+	}
+	var wantedUIDs []string
+	for _, uid := range uids {
+		header, ok := headers[uid]
+		if !ok {
+			continue
+		}
+		m, err := mail.ReadMessage(bytes.NewReader(header))
+		if err != nil {
+			continue
+		}
+		if date, err := m.Header.Date(); err == nil && date.Before(since) {
+			continue
+		}
+		if !known(ID(m.Header)) {
+			wantedUIDs = append(wantedUIDs, uid)
+		}
+	}
+	if len(wantedUIDs) == 0 {
+		return nil, nil
+	}
+
+	bodies, err := c.fetch(wantedUIDs, "BODY.PEEK[]")
+	if err != nil {
+		return nil, err
+	}
+	var messages []Message
+	for _, uid := range wantedUIDs {
+		if body, ok := bodies[uid]; ok {
+			if msg, err := Read(bytes.NewReader(body)); err == nil {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return messages, nil
+}
+
+// imapConn is a connection to an IMAP server that runs one command at a time
+type imapConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// imapResponse is an untagged response. Literals are cut out of the text, in order.
+type imapResponse struct {
+	text     string
+	literals [][]byte
+}
+
+var (
+	literalSuffix = regexp.MustCompile(`\{(\d+)\}$`)
+	fetchUID      = regexp.MustCompile(`(?i)\bUID (\d+)`)
+)
+
+func (m *IMAP) dial() (*imapConn, error) {
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if m.TLS {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", m.Addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", m.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	c := &imapConn{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := c.readResponse()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting.text, "* OK") && !strings.HasPrefix(greeting.text, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("IMAP server refused the connection: %s", greeting.text)
+	}
+	return c, nil
+}
+
+func (c *imapConn) close() {
+	c.command("LOGOUT")
+	c.conn.Close()
+}
+
+// command sends a command and returns its untagged responses, or the server's reason
+// if it does not answer OK
+func (c *imapConn) command(format string, args ...interface{}) ([]imapResponse, error) {
+	c.tag++
+	tag := "a" + strconv.Itoa(c.tag)
+	if _, err := fmt.Fprintf(c.conn, tag+" "+format+"\r\n", args...); err != nil {
+		return nil, err
+	}
+
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if status, ok := strings.CutPrefix(resp.text, tag+" "); ok {
+			if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+				command, _, _ := strings.Cut(format, " ")
+				return nil, fmt.Errorf("IMAP %s failed: %s", command, status)
+			}
+			return responses, nil
+		}
+		if text, ok := strings.CutPrefix(resp.text, "* "); ok {
+			resp.text = text
+			responses = append(responses, resp)
+		}
+	}
+}
+
+// fetch returns one literal item, such as BODY.PEEK[], of each message by UID
+func (c *imapConn) fetch(uids []string, item string) (map[string][]byte, error) {
+	responses, err := c.command("UID FETCH %s (UID %s)", strings.Join(uids, ","), item)
+	if err != nil {
+		return nil, err
+	}
+	items := map[string][]byte{}
+	for _, resp := range responses {
+		match := fetchUID.FindStringSubmatch(resp.text)
+		if match == nil || len(resp.literals) == 0 || !strings.Contains(strings.ToUpper(resp.text), " FETCH ") {
+			continue
+		}
+		items[match[1]] = resp.literals[0]
+	}
+	return items, nil
+}
+
+// readResponse reads one response line with the literals it announces
+func (c *imapConn) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var text strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return resp, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		match := literalSuffix.FindStringSubmatchIndex(line)
+		if match == nil {
+			text.WriteString(line)
+			resp.text = text.String()
+			return resp, nil
+		}
+
+		text.WriteString(line[:match[0]])
+		size, err := strconv.Atoi(line[match[2]:match[3]])
+		if err != nil || size > maxMessageSize {
+			return resp, errors.New("IMAP literal too large")
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return resp, err
+		}
+		resp.literals = append(resp.literals, literal)
+	}
+}
+
+// quote returns s as an IMAP quoted string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
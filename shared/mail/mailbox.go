@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Mailbox is a store of messages
+type Mailbox interface {
+	// Messages returns the messages dated since a time, leaving out those known
+	// reports true for by ID. Messages without a date are always returned.
+	Messages(since time.Time, known func(id string) bool) ([]Message, error)
+}
+
+// wanted reports whether a message passes the date and known filters of Messages
+func wanted(msg Message, since time.Time, known func(id string) bool) bool {
+	return (msg.Date == nil || !msg.Date.Before(since)) && !known(msg.ID)
+}
+
+// Maildir reads the new and cur directories of a Maildir. Messages are only read,
+// never moved or flagged.
+type Maildir struct {
+	Path string
+}
+
+func (m *Maildir) Messages(since time.Time, known func(id string) bool) ([]Message, error) {
+	var messages []Message
+	for _, dir := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(m.Path, dir))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			msg, err := readFile(filepath.Join(m.Path, dir, entry.Name()))
+			if err != nil {
+				continue // Not a message, or removed while reading
+			}
+			if wanted(msg, since, known) {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return messages, nil
+}
+
+func readFile(path string) (Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Message{}, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Mbox reads an mbox file. Lines quoted as ">From " are unquoted, as in the mboxrd
+// format; the file is never written.
+type Mbox struct {
+	Path string
+}
+
+func (m *Mbox) Messages(since time.Time, known func(id string) bool) ([]Message, error) {
+	f, err := os.Open(m.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var messages []Message
+	var current bytes.Buffer
+	inMessage := false
+	flush := func() {
+		if inMessage {
+			if msg, err := Read(bytes.NewReader(current.Bytes())); err == nil && wanted(msg, since, known) {
+				messages = append(messages, msg)
+			}
+		}
+		current.Reset()
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	previousBlank := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if previousBlank && strings.HasPrefix(line, "From ") {
+			flush()
+			inMessage = true
+			previousBlank = false
+			continue
+		}
+		if quoted := strings.TrimLeft(line, ">"); len(quoted) < len(line) && strings.HasPrefix(quoted, "From ") {
+			line = line[1:]
+		}
+		current.WriteString(line)
+		current.WriteString("\r\n")
+		previousBlank = line == ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return messages, nil
+}
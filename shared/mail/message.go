@@ -0,0 +1,120 @@
+// Package mail reads email messages from IMAP mailboxes, Maildir directories and mbox
+// files
+package mail
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// maxMessageSize bounds the bytes read from one message
+const maxMessageSize = 25 << 20
+
+// Message is an email with its text and HTML bodies decoded to UTF-8. Attachments are
+// left out.
+type Message struct {
+	ID       string // Message-ID without angle brackets, or a hash of the headers if it has none
+	From     string // Sender address, lower case
+	FromName string
+	Subject  string
+	Date     *time.Time
+	Text     string
+	HTML     string
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// Read parses a message in RFC 5322 format
+func Read(r io.Reader) (Message, error) {
+	m, err := mail.ReadMessage(io.LimitReader(r, maxMessageSize))
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{ID: ID(m.Header), Subject: decodeHeader(m.Header.Get("Subject"))}
+	if from, err := (&mail.AddressParser{WordDecoder: wordDecoder}).Parse(m.Header.Get("From")); err == nil {
+		msg.From, msg.FromName = strings.ToLower(from.Address), from.Name
+	}
+	if date, err := m.Header.Date(); err == nil {
+		msg.Date = &date
+	}
+	readPart(&msg, m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Header.Get("Content-Disposition"), m.Body)
+	return msg, nil
+}
+
+// ID returns the Message-ID of a message without angle brackets. Messages without one
+// are identified by a hash of their sender, date and subject.
+func ID(h mail.Header) string {
+	if id := strings.Trim(strings.TrimSpace(h.Get("Message-Id")), "<>"); id != "" {
+		return id
+	}
+	sum := sha1.Sum([]byte(h.Get("From") + "\n" + h.Get("Date") + "\n" + h.Get("Subject")))
+	return "sha1:" + hex.EncodeToString(sum[:])
+}
+
+// readPart keeps the first text and HTML bodies found in a part, descending into
+// multipart parts
+func readPart(msg *Message, contentType, transferEncoding, disposition string, body io.Reader) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if d, _, _ := mime.ParseMediaType(disposition); d == "attachment" {
+		return
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				return
+			}
+			readPart(msg, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part.Header.Get("Content-Disposition"), part)
+		}
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" ||
+		mediaType == "text/plain" && msg.Text != "" || mediaType == "text/html" && msg.HTML != "" {
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	if cs := params["charset"]; cs != "" {
+		if decoded, err := charset.NewReaderLabel(cs, body); err == nil {
+			body = decoded
+		}
+	}
+	content, err := io.ReadAll(body)
+	if err != nil && len(content) == 0 {
+		return
+	}
+
+	if mediaType == "text/html" {
+		msg.HTML = string(content)
+	} else {
+		msg.Text = strings.ReplaceAll(string(content), "\r\n", "\n")
+	}
+}
+
+// decodeHeader decodes the encoded words of a header such as =?UTF-8?Q?...?=
+func decodeHeader(value string) string {
+	if decoded, err := wordDecoder.DecodeHeader(value); err == nil {
+		return strings.TrimSpace(decoded)
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// JobActivityKind is the type of an entry in the timeline of a job
+type JobActivityKind string
+
+const (
+	JobActivityCreated            JobActivityKind = "created"
+	JobActivityStatusChanged      JobActivityKind = "status_changed"
+	JobActivityNote               JobActivityKind = "note"
+	JobActivityCVGenerated        JobActivityKind = "cv_generated"
+	JobActivityCoverGenerated     JobActivityKind = "cover_generated"
+	JobActivityScoreGenerated     JobActivityKind = "score_generated"
+	JobActivityPDFCreated         JobActivityKind = "pdf_created"
+	JobActivityInterviewScheduled JobActivityKind = "interview_scheduled"
+	JobActivityInterviewUpdated   JobActivityKind = "interview_updated"
+	JobActivityInterviewDeleted   JobActivityKind = "interview_deleted"
+	JobActivityEmailReceived      JobActivityKind = "email_received"
+)
+
+// JobActivity is one entry in the timeline of a job
+type JobActivity struct {
+	Id        int             `json:"id" db:"id"`
+	JobId     int             `json:"jobId" db:"job_id"`
+	Kind      JobActivityKind `json:"kind" db:"kind"`
+	Summary   string          `json:"summary" db:"summary"`
+	Detail    string          `json:"detail,omitempty" db:"detail"` // Markdown body for notes, text of emails
+	RefId     *int            `json:"refId,omitempty" db:"ref_id"`  // Note, interview, email or prompt version the entry refers to
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// JobNote is a free-form markdown note attached to a job
+type JobNote struct {
+	Id        int       `json:"id" db:"id"`
+	JobId     int       `json:"jobId" db:"job_id"`
+	ContactId *int      `json:"contactId,omitempty" db:"contact_id"` // Contact the note is about, e.g. a call summary
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
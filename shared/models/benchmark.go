@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BenchmarkSet is a fixed collection of jobs used to evaluate prompts.
+// Its JSON form doubles as the fixture format for export and import.
+type BenchmarkSet struct {
+	Id          int            `json:"id,omitempty" db:"id"`
+	Name        string         `json:"name" db:"name"`
+	Description string         `json:"description" db:"description"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	Jobs        []BenchmarkJob `json:"jobs"`
+}
+
+// BenchmarkJob is a single job of a benchmark set
+type BenchmarkJob struct {
+	Id          int      `json:"id,omitempty" db:"id"`
+	SetId       int      `json:"setId,omitempty" db:"set_id"`
+	Title       string   `json:"title" db:"title"`
+	Company     string   `json:"company" db:"company"`
+	Description string   `json:"description" db:"description"`
+	Keywords    []string `json:"keywords,omitempty" db:"keywords"` // Expected keywords; derived from the description when empty
+}
+
+// EvaluationStatus is the lifecycle state of a prompt evaluation
+type EvaluationStatus string
+
+const (
+	EvaluationStatusPending   EvaluationStatus = "pending"
+	EvaluationStatusRunning   EvaluationStatus = "running"
+	EvaluationStatusCompleted EvaluationStatus = "completed"
+	EvaluationStatusFailed    EvaluationStatus = "failed"
+)
+
+// PromptEvaluation is a run of a candidate prompt against a benchmark set
+type PromptEvaluation struct {
+	Id             int              `json:"id" db:"id"`
+	PromptId       int              `json:"promptId" db:"prompt_id"`
+	Kind           string           `json:"kind" db:"kind"`
+	BenchmarkSetId int              `json:"benchmarkSetId" db:"benchmark_set_id"`
+	Status         EvaluationStatus `json:"status" db:"status"`
+	Report         json.RawMessage  `json:"report,omitempty" db:"report"`
+	Error          string           `json:"error,omitempty" db:"error"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	CompletedAt    *time.Time       `json:"completed_at" db:"completed_at"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// BoardProvider is the applicant tracking system publishing a job board
+type BoardProvider string
+
+const (
+	BoardGreenhouse BoardProvider = "greenhouse"
+	BoardLever      BoardProvider = "lever"
+	BoardAshby      BoardProvider = "ashby"
+	BoardWorkable   BoardProvider = "workable"
+)
+
+// BoardProviders lists the supported providers
+var BoardProviders = []BoardProvider{BoardGreenhouse, BoardLever, BoardAshby, BoardWorkable}
+
+// Valid reports whether p is a supported provider
+func (p BoardProvider) Valid() bool {
+	for _, provider := range BoardProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// JobBoard is a company job board on a public ATS API that the board poller pulls.
+// Postings are kept when their title contains one of the keywords and their location
+// one of the locations; empty lists keep everything.
+type JobBoard struct {
+	Id           int           `json:"id" db:"id"`
+	Provider     BoardProvider `json:"provider" db:"provider"`
+	Board        string        `json:"board" db:"board"`     // Board token, company slug or subdomain at the provider
+	Company      string        `json:"company" db:"company"` // Company name for postings that do not carry one
+	Keywords     []string      `json:"keywords" db:"keywords"`
+	Locations    []string      `json:"locations" db:"locations"` // "remote" also keeps remote postings
+	Enabled      bool          `json:"enabled" db:"enabled"`
+	LastPolledAt *time.Time    `json:"lastPolledAt" db:"last_polled_at"`
+	LastError    string        `json:"lastError" db:"last_error"`
+	LastCreated  int           `json:"lastCreated" db:"last_created"` // Postings published by the last poll
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+}
+
+// Matches reports whether a posting passes the keyword and location filters of the board
+func (b JobBoard) Matches(p JobPosting) bool {
+	return matchesAny(p.Title, b.Keywords) &&
+		(matchesAny(p.Location, b.Locations) ||
+			p.WorkplaceType == WorkplaceRemote && matchesAny(string(WorkplaceRemote), b.Locations))
+}
+
+// matchesAny reports whether text contains one of the terms, ignoring case; any text
+// matches an empty list
+func matchesAny(text string, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if strings.Contains(text, strings.ToLower(term)) {
+			return true
+		}
+	}
+	return false
+}
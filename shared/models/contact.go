@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// ContactRole is the part a contact plays in applications
+type ContactRole string
+
+const (
+	ContactRoleRecruiter     ContactRole = "recruiter"
+	ContactRoleHiringManager ContactRole = "hiring_manager"
+	ContactRoleReferrer      ContactRole = "referrer"
+	ContactRoleInterviewer   ContactRole = "interviewer"
+	ContactRoleOther         ContactRole = "other"
+)
+
+// ContactRoles lists the supported contact roles
+var ContactRoles = []ContactRole{
+	ContactRoleRecruiter, ContactRoleHiringManager, ContactRoleReferrer, ContactRoleInterviewer, ContactRoleOther,
+}
+
+// Valid reports whether r is a supported contact role
+func (r ContactRole) Valid() bool {
+	for _, role := range ContactRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Contact is a person met while applying, such as a recruiter or hiring manager.
+// Contacts belong to a company by name and can be linked to its jobs.
+type Contact struct {
+	Id              int         `json:"id" db:"id"`
+	Name            string      `json:"name" db:"name"`
+	Role            ContactRole `json:"role" db:"role"`
+	Company         string      `json:"company" db:"company"` // Matches the company of jobs
+	Email           string      `json:"email" db:"email"`
+	Phone           string      `json:"phone" db:"phone"`
+	LinkedinURL     string      `json:"linkedinUrl" db:"linkedin_url"`
+	Notes           string      `json:"notes" db:"notes"`
+	LastContactedAt *time.Time  `json:"lastContactedAt" db:"last_contacted_at"` // Set by notes about the contact
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+}
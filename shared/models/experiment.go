@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// ExperimentStatus is the lifecycle state of a prompt experiment
+type ExperimentStatus string
+
+const (
+	ExperimentStatusActive  ExperimentStatus = "active"
+	ExperimentStatusStopped ExperimentStatus = "stopped"
+)
+
+// Experiment is an A/B test of two or more prompts of the same kind on live jobs
+type Experiment struct {
+	Id        int                 `json:"id" db:"id"`
+	Name      string              `json:"name" db:"name"`
+	Kind      PromptKind          `json:"kind" db:"kind"` // cv or cover
+	Status    ExperimentStatus    `json:"status" db:"status"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	StoppedAt *time.Time          `json:"stopped_at" db:"stopped_at"`
+	Variants  []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant is one prompt of an experiment with its share of the traffic
+type ExperimentVariant struct {
+	Id           int `json:"id" db:"id"`
+	ExperimentId int `json:"experimentId" db:"experiment_id"`
+	PromptId     int `json:"promptId" db:"prompt_id"`
+	Weight       int `json:"weight" db:"weight"`
+}
+
+// ExperimentAssignment records which variant generated the content of a job
+type ExperimentAssignment struct {
+	Id           int       `json:"id" db:"id"`
+	ExperimentId int       `json:"experimentId" db:"experiment_id"`
+	VariantId    int       `json:"variantId" db:"variant_id"`
+	JobId        int       `json:"jobId" db:"job_id"`
+	InputTokens  int       `json:"inputTokens" db:"input_tokens"`
+	OutputTokens int       `json:"outputTokens" db:"output_tokens"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// VariantReport aggregates the outcomes of the jobs assigned to a variant
+type VariantReport struct {
+	VariantId     int      `json:"variantId"`
+	PromptId      int      `json:"promptId"`
+	PromptName    string   `json:"promptName"`
+	Weight        int      `json:"weight"`
+	Jobs          int      `json:"jobs"`
+	ScoredJobs    int      `json:"scoredJobs"`
+	AvgScore      *float64 `json:"avgScore"`
+	Applied       int      `json:"applied"`
+	Interviewed   int      `json:"interviewed"`
+	InterviewRate float64  `json:"interviewRate"` // Interviewed jobs per applied job
+	InputTokens   int      `json:"inputTokens"`
+	OutputTokens  int      `json:"outputTokens"`
+	EstimatedCost float64  `json:"estimatedCost"`
+	CostPerJob    float64  `json:"costPerJob"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpiryPolicyKind is what a job expiry policy closes
+type ExpiryPolicyKind string
+
+const (
+	// ExpiryPolicyCloseUnapplied closes open jobs that were never applied to
+	ExpiryPolicyCloseUnapplied ExpiryPolicyKind = "close_unapplied"
+	// ExpiryPolicyGhostUnanswered marks applications without a response as ghosted
+	ExpiryPolicyGhostUnanswered ExpiryPolicyKind = "ghost_unanswered"
+)
+
+// Valid reports whether k is a supported policy kind
+func (k ExpiryPolicyKind) Valid() bool {
+	return k == ExpiryPolicyCloseUnapplied || k == ExpiryPolicyGhostUnanswered
+}
+
+// Transition returns the status a policy watches and the status it moves jobs to
+func (k ExpiryPolicyKind) Transition() (from, to JobStatus) {
+	if k == ExpiryPolicyGhostUnanswered {
+		return JobStatusApplied, JobStatusGhosted
+	}
+	return JobStatusOpen, JobStatusClosed
+}
+
+// ExpiryPolicy moves jobs that stayed in a status for AfterDays, there is one per kind
+type ExpiryPolicy struct {
+	Kind      ExpiryPolicyKind `json:"kind" db:"kind"`
+	AfterDays int              `json:"afterDays" db:"after_days"`
+	Enabled   bool             `json:"enabled" db:"enabled"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Note is the status history note of a change made by the policy
+func (p ExpiryPolicy) Note() string {
+	if p.Kind == ExpiryPolicyGhostUnanswered {
+		return fmt.Sprintf("Marked as ghosted automatically: no response %d days after applying", p.AfterDays)
+	}
+	return fmt.Sprintf("Closed automatically: open for %d days without applying", p.AfterDays)
+}
+
+// ExpiryCandidate is a job a policy would move to another status
+type ExpiryCandidate struct {
+	JobId      int              `json:"jobId"`
+	JobTitle   string           `json:"jobTitle"`
+	JobCompany string           `json:"jobCompany"`
+	Policy     ExpiryPolicyKind `json:"policy"`
+	From       JobStatus        `json:"from"`
+	To         JobStatus        `json:"to"`
+	Since      time.Time        `json:"since"` // When the job entered From, UTC
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// JobFeed is an RSS or Atom feed of job openings that the feed watcher polls. ETag and
+// LastModified come from the last answer and make the next request conditional.
+type JobFeed struct {
+	Id               int        `json:"id" db:"id"`
+	URL              string     `json:"url" db:"url"`
+	Name             string     `json:"name" db:"name"`
+	Company          string     `json:"company" db:"company"`                    // Company of every item, for feeds of a single employer
+	FetchDescription bool       `json:"fetchDescription" db:"fetch_description"` // Read the full posting from the item link
+	Enabled          bool       `json:"enabled" db:"enabled"`
+	ETag             string     `json:"-" db:"etag"`
+	LastModified     string     `json:"-" db:"last_modified"`
+	LastPolledAt     *time.Time `json:"lastPolledAt" db:"last_polled_at"`
+	LastError        string     `json:"lastError" db:"last_error"`
+	LastCreated      int        `json:"lastCreated" db:"last_created"` // Items published by the last poll
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// InterviewRound is the type of an interview round
+type InterviewRound string
+
+const (
+	InterviewRoundPhoneScreen InterviewRound = "phone_screen"
+	InterviewRoundTechnical   InterviewRound = "technical"
+	InterviewRoundBehavioral  InterviewRound = "behavioral"
+	InterviewRoundTakeHome    InterviewRound = "take_home"
+	InterviewRoundOnsite      InterviewRound = "onsite"
+	InterviewRoundFinal       InterviewRound = "final"
+	InterviewRoundOther       InterviewRound = "other"
+)
+
+// InterviewRounds lists the supported round types
+var InterviewRounds = []InterviewRound{
+	InterviewRoundPhoneScreen, InterviewRoundTechnical, InterviewRoundBehavioral,
+	InterviewRoundTakeHome, InterviewRoundOnsite, InterviewRoundFinal, InterviewRoundOther,
+}
+
+// Valid reports whether r is a supported round type
+func (r InterviewRound) Valid() bool {
+	for _, round := range InterviewRounds {
+		if r == round {
+			return true
+		}
+	}
+	return false
+}
+
+// InterviewOutcome is the result of an interview round
+type InterviewOutcome string
+
+const (
+	InterviewOutcomePending   InterviewOutcome = "pending"
+	InterviewOutcomePassed    InterviewOutcome = "passed"
+	InterviewOutcomeFailed    InterviewOutcome = "failed"
+	InterviewOutcomeCancelled InterviewOutcome = "cancelled"
+)
+
+// InterviewOutcomes lists the supported outcomes
+var InterviewOutcomes = []InterviewOutcome{InterviewOutcomePending, InterviewOutcomePassed, InterviewOutcomeFailed, InterviewOutcomeCancelled}
+
+// Valid reports whether o is a supported outcome
+func (o InterviewOutcome) Valid() bool {
+	for _, outcome := range InterviewOutcomes {
+		if o == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// Interview is one interview round of a job application
+type Interview struct {
+	Id              int              `json:"id" db:"id"`
+	JobId           int              `json:"jobId" db:"job_id"`
+	Round           InterviewRound   `json:"round" db:"round"`
+	ScheduledAt     time.Time        `json:"scheduledAt" db:"scheduled_at"` // UTC
+	DurationMinutes *int             `json:"durationMinutes" db:"duration_minutes"`
+	Interviewers    []string         `json:"interviewers" db:"interviewers"` // Stored as JSON
+	Location        string           `json:"location" db:"location"`         // Address or video call link
+	Outcome         InterviewOutcome `json:"outcome" db:"outcome"`
+	Feedback        string           `json:"feedback" db:"feedback"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// UpcomingInterview is an interview with the job it belongs to, for the dashboard
+type UpcomingInterview struct {
+	Interview
+	JobTitle   string `json:"jobTitle"`
+	JobCompany string `json:"jobCompany"`
+}
@@ -7,11 +7,66 @@ import (
 type JobStatus string
 
 const (
-	JobStatusOpen    JobStatus = "open"
-	JobStatusApplied JobStatus = "applied"
-	JobStatusClosed  JobStatus = "closed"
+	JobStatusOpen         JobStatus = "open"
+	JobStatusApplied      JobStatus = "applied"
+	JobStatusScreening    JobStatus = "screening"
+	JobStatusInterviewing JobStatus = "interviewing"
+	JobStatusOffer        JobStatus = "offer"
+	JobStatusAccepted     JobStatus = "accepted"
+	JobStatusRejected     JobStatus = "rejected"
+	JobStatusWithdrawn    JobStatus = "withdrawn"
+	JobStatusGhosted      JobStatus = "ghosted"
+	JobStatusClosed       JobStatus = "closed"
 )
 
+// jobStatusTransitions is the application pipeline: the statuses each status may move to.
+// Ghosted applications can come back to life; closed postings can be reopened.
+var jobStatusTransitions = map[JobStatus][]JobStatus{
+	JobStatusOpen:         {JobStatusApplied, JobStatusClosed},
+	JobStatusApplied:      {JobStatusScreening, JobStatusInterviewing, JobStatusRejected, JobStatusWithdrawn, JobStatusGhosted, JobStatusClosed},
+	JobStatusScreening:    {JobStatusInterviewing, JobStatusRejected, JobStatusWithdrawn, JobStatusGhosted},
+	JobStatusInterviewing: {JobStatusOffer, JobStatusRejected, JobStatusWithdrawn, JobStatusGhosted},
+	JobStatusOffer:        {JobStatusAccepted, JobStatusRejected, JobStatusWithdrawn},
+	JobStatusGhosted:      {JobStatusScreening, JobStatusInterviewing, JobStatusRejected, JobStatusWithdrawn, JobStatusClosed},
+	JobStatusClosed:       {JobStatusOpen},
+}
+
+// JobStatuses lists the supported statuses in pipeline order
+var JobStatuses = []JobStatus{
+	JobStatusOpen, JobStatusApplied, JobStatusScreening, JobStatusInterviewing, JobStatusOffer,
+	JobStatusAccepted, JobStatusRejected, JobStatusWithdrawn, JobStatusGhosted, JobStatusClosed,
+}
+
+// Valid reports whether s is a supported job status
+func (s JobStatus) Valid() bool {
+	for _, status := range JobStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a job may move from status s to next
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	for _, allowed := range jobStatusTransitions[s] {
+		if next == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// JobStatusChange is one entry of a job's status history
+type JobStatusChange struct {
+	Id        int       `json:"id" db:"id"`
+	JobId     int       `json:"jobId" db:"job_id"`
+	From      JobStatus `json:"from" db:"from_status"` // Empty for the initial status
+	To        JobStatus `json:"to" db:"to_status"`
+	Note      string    `json:"note" db:"note"`
+	ChangedAt time.Time `json:"changedAt" db:"changed_at"`
+}
+
 // Job represents the job structure shared across all services
 type Job struct {
 	Id          int        `json:"id" db:"id"`
@@ -21,21 +76,91 @@ type Job struct {
 	Status      JobStatus  `json:"status" db:"status"`
 	CvGenerated bool       `json:"cvGenerated" db:"cvGenerated"`
 	Cv          string     `json:"cv" db:"cv"`
-	Description string     `json:"description" db:"description"`
+	Description string     `json:"description" db:"description"` // Normalised markdown
 	Score       *float64   `json:"score" db:"score"`
 	AppliedAt   *time.Time `json:"applied_at" db:"applied_at"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	CoverLetter string     `json:"cover_letter" db:"cover_letter"`
+	// Description as received from the source, before normalisation
+	RawDescription string `json:"rawDescription" db:"raw_description"`
+	JobDetails
+	Tags   []string   `json:"tags"`   // Tag names, sorted
+	Skills []JobSkill `json:"skills"` // Requirements read from the description
+
+	// Prompt versions used for the generated artefacts, for traceability
+	CvPromptVersionId    *int `json:"cvPromptVersionId" db:"cv_prompt_version_id"`
+	CoverPromptVersionId *int `json:"coverPromptVersionId" db:"cover_prompt_version_id"`
+	ScorePromptVersionId *int `json:"scorePromptVersionId" db:"score_prompt_version_id"`
+
+	// Posting the job was created from, unique per source and external ID.
+	// Source is linkedin, other, or manual for jobs added without a link.
+	Source     string `json:"source" db:"source"`
+	ExternalId string `json:"externalId" db:"external_id"`
+}
+
+// JobSummary is the list projection of a job, without the large text columns
+type JobSummary struct {
+	Id             int        `json:"id"`
+	Title          string     `json:"title"`
+	Company        string     `json:"company"`
+	Link           string     `json:"link"`
+	Status         JobStatus  `json:"status"`
+	CvGenerated    bool       `json:"cvGenerated"`
+	HasCoverLetter bool       `json:"hasCoverLetter"`
+	Score          *float64   `json:"score"`
+	AppliedAt      *time.Time `json:"applied_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	JobDetails
+	Source string   `json:"source"`
+	Tags   []string `json:"tags"`
+}
+
+// PromptKind identifies what a prompt is used to generate
+type PromptKind string
+
+const (
+	PromptKindCV    PromptKind = "cv"
+	PromptKindCover PromptKind = "cover"
+	PromptKindScore PromptKind = "score"
+)
+
+// PromptKinds lists the supported prompt kinds; new kinds only need to be added here
+var PromptKinds = []PromptKind{PromptKindCV, PromptKindCover, PromptKindScore}
+
+// Valid reports whether k is a supported prompt kind
+func (k PromptKind) Valid() bool {
+	for _, kind := range PromptKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
 }
 
 // Prompt represents the prompt structure shared across all services
 type Prompt struct {
-	Id                      int    `json:"id" db:"id"`
-	Name                    string `json:"name" db:"name"`
-	Prompt                  string `json:"prompt" db:"prompt"`
-	CvGenerationDefault     bool   `json:"cvGenerationDefault" db:"cvGenerationDefault"`
-	ScoreGenerationDefault  bool   `json:"scoreGenerationDefault" db:"scoreGenerationDefault"`
-	CoverGenerationDefault  bool   `json:"coverGenerationDefault" db:"coverGenerationDefault"`
+	Id        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Prompt    string     `json:"prompt" db:"prompt"`
+	Kind      PromptKind `json:"kind" db:"kind"`
+	IsDefault bool       `json:"isDefault" db:"is_default"` // At most one default prompt per kind
+	Version   int        `json:"version" db:"version"`
+	Archived  bool       `json:"archived" db:"archived"` // Hidden from listings, kept for history
+	Tag       string     `json:"tag" db:"tag"`           // Replaces the default prompt of its kind for jobs with this tag
+	// Legacy per-kind default flags, derived from Kind and IsDefault for older clients
+	CvGenerationDefault    bool `json:"cvGenerationDefault" db:"cvGenerationDefault"`
+	ScoreGenerationDefault bool `json:"scoreGenerationDefault" db:"scoreGenerationDefault"`
+	CoverGenerationDefault bool `json:"coverGenerationDefault" db:"coverGenerationDefault"`
+}
+
+// PromptVersion is an immutable snapshot of a prompt, written on every create/update
+type PromptVersion struct {
+	Id        int       `json:"id" db:"id"`
+	PromptId  int       `json:"promptId" db:"prompt_id"`
+	Version   int       `json:"version" db:"version"`
+	Name      string    `json:"name" db:"name"`
+	Prompt    string    `json:"prompt" db:"prompt"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
 // Feature represents the feature structure shared across all services
@@ -43,4 +168,4 @@ type Feature struct {
 	Id    int    `json:"id" db:"id"`
 	Name  string `json:"name" db:"name"`
 	Value bool   `json:"value" db:"value"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// MailKind is what the mail ingester found a message to be
+type MailKind string
+
+const (
+	MailKindJobAlert        MailKind = "job_alert" // A job site alert listing postings
+	MailKindRejection       MailKind = "rejection"
+	MailKindInterview       MailKind = "interview_invite"
+	MailKindOffer           MailKind = "offer"
+	MailKindAcknowledgement MailKind = "acknowledgement" // Automatic confirmation of an application
+	MailKindOther           MailKind = "other"
+)
+
+// MailKinds lists the kinds an employer's message about an application can have
+var MailKinds = []MailKind{MailKindRejection, MailKindInterview, MailKindOffer, MailKindAcknowledgement, MailKindOther}
+
+// Valid reports whether k is one of MailKinds
+func (k MailKind) Valid() bool {
+	for _, kind := range MailKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange returns the status a message of this kind moves an application to,
+// or nothing for kinds that do not change it
+func (k MailKind) StatusChange() JobStatus {
+	switch k {
+	case MailKindRejection:
+		return JobStatusRejected
+	case MailKindInterview:
+		return JobStatusInterviewing
+	case MailKindOffer:
+		return JobStatusOffer
+	}
+	return ""
+}
+
+// MailMessage records a message the mail ingester has processed, so it is read once
+type MailMessage struct {
+	Id         int        `json:"id" db:"id"`
+	MessageId  string     `json:"messageId" db:"message_id"`
+	Sender     string     `json:"sender" db:"sender"`
+	Subject    string     `json:"subject" db:"subject"`
+	Kind       MailKind   `json:"kind" db:"kind"`
+	Template   string     `json:"template" db:"template"` // Alert template that read the postings
+	Postings   int        `json:"postings" db:"postings"`
+	JobId      *int       `json:"jobId" db:"job_id"` // Job a message about an application was matched to
+	ReceivedAt *time.Time `json:"receivedAt" db:"received_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// StatusProposalState is where a proposed status change stands
+type StatusProposalState string
+
+const (
+	StatusProposalPending   StatusProposalState = "pending" // Waiting for confirmation
+	StatusProposalAccepted  StatusProposalState = "accepted"
+	StatusProposalDismissed StatusProposalState = "dismissed"
+)
+
+// Valid reports whether s is a supported proposal state
+func (s StatusProposalState) Valid() bool {
+	return s == StatusProposalPending || s == StatusProposalAccepted || s == StatusProposalDismissed
+}
+
+// StatusProposal is a status change suggested by a message about an application.
+// Automatic proposals were accepted by the mail ingester without confirmation.
+type StatusProposal struct {
+	Id            int                 `json:"id" db:"id"`
+	JobId         int                 `json:"jobId" db:"job_id"`
+	JobTitle      string              `json:"jobTitle" db:"-"`
+	JobCompany    string              `json:"jobCompany" db:"-"`
+	MailMessageId int                 `json:"mailMessageId" db:"mail_message_id"`
+	Sender        string              `json:"sender" db:"-"`
+	Subject       string              `json:"subject" db:"-"`
+	Kind          MailKind            `json:"kind" db:"kind"`
+	From          JobStatus           `json:"from" db:"from_status"`
+	To            JobStatus           `json:"to" db:"to_status"`
+	Confidence    float64             `json:"confidence" db:"confidence"`
+	Reason        string              `json:"reason" db:"reason"`
+	State         StatusProposalState `json:"state" db:"state"`
+	Automatic     bool                `json:"automatic" db:"automatic"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+	DecidedAt     *time.Time          `json:"decidedAt" db:"decided_at"`
+}
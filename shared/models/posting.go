@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkplaceType tells where the work is done
+type WorkplaceType string
+
+const (
+	WorkplaceRemote WorkplaceType = "remote"
+	WorkplaceHybrid WorkplaceType = "hybrid"
+	WorkplaceOnSite WorkplaceType = "on_site"
+)
+
+// WorkplaceTypes lists the supported workplace types
+var WorkplaceTypes = []WorkplaceType{WorkplaceRemote, WorkplaceHybrid, WorkplaceOnSite}
+
+// Valid reports whether t is empty or a supported workplace type
+func (t WorkplaceType) Valid() bool {
+	if t == "" {
+		return true
+	}
+	for _, workplaceType := range WorkplaceTypes {
+		if t == workplaceType {
+			return true
+		}
+	}
+	return false
+}
+
+// EmploymentType is the contract type of a job
+type EmploymentType string
+
+const (
+	EmploymentFullTime   EmploymentType = "full_time"
+	EmploymentPartTime   EmploymentType = "part_time"
+	EmploymentContract   EmploymentType = "contract"
+	EmploymentTemporary  EmploymentType = "temporary"
+	EmploymentInternship EmploymentType = "internship"
+)
+
+// EmploymentTypes lists the supported employment types
+var EmploymentTypes = []EmploymentType{EmploymentFullTime, EmploymentPartTime, EmploymentContract, EmploymentTemporary, EmploymentInternship}
+
+// Valid reports whether t is empty or a supported employment type
+func (t EmploymentType) Valid() bool {
+	if t == "" {
+		return true
+	}
+	for _, employmentType := range EmploymentTypes {
+		if t == employmentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Seniority is the experience level a job asks for, following LinkedIn's levels
+type Seniority string
+
+const (
+	SeniorityInternship Seniority = "internship"
+	SeniorityEntry      Seniority = "entry"
+	SeniorityAssociate  Seniority = "associate"
+	SeniorityMidSenior  Seniority = "mid_senior"
+	SeniorityDirector   Seniority = "director"
+	SeniorityExecutive  Seniority = "executive"
+)
+
+// Seniorities lists the supported seniority levels, from junior to senior
+var Seniorities = []Seniority{SeniorityInternship, SeniorityEntry, SeniorityAssociate, SeniorityMidSenior, SeniorityDirector, SeniorityExecutive}
+
+// Valid reports whether s is empty or a supported seniority level
+func (s Seniority) Valid() bool {
+	if s == "" {
+		return true
+	}
+	for _, seniority := range Seniorities {
+		if s == seniority {
+			return true
+		}
+	}
+	return false
+}
+
+// JobDetails are the structured attributes of a job posting; all of them are optional
+type JobDetails struct {
+	Location       string         `json:"location" db:"location"`
+	WorkplaceType  WorkplaceType  `json:"workplaceType" db:"workplace_type"`
+	EmploymentType EmploymentType `json:"employmentType" db:"employment_type"`
+	Seniority      Seniority      `json:"seniority" db:"seniority"`
+	SalaryMin      *float64       `json:"salaryMin" db:"salary_min"`
+	SalaryMax      *float64       `json:"salaryMax" db:"salary_max"`
+	SalaryCurrency string         `json:"salaryCurrency" db:"salary_currency"` // ISO 4217 code
+	PostedAt       *time.Time     `json:"postedAt" db:"posted_at"`
+}
+
+// JobPosting is the data a job is created from, as carried by jobs.create_request
+type JobPosting struct {
+	Title       string `json:"title"`
+	Company     string `json:"company"`
+	Link        string `json:"link"`
+	Description string `json:"description"`
+	JobDetails
+
+	// Optional; derived from the link when empty
+	Source     string `json:"source"`
+	ExternalId string `json:"externalId"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// ReminderRuleKind is what a reminder rule watches
+type ReminderRuleKind string
+
+const (
+	// ReminderRuleStatusStale fires when a job stays in Status for DelayHours
+	ReminderRuleStatusStale ReminderRuleKind = "status_stale"
+	// ReminderRuleInterviewUpcoming fires DelayHours before a pending interview
+	ReminderRuleInterviewUpcoming ReminderRuleKind = "interview_upcoming"
+)
+
+// Valid reports whether k is a supported rule kind
+func (k ReminderRuleKind) Valid() bool {
+	return k == ReminderRuleStatusStale || k == ReminderRuleInterviewUpcoming
+}
+
+// ReminderRule is a configurable rule the scheduler creates reminders from
+type ReminderRule struct {
+	Id         int              `json:"id" db:"id"`
+	Name       string           `json:"name" db:"name"`
+	Kind       ReminderRuleKind `json:"kind" db:"kind"`
+	Status     JobStatus        `json:"status,omitempty" db:"status"` // Watched status of status_stale rules
+	DelayHours int              `json:"delayHours" db:"delay_hours"`
+	Enabled    bool             `json:"enabled" db:"enabled"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// ReminderStatus is the state of a reminder
+type ReminderStatus string
+
+const (
+	ReminderStatusPending   ReminderStatus = "pending"   // Created, due event not published yet
+	ReminderStatusDue       ReminderStatus = "due"       // Due event published, waiting for the user
+	ReminderStatusSnoozed   ReminderStatus = "snoozed"   // Due again at SnoozedUntil
+	ReminderStatusDismissed ReminderStatus = "dismissed" // Handled by the user
+	ReminderStatusExpired   ReminderStatus = "expired"   // No longer applies, e.g. the status changed
+)
+
+// Valid reports whether s is a supported reminder status
+func (s ReminderStatus) Valid() bool {
+	switch s {
+	case ReminderStatusPending, ReminderStatusDue, ReminderStatusSnoozed, ReminderStatusDismissed, ReminderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// Reminder is a reminder about a job created by a rule
+type Reminder struct {
+	Id           int              `json:"id" db:"id"`
+	RuleId       int              `json:"ruleId" db:"rule_id"`
+	Kind         ReminderRuleKind `json:"kind" db:"kind"`
+	JobId        int              `json:"jobId" db:"job_id"`
+	RefId        int              `json:"refId" db:"ref_id"` // Status history entry or interview the reminder is about
+	Message      string           `json:"message" db:"message"`
+	DueAt        time.Time        `json:"dueAt" db:"due_at"` // UTC
+	Status       ReminderStatus   `json:"status" db:"status"`
+	SnoozedUntil *time.Time       `json:"snoozedUntil" db:"snoozed_until"`
+	NotifiedAt   *time.Time       `json:"notifiedAt" db:"notified_at"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	JobTitle     string           `json:"jobTitle"`
+	JobCompany   string           `json:"jobCompany"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// SkillKind is what a requirement of a job asks for
+type SkillKind string
+
+const (
+	SkillKindSkill         SkillKind = "skill"    // Technology, tool or practice
+	SkillKindLanguage      SkillKind = "language" // Spoken language
+	SkillKindCertification SkillKind = "certification"
+	SkillKindEducation     SkillKind = "education"
+	SkillKindExperience    SkillKind = "experience" // Years of experience not tied to one skill
+)
+
+// SkillKinds lists the supported requirement kinds
+var SkillKinds = []SkillKind{SkillKindSkill, SkillKindLanguage, SkillKindCertification, SkillKindEducation, SkillKindExperience}
+
+// Valid reports whether k is a supported requirement kind
+func (k SkillKind) Valid() bool {
+	for _, kind := range SkillKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources of a job requirement
+const (
+	SkillSourceTaxonomy = "taxonomy" // Found by the bundled skills taxonomy
+	SkillSourceAI       = "ai"       // Found by the AI model only
+)
+
+// JobSkill is one requirement read from the description of a job
+type JobSkill struct {
+	Id       int       `json:"id" db:"id"`
+	JobId    int       `json:"jobId" db:"job_id"`
+	Kind     SkillKind `json:"kind" db:"kind"`
+	Name     string    `json:"name" db:"name"`         // Canonical name, e.g. "Kubernetes" for "k8s"
+	Category string    `json:"category" db:"category"` // Taxonomy category of skills, e.g. "cloud"
+	Years    *int      `json:"years" db:"years"`       // Minimum years of experience asked for
+	Required bool      `json:"required" db:"required"` // Must-have rather than nice-to-have
+	Detail   string    `json:"detail" db:"detail"`     // Level of a language, field of a degree
+	Source   string    `json:"source" db:"source"`
+}
+
+// SkillDemand is how many jobs ask for a requirement
+type SkillDemand struct {
+	Kind         SkillKind `json:"kind"`
+	Name         string    `json:"name"`
+	Category     string    `json:"category"`
+	Jobs         int       `json:"jobs"`
+	RequiredJobs int       `json:"requiredJobs"` // Jobs for which it is a must-have
+}
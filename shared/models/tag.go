@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxTagLength is the longest tag name in runes
+const MaxTagLength = 64
+
+// Tag is a user-defined label of jobs, e.g. dream-company or needs-referral
+type Tag struct {
+	Id       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"name"`
+	JobCount int    `json:"jobCount"`
+}
+
+// NormalizeTag turns user input into a tag name: lower case, with runs of spaces
+// replaced by a dash. It reports false for empty or too long names and for names with
+// characters other than letters, digits, dashes, underscores and dots.
+func NormalizeTag(name string) (string, bool) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	if name == "" || len([]rune(name)) > MaxTagLength {
+		return "", false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' {
+			return "", false
+		}
+	}
+	return name, true
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// APIToken is a personal token for clients outside the web app, such as a browser
+// extension. Only a hash of the secret is stored; Prefix tells tokens apart.
+type APIToken struct {
+	Id         int        `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"` // First characters of the secret
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt" db:"last_used_at"`
+}
@@ -85,7 +85,7 @@ func Close() {
 func createJobsStream() {
 	stream, err := js.CreateOrUpdateStream(context.Background(), jetstream.StreamConfig{
 		Name:      "JOBS",
-		Subjects:  []string{"jobs.*", "cv.*", "cover.*", "prompts.*", "websocket.*"},
+		Subjects:  []string{"jobs.*", "cv.*", "cover.*", "prompts.*", "interviews.*", "reminders.*", "websocket.*"},
 		Retention: jetstream.LimitsPolicy,
 		MaxAge:    24 * time.Hour, // Keep messages for 24 hours
 	})
@@ -103,7 +103,7 @@ func createJobsStream() {
 }
 
 // PublishJobCreationRequest publishes a job creation request (before DB insertion)
-func PublishJobCreationRequest(title, company, link, description string) error {
+func PublishJobCreationRequest(posting models.JobPosting) error {
 	js := GetJetStream()
 	if js == nil {
 		return fmt.Errorf("JetStream not initialized")
@@ -112,12 +112,7 @@ func PublishJobCreationRequest(title, company, link, description string) error {
 	// Create message payload for job creation request
 	message := map[string]interface{}{
 		"type": "job_creation_request",
-		"data": map[string]interface{}{
-			"title":       title,
-			"company":     company,
-			"link":        link,
-			"description": description,
-		},
+		"data": posting,
 	}
 
 	// Convert to JSON
@@ -132,7 +127,7 @@ func PublishJobCreationRequest(title, company, link, description string) error {
 		return err
 	}
 
-	log.Printf("Published job creation request for: %s at %s", title, company)
+	log.Printf("Published job creation request for: %s at %s", posting.Title, posting.Company)
 	return nil
 }
 
@@ -165,6 +160,42 @@ func PublishJobMessage(job models.Job) error {
 	return nil
 }
 
+// JobAlreadyKnownMessage reports a job creation request for a posting that is already stored
+type JobAlreadyKnownMessage struct {
+	JobID              int    `json:"job_id"`
+	Link               string `json:"link"`
+	DescriptionUpdated bool   `json:"description_updated"`
+}
+
+// PublishJobAlreadyKnownMessage publishes that a job creation request was deduplicated
+func PublishJobAlreadyKnownMessage(known JobAlreadyKnownMessage) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	// Create message payload
+	message := map[string]interface{}{
+		"type": "job_already_known",
+		"data": known,
+	}
+
+	// Convert to JSON
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// Publish to JetStream
+	_, err = js.Publish(context.Background(), "jobs.already_known", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published already known message for job ID: %d", known.JobID)
+	return nil
+}
+
 // PublishCVMessage publishes a CV generation completion message
 func PublishCVMessage(cvData CVData) error {
 	js := GetJetStream()
@@ -342,12 +373,13 @@ func PublishCoverGenerationRequest(jobID string, promptID *int) error {
 
 // JobStatusUpdateRequest represents a job status update request
 type JobStatusUpdateRequest struct {
-	JobID  int    `json:"job_id"`
-	Status string `json:"status"`
+	JobID  int              `json:"job_id"`
+	Status models.JobStatus `json:"status"`
+	Note   string           `json:"note,omitempty"`
 }
 
 // PublishJobStatusUpdateRequest publishes a job status update request message
-func PublishJobStatusUpdateRequest(jobID int, status string) error {
+func PublishJobStatusUpdateRequest(jobID int, status models.JobStatus, note string) error {
 	js := GetJetStream()
 	if js == nil {
 		return fmt.Errorf("JetStream not initialized")
@@ -359,6 +391,7 @@ func PublishJobStatusUpdateRequest(jobID int, status string) error {
 		"data": JobStatusUpdateRequest{
 			JobID:  jobID,
 			Status: status,
+			Note:   note,
 		},
 	}
 
@@ -378,6 +411,101 @@ func PublishJobStatusUpdateRequest(jobID int, status string) error {
 	return nil
 }
 
+// PublishJobStatusChangedMessage publishes a status change of a job after it was stored
+func PublishJobStatusChangedMessage(change models.JobStatusChange) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	// Create message payload
+	message := map[string]interface{}{
+		"type": "job_status_changed",
+		"data": change,
+	}
+
+	// Convert to JSON
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// Publish to JetStream
+	_, err = js.Publish(context.Background(), "jobs.status_changed", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published status change of job ID: %d from %s to %s", change.JobId, change.From, change.To)
+	return nil
+}
+
+// Interview lifecycle events, published on interviews.<event>
+const (
+	InterviewCreated = "created"
+	InterviewUpdated = "updated"
+	InterviewDeleted = "deleted"
+)
+
+// PublishInterviewEvent publishes a change of an interview so reminders or
+// interview preparation can hook in
+func PublishInterviewEvent(event string, interview models.Interview) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	// Create message payload
+	message := map[string]interface{}{
+		"type": "interview_" + event,
+		"data": interview,
+	}
+
+	// Convert to JSON
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// Publish to JetStream
+	_, err = js.Publish(context.Background(), "interviews."+event, payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published interview %s message for interview ID: %d", event, interview.Id)
+	return nil
+}
+
+// PublishReminderDueMessage publishes a reminder that became due, or due again after a snooze
+func PublishReminderDueMessage(reminder models.Reminder) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	// Create message payload
+	message := map[string]interface{}{
+		"type": "reminder_due",
+		"data": reminder,
+	}
+
+	// Convert to JSON
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// Publish to JetStream
+	_, err = js.Publish(context.Background(), "reminders.due", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published reminder due message for reminder ID: %d", reminder.Id)
+	return nil
+}
+
 // SubscribeToJobsCreated subscribes to job creation messages
 func SubscribeToJobsCreated(handler func(*nats.Msg)) (jetstream.ConsumeContext, error) {
 	js := GetJetStream()
@@ -901,25 +1029,25 @@ func SubscribeToCoverGeneratedGeneric(handler MessageHandler) (jetstream.Consume
 
 // PromptCreationRequest represents a prompt creation request
 type PromptCreationRequest struct {
-	Name                    string `json:"name"`
-	Prompt                  string `json:"prompt"`
-	CvGenerationDefault     bool   `json:"cvGenerationDefault"`
-	ScoreGenerationDefault  bool   `json:"scoreGenerationDefault"`
-	CoverGenerationDefault  bool   `json:"coverGenerationDefault"`
+	Name      string            `json:"name"`
+	Prompt    string            `json:"prompt"`
+	Kind      models.PromptKind `json:"kind"`
+	IsDefault bool              `json:"isDefault"`
+	Tag       string            `json:"tag,omitempty"`
 }
 
 // PromptUpdateRequest represents a prompt update request
 type PromptUpdateRequest struct {
-	ID                      int    `json:"id"`
-	Name                    string `json:"name"`
-	Prompt                  string `json:"prompt"`
-	CvGenerationDefault     bool   `json:"cvGenerationDefault"`
-	ScoreGenerationDefault  bool   `json:"scoreGenerationDefault"`
-	CoverGenerationDefault  bool   `json:"coverGenerationDefault"`
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Prompt    string            `json:"prompt"`
+	Kind      models.PromptKind `json:"kind"`
+	IsDefault bool              `json:"isDefault"`
+	Tag       string            `json:"tag,omitempty"`
 }
 
 // PublishPromptCreationRequest publishes a prompt creation request
-func PublishPromptCreationRequest(name, prompt string, cvDefault, scoreDefault, coverDefault bool) error {
+func PublishPromptCreationRequest(name, prompt string, kind models.PromptKind, isDefault bool, tag string) error {
 	js := GetJetStream()
 	if js == nil {
 		return fmt.Errorf("JetStream not initialized")
@@ -928,11 +1056,11 @@ func PublishPromptCreationRequest(name, prompt string, cvDefault, scoreDefault,
 	message := map[string]interface{}{
 		"type": "prompt_creation_request",
 		"data": PromptCreationRequest{
-			Name:                    name,
-			Prompt:                  prompt,
-			CvGenerationDefault:     cvDefault,
-			ScoreGenerationDefault:  scoreDefault,
-			CoverGenerationDefault:  coverDefault,
+			Name:      name,
+			Prompt:    prompt,
+			Kind:      kind,
+			IsDefault: isDefault,
+			Tag:       tag,
 		},
 	}
 
@@ -951,7 +1079,7 @@ func PublishPromptCreationRequest(name, prompt string, cvDefault, scoreDefault,
 }
 
 // PublishPromptUpdateRequest publishes a prompt update request
-func PublishPromptUpdateRequest(id int, name, prompt string, cvDefault, scoreDefault, coverDefault bool) error {
+func PublishPromptUpdateRequest(id int, name, prompt string, kind models.PromptKind, isDefault bool, tag string) error {
 	js := GetJetStream()
 	if js == nil {
 		return fmt.Errorf("JetStream not initialized")
@@ -960,12 +1088,12 @@ func PublishPromptUpdateRequest(id int, name, prompt string, cvDefault, scoreDef
 	message := map[string]interface{}{
 		"type": "prompt_update_request",
 		"data": PromptUpdateRequest{
-			ID:                      id,
-			Name:                    name,
-			Prompt:                  prompt,
-			CvGenerationDefault:     cvDefault,
-			ScoreGenerationDefault:  scoreDefault,
-			CoverGenerationDefault:  coverDefault,
+			ID:        id,
+			Name:      name,
+			Prompt:    prompt,
+			Kind:      kind,
+			IsDefault: isDefault,
+			Tag:       tag,
 		},
 	}
 
@@ -1036,3 +1164,308 @@ func SubscribeToPromptUpdateRequestsGeneric(handler MessageHandler) (jetstream.C
 		}
 	})
 }
+
+// PromptRollbackRequest represents a request to restore an earlier prompt version
+type PromptRollbackRequest struct {
+	ID      int `json:"id"`
+	Version int `json:"version"`
+}
+
+// PublishPromptRollbackRequest publishes a prompt rollback request
+func PublishPromptRollbackRequest(id, version int) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	message := map[string]interface{}{
+		"type": "prompt_rollback_request",
+		"data": PromptRollbackRequest{
+			ID:      id,
+			Version: version,
+		},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = js.Publish(context.Background(), "prompts.rollback_request", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published prompt rollback request for ID: %d to version: %d", id, version)
+	return nil
+}
+
+// SubscribeToPromptRollbackRequestsGeneric subscribes to prompt rollback request messages with generic handler
+func SubscribeToPromptRollbackRequestsGeneric(handler MessageHandler) (jetstream.ConsumeContext, error) {
+	js := GetJetStream()
+	if js == nil {
+		return nil, fmt.Errorf("JetStream not initialized")
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(context.Background(), "JOBS", jetstream.ConsumerConfig{
+		Name:           "prompt-rollback-consumer",
+		Durable:        "prompt-rollback-consumer",
+		FilterSubjects: []string{"prompts.rollback_request"},
+		AckWait:        5 * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(msg.Data()); err != nil {
+			log.Printf("Error handling prompt rollback message: %v", err)
+			msg.Nak()
+		} else {
+			msg.Ack()
+		}
+	})
+}
+
+// PromptSetDefaultRequest represents a request to make a prompt the default of its kind
+type PromptSetDefaultRequest struct {
+	ID int `json:"id"`
+}
+
+// PublishPromptSetDefaultRequest publishes a request to make a prompt the default of its kind
+func PublishPromptSetDefaultRequest(id int) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	message := map[string]interface{}{
+		"type": "prompt_set_default_request",
+		"data": PromptSetDefaultRequest{
+			ID: id,
+		},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = js.Publish(context.Background(), "prompts.set_default_request", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published prompt set default request for ID: %d", id)
+	return nil
+}
+
+// SubscribeToPromptSetDefaultRequestsGeneric subscribes to prompt set default request messages with generic handler
+func SubscribeToPromptSetDefaultRequestsGeneric(handler MessageHandler) (jetstream.ConsumeContext, error) {
+	js := GetJetStream()
+	if js == nil {
+		return nil, fmt.Errorf("JetStream not initialized")
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(context.Background(), "JOBS", jetstream.ConsumerConfig{
+		Name:           "prompt-set-default-consumer",
+		Durable:        "prompt-set-default-consumer",
+		FilterSubjects: []string{"prompts.set_default_request"},
+		AckWait:        5 * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(msg.Data()); err != nil {
+			log.Printf("Error handling prompt set default message: %v", err)
+			msg.Nak()
+		} else {
+			msg.Ack()
+		}
+	})
+}
+
+// PromptDeleteRequest represents a request to delete a prompt
+type PromptDeleteRequest struct {
+	ID int `json:"id"`
+}
+
+// PublishPromptDeleteRequest publishes a request to delete a prompt
+func PublishPromptDeleteRequest(id int) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	message := map[string]interface{}{
+		"type": "prompt_delete_request",
+		"data": PromptDeleteRequest{
+			ID: id,
+		},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = js.Publish(context.Background(), "prompts.delete_request", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published prompt delete request for ID: %d", id)
+	return nil
+}
+
+// SubscribeToPromptDeleteRequestsGeneric subscribes to prompt delete request messages with generic handler
+func SubscribeToPromptDeleteRequestsGeneric(handler MessageHandler) (jetstream.ConsumeContext, error) {
+	js := GetJetStream()
+	if js == nil {
+		return nil, fmt.Errorf("JetStream not initialized")
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(context.Background(), "JOBS", jetstream.ConsumerConfig{
+		Name:           "prompt-delete-consumer",
+		Durable:        "prompt-delete-consumer",
+		FilterSubjects: []string{"prompts.delete_request"},
+		AckWait:        5 * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(msg.Data()); err != nil {
+			log.Printf("Error handling prompt delete message: %v", err)
+			msg.Nak()
+		} else {
+			msg.Ack()
+		}
+	})
+}
+
+// PromptArchiveRequest represents a request to archive or restore a prompt
+type PromptArchiveRequest struct {
+	ID       int  `json:"id"`
+	Archived bool `json:"archived"`
+}
+
+// PublishPromptArchiveRequest publishes a request to archive or restore a prompt
+func PublishPromptArchiveRequest(id int, archived bool) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	message := map[string]interface{}{
+		"type": "prompt_archive_request",
+		"data": PromptArchiveRequest{
+			ID:       id,
+			Archived: archived,
+		},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = js.Publish(context.Background(), "prompts.archive_request", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published prompt archive request for ID: %d (archived: %t)", id, archived)
+	return nil
+}
+
+// SubscribeToPromptArchiveRequestsGeneric subscribes to prompt archive request messages with generic handler
+func SubscribeToPromptArchiveRequestsGeneric(handler MessageHandler) (jetstream.ConsumeContext, error) {
+	js := GetJetStream()
+	if js == nil {
+		return nil, fmt.Errorf("JetStream not initialized")
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(context.Background(), "JOBS", jetstream.ConsumerConfig{
+		Name:           "prompt-archive-consumer",
+		Durable:        "prompt-archive-consumer",
+		FilterSubjects: []string{"prompts.archive_request"},
+		AckWait:        5 * time.Minute,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(msg.Data()); err != nil {
+			log.Printf("Error handling prompt archive message: %v", err)
+			msg.Nak()
+		} else {
+			msg.Ack()
+		}
+	})
+}
+
+// PromptEvaluationRequest represents a request to run a stored prompt evaluation
+type PromptEvaluationRequest struct {
+	EvaluationID int `json:"evaluation_id"`
+}
+
+// PublishPromptEvaluationRequest publishes a prompt evaluation request
+func PublishPromptEvaluationRequest(evaluationID int) error {
+	js := GetJetStream()
+	if js == nil {
+		return fmt.Errorf("JetStream not initialized")
+	}
+
+	message := map[string]interface{}{
+		"type": "prompt_evaluation_request",
+		"data": PromptEvaluationRequest{
+			EvaluationID: evaluationID,
+		},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = js.Publish(context.Background(), "prompts.evaluate_request", payload)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Published prompt evaluation request for evaluation ID: %d", evaluationID)
+	return nil
+}
+
+// SubscribeToPromptEvaluationRequestsGeneric subscribes to prompt evaluation request messages with generic handler
+func SubscribeToPromptEvaluationRequestsGeneric(handler MessageHandler) (jetstream.ConsumeContext, error) {
+	js := GetJetStream()
+	if js == nil {
+		return nil, fmt.Errorf("JetStream not initialized")
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(context.Background(), "JOBS", jetstream.ConsumerConfig{
+		Name:           "prompt-evaluator",
+		Durable:        "prompt-evaluator",
+		FilterSubjects: []string{"prompts.evaluate_request"},
+		AckWait:        30 * time.Minute, // An evaluation makes several AI calls per benchmark job
+		MaxDeliver:     3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumer.Consume(func(msg jetstream.Msg) {
+		if err := handler(msg.Data()); err != nil {
+			log.Printf("Error handling prompt evaluation message: %v", err)
+			msg.Nak()
+		} else {
+			msg.Ack()
+		}
+	})
+}
@@ -0,0 +1,321 @@
+package normalize
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// lineElements start on a new line, paragraphElements after an empty line
+var (
+	lineElements = map[atom.Atom]bool{
+		atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true, atom.Header: true,
+		atom.Footer: true, atom.Tr: true, atom.Dt: true, atom.Dd: true,
+	}
+	paragraphElements = map[atom.Atom]bool{
+		atom.P: true, atom.Dl: true, atom.Table: true, atom.Blockquote: true, atom.Pre: true, atom.Hr: true,
+	}
+	headingLevels = map[atom.Atom]int{
+		atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+	}
+)
+
+// skippedElements hold no readable text
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Head: true,
+	atom.Svg: true, atom.Button: true, atom.Form: true, atom.Nav: true, atom.Img: true,
+}
+
+// Markdown converts HTML to markdown with headings, paragraphs, nested lists, bold and
+// italic text and links. A paragraph that is all bold, a common way to title the
+// sections of a posting, becomes a heading.
+func Markdown(fragment string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return fragment
+	}
+	var w markdownWriter
+	for _, n := range nodes {
+		w.node(n)
+	}
+	return w.b.String()
+}
+
+// markdownWriter lays out markdown, holding line breaks back until the next text so
+// that white space between elements does not add empty lines
+type markdownWriter struct {
+	b      strings.Builder
+	breaks int    // Pending line breaks: 1 for a new line, 2 for a new paragraph
+	prefix string // Written before the next text, e.g. a list item marker
+	space  bool   // Pending space between words
+	lists  []int  // Open lists, innermost last: the next number of ordered lists, -1 for bullets
+}
+
+func (w *markdownWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			w.node(c)
+		}
+		return
+	}
+	if skippedElements[n.DataAtom] {
+		return
+	}
+
+	if level, ok := headingLevels[n.DataAtom]; ok {
+		w.heading(level, nodeText(n))
+		return
+	}
+	switch n.DataAtom {
+	case atom.Br:
+		// A second break in a row ends the paragraph
+		w.lineBreak(min(w.breaks+1, 2))
+		return
+	case atom.Strong, atom.B:
+		w.inline(n, "**", "**")
+		return
+	case atom.Em, atom.I:
+		w.inline(n, "_", "_")
+		return
+	case atom.A:
+		href := strings.TrimSpace(attr(n, "href"))
+		if text := collapse(nodeText(n)); (strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://")) && text != href {
+			w.inline(n, "[", "]("+href+")")
+		} else {
+			w.inline(n, "", "")
+		}
+		return
+	case atom.Ul, atom.Ol:
+		w.list(n)
+		return
+	case atom.Li:
+		w.item(n)
+		return
+	}
+
+	breaks := 0
+	if paragraphElements[n.DataAtom] {
+		breaks = 2
+	} else if lineElements[n.DataAtom] {
+		breaks = 1
+	}
+	if breaks > 0 {
+		if title, ok := boldTitle(n); ok {
+			w.heading(3, title)
+			return
+		}
+	}
+	w.lineBreak(breaks)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+	w.lineBreak(breaks)
+	if n.DataAtom == atom.Td || n.DataAtom == atom.Th {
+		w.space = true
+	}
+}
+
+func (w *markdownWriter) heading(level int, text string) {
+	text = collapse(text)
+	if text == "" {
+		return
+	}
+	if len(w.lists) > 0 {
+		// Lists cannot hold headings, their titles stay bold
+		w.inlineText(text, "**", "**", false, false)
+		return
+	}
+	w.lineBreak(2)
+	w.prefix = strings.Repeat("#", level) + " "
+	w.text(text)
+	w.lineBreak(2)
+}
+
+// inline writes a bold, italic or link element as one piece of text between open and
+// close. Elements holding blocks are written as their content.
+func (w *markdownWriter) inline(n *html.Node, open, close string) {
+	if holdsBlocks(n) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			w.node(c)
+		}
+		return
+	}
+	raw := nodeText(n)
+	w.inlineText(collapse(raw), open, close, startsWithSpace(raw), endsWithSpace(raw))
+}
+
+func (w *markdownWriter) inlineText(text, open, close string, spaceBefore, spaceAfter bool) {
+	if text == "" {
+		w.space = w.space || spaceBefore || spaceAfter
+		return
+	}
+	if spaceBefore {
+		w.space = true
+	}
+	w.write(open + text + close)
+	w.space = spaceAfter
+}
+
+func (w *markdownWriter) list(n *html.Node) {
+	next := -1
+	if n.DataAtom == atom.Ol {
+		next = 1
+		if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+			next = start
+		}
+	}
+	breaks := 2
+	if len(w.lists) > 0 {
+		breaks = 1
+	}
+	w.lineBreak(breaks)
+	w.lists = append(w.lists, next)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+	w.lists = w.lists[:len(w.lists)-1]
+	w.lineBreak(breaks)
+}
+
+func (w *markdownWriter) item(n *html.Node) {
+	w.lineBreak(1)
+	marker := "- "
+	if depth := len(w.lists); depth > 0 {
+		if next := w.lists[depth-1]; next >= 0 {
+			marker = strconv.Itoa(next) + ". "
+			w.lists[depth-1]++
+		}
+		marker = strings.Repeat("  ", depth-1) + marker
+	} else {
+		// An item outside of any list is still written as a bullet
+		w.lists = append(w.lists, -1)
+		defer func() { w.lists = w.lists[:len(w.lists)-1] }()
+	}
+	w.prefix = marker
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+	w.lineBreak(1)
+}
+
+func (w *markdownWriter) lineBreak(n int) {
+	// Paragraphs within list items would break the list apart
+	if len(w.lists) > 0 && n > 1 {
+		n = 1
+	}
+	if n > w.breaks {
+		w.breaks = n
+	}
+}
+
+func (w *markdownWriter) text(s string) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		w.space = w.space || s != ""
+		return
+	}
+	if startsWithSpace(s) {
+		w.space = true
+	}
+	w.write(strings.Join(words, " "))
+	w.space = endsWithSpace(s)
+}
+
+// write writes text after the pending line breaks or space and the prefix
+func (w *markdownWriter) write(s string) {
+	if w.b.Len() > 0 {
+		if w.breaks > 0 {
+			w.b.WriteString(strings.Repeat("\n", w.breaks))
+		} else if w.space {
+			w.b.WriteString(" ")
+		}
+	}
+	w.b.WriteString(w.prefix)
+	w.b.WriteString(s)
+	w.breaks, w.prefix = 0, ""
+}
+
+// boldTitle returns the text of a block that holds nothing but one bold element of a
+// few words
+func boldTitle(n *html.Node) (string, bool) {
+	var bold *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) == "":
+		case c.Type == html.ElementNode && c.DataAtom == atom.Br:
+		case c.Type == html.ElementNode && (c.DataAtom == atom.Strong || c.DataAtom == atom.B) && bold == nil:
+			bold = c
+		default:
+			return "", false
+		}
+	}
+	if bold == nil || holdsBlocks(bold) {
+		return "", false
+	}
+	title := strings.TrimSuffix(collapse(nodeText(bold)), ":")
+	if title == "" || len(strings.Fields(title)) > 10 {
+		return "", false
+	}
+	return title, true
+}
+
+// holdsBlocks reports whether an element holds elements that start a line
+func holdsBlocks(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if lineElements[c.DataAtom] || paragraphElements[c.DataAtom] || headingLevels[c.DataAtom] > 0 ||
+			c.DataAtom == atom.Ul || c.DataAtom == atom.Ol || c.DataAtom == atom.Li || c.DataAtom == atom.Br || holdsBlocks(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the text below a node without any layout
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n\f") != s
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n\f") != s
+}
@@ -0,0 +1,82 @@
+package normalize
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// windows1252 maps the characters Windows-1252 puts at 0x80-0x9F to their byte.
+// Characters from U+0080 to U+00FF stand for the byte of the same value.
+var windows1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99,
+	'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+func windows1252Byte(r rune) (byte, bool) {
+	if r >= 0x80 && r <= 0xFF {
+		return byte(r), true
+	}
+	b, ok := windows1252[r]
+	return b, ok
+}
+
+// FixMojibake repairs UTF-8 text that was decoded as Windows-1252 or Latin-1 on the
+// way, such as "â€™" for "’" or "Ã©" for "é". Runs of such characters are encoded
+// back to bytes and every valid multi-byte UTF-8 sequence among them is decoded;
+// other characters, such as a lone "é", are kept. Text garbled twice is repaired
+// twice.
+func FixMojibake(text string) string {
+	for i := 0; i < 2; i++ {
+		fixed := fixMojibakeOnce(text)
+		if fixed == text {
+			break
+		}
+		text = fixed
+	}
+	// "”" garbled as "â€\x9d" often loses its last, invisible character
+	return strings.ReplaceAll(text, "â€", "”")
+}
+
+func fixMojibakeOnce(text string) string {
+	var b strings.Builder
+	var run []rune
+	flush := func() {
+		if len(run) > 1 {
+			writeDecoded(&b, run)
+		} else if len(run) == 1 {
+			b.WriteRune(run[0])
+		}
+		run = run[:0]
+	}
+	for _, r := range text {
+		if _, ok := windows1252Byte(r); ok {
+			run = append(run, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
+
+// writeDecoded writes a run of Windows-1252 characters, replacing the ones whose bytes
+// form a multi-byte UTF-8 sequence by the character they encode
+func writeDecoded(b *strings.Builder, run []rune) {
+	bytes := make([]byte, len(run))
+	for i, r := range run {
+		bytes[i], _ = windows1252Byte(r)
+	}
+	for i := 0; i < len(bytes); {
+		r, size := utf8.DecodeRune(bytes[i:])
+		if r != utf8.RuneError && size > 1 {
+			b.WriteRune(r)
+			i += size
+			continue
+		}
+		b.WriteRune(run[i])
+		i++
+	}
+}
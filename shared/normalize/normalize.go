@@ -0,0 +1,155 @@
+// Package normalize turns job descriptions from any source, scraped text, pasted
+// text or HTML, into clean markdown for prompts and documents
+package normalize
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Description normalises a job description: garbled characters are repaired, HTML is
+// converted to markdown, white space is collapsed, bullets become markdown list items,
+// and boilerplate such as "Show more" buttons, equal opportunity statements and
+// repeated paragraphs is removed.
+func Description(raw string) string {
+	text := FixMojibake(raw)
+	if escapedHTML.MatchString(text) {
+		text = html.UnescapeString(text)
+	}
+	if htmlTags.MatchString(text) {
+		text = Markdown(text)
+	} else {
+		text = html.UnescapeString(text)
+	}
+	return removeBoilerplate(cleanLines(text))
+}
+
+// htmlTags match the tags that make a description HTML rather than text that merely
+// mentions a tag; escapedHTML the same tags escaped once more
+var (
+	htmlTags    = regexp.MustCompile(`(?i)</?(p|div|br|ul|ol|li|h[1-6]|strong|b|em|i|span|a|table)(\s[^>]*)?/?>`)
+	escapedHTML = regexp.MustCompile(`(?i)&lt;/?(p|div|br|ul|ol|li|h[1-6]|strong|b|em|span)(\s.*?)?/?&gt;`)
+)
+
+// spaceReplacer removes invisible characters and turns odd spaces into plain spaces
+var spaceReplacer = strings.NewReplacer(
+	"\r\n", "\n", "\r", "\n", "\t", " ",
+	"\u00a0", " ", "\u2007", " ", "\u202f", " ", "\u2009", " ", "\u3000", " ",
+	"\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "", "\u00ad", "",
+)
+
+// bullet matches the list markers of pasted and scraped text, such as "•", "▪" or "*"
+var bullet = regexp.MustCompile(`^([•·▪◦●■□‣∙➢➤►✓✔]|[*–—-] )\s*`)
+
+// noiseLines are the buttons and labels of job sites that end up in scraped text
+var noiseLines = regexp.MustCompile(`(?i)^(show (more|less)|see (more|less)|read (more|less)|…\s*(more|less)|` +
+	`about the job|apply( now)?|easy apply|save( job)?|report this (job|listing))$`)
+
+// cleanLines collapses the white space of every line, turns bullets into markdown list
+// items, drops job site buttons and keeps at most one empty line between paragraphs
+func cleanLines(text string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(spaceReplacer.Replace(text), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if noiseLines.MatchString(trimmed) {
+			continue
+		}
+
+		indent := ""
+		if marker := bullet.FindString(trimmed); marker != "" {
+			trimmed = "- " + trimmed[len(marker):]
+			indent = listIndent(line)
+		} else if orderedItem.MatchString(trimmed) {
+			indent = listIndent(line)
+		}
+		trimmed = collapse(trimmed)
+		if trimmed == "-" {
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, indent+trimmed)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var orderedItem = regexp.MustCompile(`^\d{1,2}[.)] `)
+
+// listIndent keeps the nesting of list items, two spaces per level
+func listIndent(line string) string {
+	spaces := len(line) - len(strings.TrimLeft(line, " "))
+	return strings.Repeat("  ", spaces/2)
+}
+
+// equalOpportunity matches the legal statements many postings end with
+var equalOpportunity = regexp.MustCompile(`(?i)(equal (employment )?opportunit(y|ies)( employer)?|affirmative action|` +
+	`without regard to (their )?(race|colou?r|religion|sex|gender|age|national origin|disability|sexual orientation)|` +
+	`regardless of (their )?(race|colou?r|religion|sex|gender|age|national origin|disability|ethnicity|sexual orientation)|` +
+	`protected veteran|e-verify|eeo is the law|pay transparency non-?discrimination|` +
+	`reasonable accommodations? (during|in|for) (the|our) (application|hiring|recruiting))`)
+
+// minDuplicate is the length from which a repeated paragraph is boilerplate; short
+// ones, such as list items, may repeat on purpose
+const minDuplicate = 40
+
+// removeBoilerplate drops equal opportunity statements, repeated paragraphs such as a
+// second "About us", and the headings left without content
+func removeBoilerplate(text string) string {
+	seen := map[string]bool{}
+	var kept []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if !isHeading(paragraph) && equalOpportunity.MatchString(paragraph) {
+			continue
+		}
+		key := strings.ToLower(collapse(paragraph))
+		if utf8.RuneCountInString(key) >= minDuplicate {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		kept = append(kept, paragraph)
+	}
+
+	// A heading followed by a heading of the same or a higher level, or by nothing,
+	// lost its section
+	var result []string
+	for i, paragraph := range kept {
+		if level := headingLevel(paragraph); level > 0 {
+			if i == len(kept)-1 {
+				continue
+			}
+			if next := headingLevel(kept[i+1]); next > 0 && next <= level {
+				continue
+			}
+		}
+		result = append(result, paragraph)
+	}
+	return strings.Join(result, "\n\n")
+}
+
+func isHeading(paragraph string) bool {
+	return headingLevel(paragraph) > 0
+}
+
+// headingLevel returns the level of a markdown heading, 0 for other paragraphs
+func headingLevel(paragraph string) int {
+	if strings.Contains(paragraph, "\n") {
+		return 0
+	}
+	level := len(paragraph) - len(strings.TrimLeft(paragraph, "#"))
+	if level == 0 || level > 6 || !strings.HasPrefix(paragraph[level:], " ") {
+		return 0
+	}
+	return level
+}
@@ -0,0 +1,87 @@
+package normalize
+
+import "testing"
+
+func TestFixMojibake(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"apostrophe", "We’re hiring", "We’re hiring"},
+		{"accent", "CafÃ© in ZÃ¼rich", "Café in Zürich"},
+		{"dashes and quotes", "Go â€“ â€œcloudâ€\u009d native", "Go – “cloud” native"},
+		{"lost last byte", "â€œRemoteâ€ role", "“Remote” role"},
+		{"bullet", "â€¢ Kubernetes", "• Kubernetes"},
+		{"garbled twice", "CafÃƒÂ©", "Café"},
+		{"pound", "Â£60k", "£60k"},
+		{"plain accents", "Café in Zürich, naïve résumé", "Café in Zürich, naïve résumé"},
+		{"plain symbols", "© 2026 Acme™ – £60k, 50% “remote”", "© 2026 Acme™ – £60k, 50% “remote”"},
+		{"spanish", "¡Únete! Señor Ñandú", "¡Únete! Señor Ñandú"},
+		{"nordic", "Ærøskøbing Ålesund", "Ærøskøbing Ålesund"},
+		{"ascii", "Senior Go Engineer", "Senior Go Engineer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FixMojibake(tt.text); got != tt.want {
+				t.Errorf("FixMojibake(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"paragraphs", "<p>First  line</p>\n<p>Second<br>line</p>", "First line\n\nSecond\nline"},
+		{"heading", "<h2>About us</h2><p>We build tools.</p>", "## About us\n\nWe build tools."},
+		{"bold paragraph is a heading", "<p><strong>Requirements:</strong></p><ul><li>Go</li></ul>", "### Requirements\n\n- Go"},
+		{"inline", "<p>Use <b>Go</b> and <em>SQL</em></p>", "Use **Go** and _SQL_"},
+		{"link", `<p>See <a href="https://acme.example/jobs">our jobs</a></p>`, "See [our jobs](https://acme.example/jobs)"},
+		{"link text is the url", `<a href="https://acme.example">https://acme.example</a>`, "https://acme.example"},
+		{"nested lists", "<ul><li>Backend<ul><li>Go</li><li>MySQL</li></ul></li><li>Frontend</li></ul>", "- Backend\n  - Go\n  - MySQL\n- Frontend"},
+		{"ordered list", `<ol start="3"><li>Three</li><li>Four</li></ol>`, "3. Three\n4. Four"},
+		{"skipped elements", "<p>Text</p><script>track()</script><button>Apply</button>", "Text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.html); got != tt.want {
+				t.Errorf("Markdown(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescription(t *testing.T) {
+	about := "Acme builds the tools that thousands of teams rely on every day."
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"html", "<p>We’re <b>hiring</b></p><ul><li>Go</li></ul>", "We’re **hiring**\n\n- Go"},
+		{"escaped html", "&lt;p&gt;Build APIs&lt;/p&gt;", "Build APIs"},
+		{"text bullets", "Requirements\n• Go\n  ▪ gRPC\n* MySQL", "Requirements\n- Go\n  - gRPC\n- MySQL"},
+		{"spaces", "Go developer​\r\n\r\n\r\n\tRemote", "Go developer\n\nRemote"},
+		{"job site buttons", "About the job\nBuild APIs\nShow more\nEasy Apply", "Build APIs"},
+		{"equal opportunity", "Build APIs.\n\nAcme is an equal opportunity employer.", "Build APIs."},
+		{"repeated paragraph", about + "\n\nBuild APIs.\n\n" + about, about + "\n\nBuild APIs."},
+		{"short repeats stay", "- Go\n\n- Go", "- Go\n\n- Go"},
+		{"empty heading", "## About you\n\nYou know Go.\n\n## Equal opportunity\n\nWe are an equal opportunity employer.", "## About you\n\nYou know Go."},
+		{"text mentioning a tag", "Experience with <template> literals & JSX", "Experience with <template> literals & JSX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Description(tt.raw); got != tt.want {
+				t.Errorf("Description(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
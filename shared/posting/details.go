@@ -0,0 +1,155 @@
+package posting
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// ParseDetails extracts the structured attributes found in free text such as
+// LinkedIn's job insights ("$120K/yr - $150K/yr · Remote · Full-time · Mid-Senior level").
+// Attributes that are not mentioned are left empty.
+func ParseDetails(text string, now time.Time) models.JobDetails {
+	details := models.JobDetails{
+		WorkplaceType:  ParseWorkplaceType(text),
+		EmploymentType: ParseEmploymentType(text),
+		Seniority:      ParseSeniority(text),
+		PostedAt:       ParsePostedAgo(text, now),
+	}
+	details.SalaryMin, details.SalaryMax, details.SalaryCurrency = ParseSalary(text)
+	return details
+}
+
+var workplacePatterns = []struct {
+	pattern *regexp.Regexp
+	value   models.WorkplaceType
+}{
+	{regexp.MustCompile(`(?i)\bhybrid\b`), models.WorkplaceHybrid},
+	{regexp.MustCompile(`(?i)\bon[- ]?site\b|\bin[- ]office\b`), models.WorkplaceOnSite},
+	{regexp.MustCompile(`(?i)\bremote\b`), models.WorkplaceRemote},
+}
+
+// ParseWorkplaceType finds the workplace type mentioned in text
+func ParseWorkplaceType(text string) models.WorkplaceType {
+	for _, p := range workplacePatterns {
+		if p.pattern.MatchString(text) {
+			return p.value
+		}
+	}
+	return ""
+}
+
+var employmentPatterns = []struct {
+	pattern *regexp.Regexp
+	value   models.EmploymentType
+}{
+	{regexp.MustCompile(`(?i)\bfull[- ]?time\b`), models.EmploymentFullTime},
+	{regexp.MustCompile(`(?i)\bpart[- ]?time\b`), models.EmploymentPartTime},
+	{regexp.MustCompile(`(?i)\bcontract(or)?\b|\bfreelance\b`), models.EmploymentContract},
+	{regexp.MustCompile(`(?i)\btemporary\b`), models.EmploymentTemporary},
+	{regexp.MustCompile(`(?i)\binternship\b`), models.EmploymentInternship},
+}
+
+// ParseEmploymentType finds the employment type mentioned in text
+func ParseEmploymentType(text string) models.EmploymentType {
+	for _, p := range employmentPatterns {
+		if p.pattern.MatchString(text) {
+			return p.value
+		}
+	}
+	return ""
+}
+
+var seniorityPatterns = []struct {
+	pattern *regexp.Regexp
+	value   models.Seniority
+}{
+	{regexp.MustCompile(`(?i)\bmid[- ]senior\b`), models.SeniorityMidSenior},
+	{regexp.MustCompile(`(?i)\bentry[- ]level\b|\bjunior\b`), models.SeniorityEntry},
+	{regexp.MustCompile(`(?i)\bassociate\b`), models.SeniorityAssociate},
+	{regexp.MustCompile(`(?i)\bdirector\b`), models.SeniorityDirector},
+	{regexp.MustCompile(`(?i)\bexecutive\b`), models.SeniorityExecutive},
+	{regexp.MustCompile(`(?i)\binternship\b`), models.SeniorityInternship},
+}
+
+// ParseSeniority finds the seniority level mentioned in text
+func ParseSeniority(text string) models.Seniority {
+	for _, p := range seniorityPatterns {
+		if p.pattern.MatchString(text) {
+			return p.value
+		}
+	}
+	return ""
+}
+
+var salaryPattern = regexp.MustCompile(`(?i)([$€£]|\b(?:USD|EUR|GBP|CHF|CAD|AUD)\b)\s?(\d[\d,]*(?:\.\d+)?)\s*(k\b)?`)
+
+var currencySymbols = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+
+// ParseSalary finds a salary or salary range such as "$120K/yr - $150K/yr" in text.
+// A single amount is returned as both minimum and maximum.
+func ParseSalary(text string) (min, max *float64, currency string) {
+	matches := salaryPattern.FindAllStringSubmatch(text, 2)
+	var amounts []float64
+	for _, match := range matches {
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(match[2], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		if match[3] != "" {
+			amount *= 1000
+		}
+		if currency == "" {
+			currency = strings.ToUpper(match[1])
+			if code, ok := currencySymbols[match[1]]; ok {
+				currency = code
+			}
+		}
+		amounts = append(amounts, amount)
+	}
+
+	switch len(amounts) {
+	case 0:
+		return nil, nil, ""
+	case 1:
+		return &amounts[0], &amounts[0], currency
+	default:
+		if amounts[1] < amounts[0] {
+			amounts[0], amounts[1] = amounts[1], amounts[0]
+		}
+		return &amounts[0], &amounts[1], currency
+	}
+}
+
+var postedAgoPattern = regexp.MustCompile(`(?i)\b(\d+)\s+(minute|hour|day|week|month)s?\s+ago\b`)
+
+// ParsePostedAgo turns a relative date such as "Reposted 2 weeks ago" into a time
+func ParsePostedAgo(text string, now time.Time) *time.Time {
+	match := postedAgoPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil
+	}
+
+	var posted time.Time
+	switch strings.ToLower(match[2]) {
+	case "minute":
+		posted = now.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		posted = now.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		posted = now.AddDate(0, 0, -n)
+	case "week":
+		posted = now.AddDate(0, 0, -7*n)
+	case "month":
+		posted = now.AddDate(0, -n, 0)
+	}
+	posted = posted.Truncate(time.Minute)
+	return &posted
+}
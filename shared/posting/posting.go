@@ -0,0 +1,117 @@
+package posting
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Sources of job postings
+const (
+	SourceLinkedIn = "linkedin"
+	SourceOther    = "other"  // Any other site; the external ID is the canonical host and path
+	SourceManual   = "manual" // Added without a link, never deduplicated
+)
+
+// maxExternalIDLength matches the jobs.external_id column; longer IDs are hashed
+const maxExternalIDLength = 191
+
+// Key identifies a job posting independently of how its link was shared
+type Key struct {
+	Source     string // SourceLinkedIn or SourceOther
+	ExternalID string // Posting ID at the source
+	URL        string // Canonical link to the posting
+}
+
+// IsZero reports whether no key could be derived, e.g. for jobs added without a link
+func (k Key) IsZero() bool {
+	return k.Source == "" || k.ExternalID == ""
+}
+
+// trackingParams are query parameters that never identify a posting
+var trackingParams = map[string]bool{
+	"trk": true, "trkinfo": true, "trackingid": true, "refid": true, "ref": true,
+	"lipi": true, "midtoken": true, "midsig": true, "eid": true, "otptoken": true,
+	"ebp": true, "originalsubdomain": true, "src": true, "source": true,
+	"gclid": true, "fbclid": true, "msclkid": true, "mc_cid": true, "mc_eid": true,
+	"gh_src": true, "lever-source": true, "lever-origin": true,
+}
+
+var linkedInJobPath = regexp.MustCompile(`/jobs/view/(?:[^/]*-)?(\d+)`)
+
+// Canonicalize derives the posting key of a job link. Tracking parameters, fragments
+// and trailing slashes are dropped; LinkedIn links are reduced to their numeric job ID
+// whether they point at /jobs/view/<id> or carry it as currentJobId.
+func Canonicalize(link string) Key {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return Key{}
+	}
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return Key{}
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	if host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com") {
+		if id := linkedInJobID(u); id != "" {
+			return Key{
+				Source:     SourceLinkedIn,
+				ExternalID: id,
+				URL:        "https://www.linkedin.com/jobs/view/" + id + "/",
+			}
+		}
+	}
+
+	query := u.Query()
+	for name := range query {
+		lower := strings.ToLower(name)
+		if trackingParams[lower] || strings.HasPrefix(lower, "utm_") {
+			query.Del(name)
+		}
+	}
+
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	canonical := url.URL{Scheme: "https", Host: host, RawQuery: query.Encode()}
+	canonical.RawPath = path
+	canonical.Path, _ = url.PathUnescape(path)
+
+	externalID := host + path
+	if encoded := query.Encode(); encoded != "" {
+		externalID += "?" + encoded
+	}
+	if len(externalID) > maxExternalIDLength {
+		sum := sha1.Sum([]byte(externalID))
+		externalID = "sha1:" + hex.EncodeToString(sum[:])
+	}
+
+	return Key{Source: SourceOther, ExternalID: externalID, URL: canonical.String()}
+}
+
+func linkedInJobID(u *url.URL) string {
+	if match := linkedInJobPath.FindStringSubmatch(u.Path); match != nil {
+		return match[1]
+	}
+	if id := u.Query().Get("currentJobId"); isDigits(id) {
+		return id
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Generation assembles the text sent to the model to generate a CV or cover letter
+func Generation(promptText, title, company, description string) string {
+	return promptText + "\n\n" +
+		"Title: " + title + "\n" +
+		"Company: " + company + "\n" +
+		"Description: " + description + "\n"
+}
+
+// GenerationForJob assembles the generation text for a stored job, adding the
+// structured attributes, tags and requirements it has so prompts can refer to them,
+// e.g. to mention relocation, to write a freelancer-style CV for jobs tagged contract
+// or to put the must-have skills first
+func GenerationForJob(promptText string, job *models.Job) string {
+	text := Generation(promptText, job.Title, job.Company, job.Description) + Details(job.JobDetails)
+	if len(job.Tags) > 0 {
+		text += "Tags: " + strings.Join(job.Tags, ", ") + "\n"
+	}
+	return text + Requirements(job.Skills)
+}
+
+// Requirements renders the must-have and nice-to-have requirements of a job, one line
+// each, e.g. "Must-have requirements: Go (5+ years), English (C1)"
+func Requirements(skills []models.JobSkill) string {
+	var required, nice []string
+	for _, skill := range skills {
+		item := skill.Name
+		var notes []string
+		if skill.Detail != "" && skill.Kind != models.SkillKindExperience {
+			notes = append(notes, skill.Detail)
+		}
+		if skill.Years != nil {
+			notes = append(notes, strconv.Itoa(*skill.Years)+"+ years")
+		}
+		if len(notes) > 0 {
+			item += " (" + strings.Join(notes, ", ") + ")"
+		}
+		if skill.Required {
+			required = append(required, item)
+		} else {
+			nice = append(nice, item)
+		}
+	}
+
+	var b strings.Builder
+	if len(required) > 0 {
+		b.WriteString("Must-have requirements: " + strings.Join(required, ", ") + "\n")
+	}
+	if len(nice) > 0 {
+		b.WriteString("Nice-to-have requirements: " + strings.Join(nice, ", ") + "\n")
+	}
+	return b.String()
+}
+
+// CoverForJob assembles the cover letter generation text for a stored job. With a
+// known hiring manager the letter is addressed to them instead of a generic greeting.
+func CoverForJob(promptText string, job *models.Job, hiringManager *models.Contact) string {
+	text := GenerationForJob(promptText, job)
+	if hiringManager != nil && strings.TrimSpace(hiringManager.Name) != "" {
+		text += "Hiring manager: " + hiringManager.Name + "\n" +
+			"Address the letter to " + hiringManager.Name + " by name instead of using a generic greeting.\n"
+	}
+	return text
+}
+
+// Details renders the known structured attributes of a job, one per line
+func Details(details models.JobDetails) string {
+	var b strings.Builder
+	if details.Location != "" {
+		b.WriteString("Location: " + details.Location + "\n")
+	}
+	if details.WorkplaceType != "" {
+		b.WriteString("Workplace: " + humanize(string(details.WorkplaceType)) + "\n")
+	}
+	if details.EmploymentType != "" {
+		b.WriteString("Employment type: " + humanize(string(details.EmploymentType)) + "\n")
+	}
+	if details.Seniority != "" {
+		b.WriteString("Seniority: " + humanize(string(details.Seniority)) + "\n")
+	}
+	if salary := salaryRange(details); salary != "" {
+		b.WriteString("Salary: " + salary + "\n")
+	}
+	return b.String()
+}
+
+func salaryRange(details models.JobDetails) string {
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	var salary string
+	switch {
+	case details.SalaryMin != nil && details.SalaryMax != nil && *details.SalaryMin != *details.SalaryMax:
+		salary = format(*details.SalaryMin) + " - " + format(*details.SalaryMax)
+	case details.SalaryMin != nil:
+		salary = format(*details.SalaryMin)
+	case details.SalaryMax != nil:
+		salary = format(*details.SalaryMax)
+	default:
+		return ""
+	}
+	if details.SalaryCurrency != "" {
+		salary += " " + details.SalaryCurrency
+	}
+	return salary
+}
+
+// humanize turns enum values such as mid_senior into "mid senior"
+func humanize(value string) string {
+	return strings.ReplaceAll(value, "_", " ")
+}
+
+// Score assembles the text sent to the model to score a CV against a job description
+func Score(promptText, description, cv string) string {
+	return promptText + "\n\n" +
+		"Job Description: " + description + "\n" +
+		"CV: " + cv + "\n"
+}
+
+// ForJob renders a prompt of the given kind for a job, exactly as the generators would.
+// Cover prompts are rendered without a hiring manager; see CoverForJob.
+func ForJob(kind models.PromptKind, promptText string, job *models.Job) (string, error) {
+	switch kind {
+	case models.PromptKindCV, models.PromptKindCover:
+		return GenerationForJob(promptText, job), nil
+	case models.PromptKindScore:
+		return Score(promptText, job.Description, job.Cv), nil
+	default:
+		return "", fmt.Errorf("unsupported prompt kind: %s", kind)
+	}
+}
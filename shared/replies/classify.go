@@ -0,0 +1,200 @@
+// Package replies reads the messages employers send about applications: it tells
+// rejections, interview invitations, offers and acknowledgements apart and finds the
+// job a message is about.
+package replies
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	"github.com/hirepilot/shared/extract"
+	"github.com/hirepilot/shared/mail"
+	"github.com/hirepilot/shared/models"
+)
+
+// Confidence of a classification by a strong phrase, such as "regret to inform", and
+// by weak phrases only, such as "unfortunately"
+const (
+	StrongConfidence = 0.9
+	WeakConfidence   = 0.6
+)
+
+// Classification is the kind of a message with how sure the classifier is about it
+type Classification struct {
+	Kind       models.MailKind
+	Confidence float64 // Between 0 and 1
+	Reason     string
+}
+
+// rule recognises one kind of message
+type rule struct {
+	kind   models.MailKind
+	strong *regexp.Regexp
+	weak   *regexp.Regexp
+}
+
+// rules in order of precedence: a rejection that mentions an offer or an interview is
+// still a rejection
+var rules = []rule{
+	{
+		models.MailKindRejection,
+		regexp.MustCompile(`(?i)\b(regret to (inform|let you know)|decided not to (move|proceed|progress)|` +
+			`(not|won't) be (moving|proceeding|progressing) (forward )?with your (application|candidacy)|` +
+			`(will not|won't|cannot|can't) (be )?(moving|proceeding|progressing|move|proceed|progress) (forward )?with your|` +
+			`(decided to )?(move|moving|proceed|proceeding|go|going) forward with (other|another) (candidates?|applicants?)|` +
+			`pursue other candidates|position has (already |now )?been filled|not (been )?selected (to|for)|` +
+			`(unable|not able) to (extend|make) (you )?an offer|no longer (being )?considered)\b`),
+		regexp.MustCompile(`(?i)\b(unfortunately|other candidates|not (a|the) (right |good )?(fit|match)|at this time|wish you (all )?the best|best of luck)\b`),
+	},
+	{
+		models.MailKindOffer,
+		regexp.MustCompile(`(?i)\b((pleased|happy|delighted|excited|thrilled) to (extend|offer|make)|offer letter|` +
+			`(extend|make) (you )?an offer|job offer|offer of employment|formal offer)\b`),
+		regexp.MustCompile(`(?i)\b(compensation package|start date|sign(ing)? bonus|welcome (to|aboard))\b`),
+	},
+	{
+		models.MailKindInterview,
+		regexp.MustCompile(`(?i)\b((invite|invitation|invited) (you )?(to|for) (an? |the )?(first |next |technical |video |phone )?(interview|call|chat|conversation|phone screen)|` +
+			`(schedule|set up|arrange|book) (an? |the )?(first |next |technical |video |phone )?(interview|call|chat|phone screen|time to (talk|chat|speak))|` +
+			`interview (invitation|invite|request)|your availability (for|to)|calendly\.com|next steps? (in|of) (the|our) (hiring |interview )?process)\b`),
+		regexp.MustCompile(`(?i)\b(interview|availability|time slots?|would you be available|hiring manager)\b`),
+	},
+	{
+		models.MailKindAcknowledgement,
+		regexp.MustCompile(`(?i)\b(we (have )?received your application|your application (was|has been) (sent|submitted|received)|` +
+			`application (received|confirmation|submitted)|thank(s| you) for (your )?(applying|application)( to| for| with)?)\b`),
+		regexp.MustCompile(`(?i)\b(thank(s| you) for your interest|will (review|be reviewing) your|in touch (soon|shortly))\b`),
+	},
+}
+
+// ClassifyRules classifies a message by its subject and body text. The strongest rule
+// wins, ties go to the rule first in precedence; messages matching no rule are other.
+// Rejections and invitations often thank for the application, so a message is only an
+// acknowledgement when no other rule matches.
+func ClassifyRules(msg mail.Message) Classification {
+	text := msg.Subject + "\n" + Body(msg)
+	best := Classification{Kind: models.MailKindOther}
+	weakKinds := 0
+	for _, r := range rules {
+		if r.kind == models.MailKindAcknowledgement && best.Confidence > 0 {
+			break
+		}
+		if phrase := r.strong.FindString(text); phrase != "" {
+			if best.Confidence < StrongConfidence {
+				best = Classification{r.kind, StrongConfidence, fmt.Sprintf("mentions %q", strings.ToLower(phrase))}
+			}
+		} else if phrase := r.weak.FindString(text); phrase != "" {
+			weakKinds++
+			if best.Confidence < WeakConfidence {
+				best = Classification{r.kind, WeakConfidence, fmt.Sprintf("mentions %q", strings.ToLower(phrase))}
+			}
+		}
+	}
+	// Weak phrases of several kinds, such as "unfortunately" and "interview", say little
+	if best.Confidence == WeakConfidence && weakKinds > 1 {
+		best.Confidence = WeakConfidence - 0.1
+	}
+	return best
+}
+
+// Classifier classifies messages by rules, asking an AI model about the messages the
+// rules are not sure about when AI is set
+type Classifier struct {
+	AI sharedAI.AIClient
+}
+
+// Classify classifies a message. If the AI model fails, the classification by rules is
+// returned with the error.
+func (c Classifier) Classify(msg mail.Message) (Classification, error) {
+	byRules := ClassifyRules(msg)
+	if c.AI == nil || byRules.Confidence >= StrongConfidence {
+		return byRules, nil
+	}
+	byAI, err := c.classifyAI(msg)
+	if err != nil {
+		return byRules, err
+	}
+	if byAI.Confidence > byRules.Confidence {
+		return byAI, nil
+	}
+	return byRules, nil
+}
+
+// maxPromptBody bounds the runes of a message body sent to the AI model
+const maxPromptBody = 4000
+
+func (c Classifier) classifyAI(msg mail.Message) (Classification, error) {
+	kinds := make([]string, len(models.MailKinds))
+	for i, kind := range models.MailKinds {
+		kinds[i] = `"` + string(kind) + `"`
+	}
+	body := Body(msg)
+	if utf8.RuneCountInString(body) > maxPromptBody {
+		body = string([]rune(body)[:maxPromptBody])
+	}
+	prompt := "A job seeker received the email below about one of their job applications. Classify it as one of " +
+		strings.Join(kinds, ", ") + ". \"acknowledgement\" is an automatic confirmation that an application was received, " +
+		"\"other\" is anything that is not about the outcome of an application.\n" +
+		`Answer with JSON only: {"kind": "...", "confidence": <number between 0 and 1>, "reason": "<one short sentence>"}` +
+		"\n\nFrom: " + msg.FromName + " <" + msg.From + ">\nSubject: " + msg.Subject + "\n\n" + body
+
+	raw, err := c.AI.Generate(prompt)
+	if err != nil {
+		return Classification{}, err
+	}
+	return parseAIClassification(raw)
+}
+
+// parseAIClassification reads the JSON object of a model answer, which may be wrapped
+// in a code fence or text
+func parseAIClassification(raw string) (Classification, error) {
+	start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}")
+	if start < 0 || end < start {
+		return Classification{}, fmt.Errorf("no JSON object in classification: %q", raw)
+	}
+	var answer struct {
+		Kind       models.MailKind `json:"kind"`
+		Confidence float64         `json:"confidence"`
+		Reason     string          `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &answer); err != nil {
+		return Classification{}, fmt.Errorf("invalid classification %q: %w", raw, err)
+	}
+	if !answer.Kind.Valid() {
+		return Classification{}, fmt.Errorf("unknown message kind %q", answer.Kind)
+	}
+	confidence := answer.Confidence
+	if confidence < 0 {
+		confidence = 0
+	} else if confidence > 1 {
+		confidence = 1
+	}
+	return Classification{answer.Kind, confidence, "AI: " + strings.TrimSpace(answer.Reason)}, nil
+}
+
+// quoteStart matches the line before the quoted message of a reply
+var quoteStart = regexp.MustCompile(`(?i)^(on .+ wrote:|-+ ?original message ?-+|from: .+)$`)
+
+// Body returns the text of a message without the quoted messages it replies to
+func Body(msg mail.Message) string {
+	text := msg.Text
+	if strings.TrimSpace(text) == "" && msg.HTML != "" {
+		text = extract.FragmentText(msg.HTML)
+	}
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if quoteStart.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
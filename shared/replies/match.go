@@ -0,0 +1,145 @@
+package replies
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hirepilot/shared/mail"
+	"github.com/hirepilot/shared/models"
+)
+
+// Candidate is a job a message may be about
+type Candidate struct {
+	JobId         int
+	Title         string
+	Company       string
+	Status        models.JobStatus
+	ContactEmails []string // Lowercase emails of the contacts linked to the job
+}
+
+// Match is the job a message was found to be about. Certain matches were found by a
+// linked contact, by the sender domain or by both the company and the job title.
+type Match struct {
+	Candidate
+	Certain bool
+	Reason  string
+}
+
+// MatchJob finds the job a message is about. A message from a contact linked to one
+// of the jobs is about that job; otherwise it must name the company of the job, in
+// the sender domain or the text, and when several jobs are at that company also the
+// job title. Messages that could be about several jobs match none.
+func MatchJob(msg mail.Message, candidates []Candidate) (Match, bool) {
+	var byContact []Candidate
+	for _, c := range candidates {
+		for _, email := range c.ContactEmails {
+			if msg.From != "" && email == msg.From {
+				byContact = append(byContact, c)
+				break
+			}
+		}
+	}
+	text := " " + normalize(msg.Subject+"\n"+msg.FromName+"\n"+Body(msg)) + " "
+	if len(byContact) == 1 {
+		return Match{byContact[0], true, "the sender is a contact of the job"}, true
+	}
+	if len(byContact) > 1 {
+		if c, ok := onlyTitleIn(text, byContact); ok {
+			return Match{c, true, "the sender is a contact of the job and the message names its title"}, true
+		}
+		return Match{}, false
+	}
+
+	_, domain, _ := strings.Cut(msg.From, "@")
+	var atCompany []Candidate
+	byDomain := false
+	for _, c := range candidates {
+		company := normalizeCompany(c.Company)
+		if len(company) < 3 {
+			continue
+		}
+		if domainNames(domain, company) {
+			if !byDomain {
+				atCompany, byDomain = nil, true
+			}
+			atCompany = append(atCompany, c)
+		} else if !byDomain && strings.Contains(text, " "+company+" ") {
+			atCompany = append(atCompany, c)
+		}
+	}
+
+	if c, ok := onlyTitleIn(text, atCompany); ok {
+		return Match{c, true, "the message names the company and the job title"}, true
+	}
+	if len(atCompany) != 1 || !sameCompany(atCompany) {
+		return Match{}, false
+	}
+	if byDomain {
+		return Match{atCompany[0], true, "the sender domain is the company's"}, true
+	}
+	return Match{atCompany[0], false, "the message names the company"}, true
+}
+
+// onlyTitleIn returns the one candidate whose title the text names
+func onlyTitleIn(text string, candidates []Candidate) (Candidate, bool) {
+	var found []Candidate
+	for _, c := range candidates {
+		if title := normalizeTitle(c.Title); len(title) >= 4 && strings.Contains(text, " "+title+" ") {
+			found = append(found, c)
+		}
+	}
+	if len(found) != 1 {
+		return Candidate{}, false
+	}
+	return found[0], true
+}
+
+func sameCompany(candidates []Candidate) bool {
+	for _, c := range candidates[1:] {
+		if normalizeCompany(c.Company) != normalizeCompany(candidates[0].Company) {
+			return false
+		}
+	}
+	return true
+}
+
+// domainNames reports whether a label of a sender domain, such as mail.acme-corp.com,
+// is the company name without spaces or hyphens
+func domainNames(domain, company string) bool {
+	compact := strings.ReplaceAll(company, " ", "")
+	labels := strings.Split(domain, ".")
+	for _, label := range labels[:len(labels)-1] {
+		if strings.ReplaceAll(label, "-", "") == compact {
+			return true
+		}
+	}
+	return false
+}
+
+var nonWord = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// normalize lowercases text and turns everything but letters and digits into single spaces
+func normalize(s string) string {
+	return strings.TrimSpace(nonWord.ReplaceAllString(strings.ToLower(s), " "))
+}
+
+// legalForms are left out of company names, as messages rarely spell them out
+var legalForms = map[string]bool{
+	"inc": true, "llc": true, "ltd": true, "limited": true, "corp": true, "corporation": true, "co": true,
+	"company": true, "plc": true, "gmbh": true, "ag": true, "se": true, "sa": true, "bv": true, "nv": true,
+}
+
+func normalizeCompany(company string) string {
+	words := strings.Fields(normalize(company))
+	for len(words) > 1 && legalForms[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// titleNoise are the parts of a title a message leaves out, such as "(m/w/d)" or "- Remote"
+var titleNoise = regexp.MustCompile(`\([^)]*\)|\[[^]]*\]|\s[-–|]\s.*$`)
+
+func normalizeTitle(title string) string {
+	return normalize(titleNoise.ReplaceAllString(title, ""))
+}
@@ -0,0 +1,128 @@
+package skills
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	sharedAI "github.com/hirepilot/shared/ai"
+	"github.com/hirepilot/shared/models"
+)
+
+// Extractor reads requirements by the taxonomy, asking an AI model for the ones the
+// taxonomy does not know when AI is set
+type Extractor struct {
+	AI sharedAI.AIClient
+}
+
+// Extract reads the requirements of a job description. The requirements found by the
+// model are added to the taxonomy ones, filling in the years and details the taxonomy
+// missed. If the model fails, the taxonomy requirements are returned with the error.
+func (x Extractor) Extract(description string) ([]models.JobSkill, error) {
+	found := requirements{}
+	for _, skill := range Extract(description) {
+		found.add(skill)
+	}
+	if x.AI == nil || strings.TrimSpace(description) == "" {
+		return found.list, nil
+	}
+
+	byAI, err := x.extractAI(description)
+	if err != nil {
+		return found.list, err
+	}
+	for _, skill := range byAI {
+		found.add(skill)
+	}
+	return found.list, nil
+}
+
+// maxPromptDescription bounds the runes of a description sent to the AI model
+const maxPromptDescription = 8000
+
+func (x Extractor) extractAI(description string) ([]models.JobSkill, error) {
+	kinds := make([]string, len(models.SkillKinds))
+	for i, kind := range models.SkillKinds {
+		kinds[i] = `"` + string(kind) + `"`
+	}
+	if utf8.RuneCountInString(description) > maxPromptDescription {
+		description = string([]rune(description)[:maxPromptDescription])
+	}
+	prompt := "List the requirements of the job posting below. The kind of a requirement is one of " +
+		strings.Join(kinds, ", ") + `: "skill" for technologies, tools and practices, "language" for spoken languages, ` +
+		`"experience" for years of experience not tied to one skill. Use the usual short name of a skill, e.g. "Kubernetes" for "k8s". ` +
+		"A requirement is required unless the posting calls it nice to have, a plus or preferred. " +
+		"Detail is the level of a language or the field of a degree, empty otherwise.\n" +
+		`Answer with JSON only: [{"kind": "...", "name": "...", "years": <minimum years or null>, "required": <true or false>, "detail": "..."}]` +
+		"\n\n" + description
+
+	raw, err := x.AI.Generate(prompt)
+	if err != nil {
+		return nil, err
+	}
+	return parseAIRequirements(raw)
+}
+
+// parseAIRequirements reads the JSON array of a model answer, which may be wrapped in a
+// code fence or text. Names the taxonomy knows get their canonical spelling.
+func parseAIRequirements(raw string) ([]models.JobSkill, error) {
+	start, end := strings.Index(raw, "["), strings.LastIndex(raw, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in requirements: %q", raw)
+	}
+	var answer []struct {
+		Kind     models.SkillKind `json:"kind"`
+		Name     string           `json:"name"`
+		Years    *int             `json:"years"`
+		Required bool             `json:"required"`
+		Detail   string           `json:"detail"`
+	}
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &answer); err != nil {
+		return nil, fmt.Errorf("invalid requirements %q: %w", raw, err)
+	}
+
+	var skills []models.JobSkill
+	for _, item := range answer {
+		name := strings.TrimSpace(item.Name)
+		if !item.Kind.Valid() || name == "" || utf8.RuneCountInString(name) > 100 {
+			continue
+		}
+		skill := models.JobSkill{
+			Kind:     item.Kind,
+			Name:     name,
+			Required: item.Required,
+			Detail:   truncate(strings.TrimSpace(item.Detail)),
+			Source:   models.SkillSourceAI,
+		}
+		if item.Years != nil && *item.Years > 0 && *item.Years <= 30 {
+			skill.Years = item.Years
+		}
+		canonicalize(&skill)
+		skills = append(skills, skill)
+	}
+	return skills, nil
+}
+
+// canonicalize gives a requirement named by the model the name the taxonomy uses for it
+func canonicalize(skill *models.JobSkill) {
+	switch skill.Kind {
+	case models.SkillKindSkill, models.SkillKindCertification:
+		if kind, name, category, ok := Lookup(skill.Name); ok {
+			skill.Kind, skill.Name, skill.Category = kind, name, category
+		}
+	case models.SkillKindLanguage:
+		if name, ok := spokenLanguages[strings.ToLower(skill.Name)]; ok {
+			skill.Name = name
+		}
+	case models.SkillKindEducation:
+		for _, degree := range degrees {
+			if degree.pattern.MatchString(skill.Name) {
+				skill.Name = degree.name
+				break
+			}
+		}
+	case models.SkillKindExperience:
+		skill.Name = ExperienceName
+	}
+}
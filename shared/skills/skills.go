@@ -0,0 +1,402 @@
+// Package skills reads the requirements of a job from its description: skills and
+// technologies, years of experience, spoken languages, certifications and education,
+// each marked must-have or nice-to-have.
+package skills
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// ExperienceName is the name of the experience requirement not tied to one skill
+const ExperienceName = "Professional experience"
+
+// maxDetail bounds the runes of the detail of a requirement
+const maxDetail = 255
+
+var (
+	// niceHeading matches the headings of sections of nice-to-have requirements
+	niceHeading = regexp.MustCompile(`(?i)\b(nice[- ]to[- ]haves?|bonus|plus(es)?|preferred|desired|desirable|optional|good to have|would be (great|nice)|extra credit|ideally|even better|not required)\b`)
+	// niceLine matches lines that make their requirements nice-to-have
+	niceLine = regexp.MustCompile(`(?i)\b(nice[- ]to[- ]have|is a (big )?plus|are a (big )?plus|(a )?bonus|preferred|preferably|ideally|desirable|would be (great|nice|an advantage|beneficial)|is an advantage|advantageous|familiarity with|exposure to|not required)\b`)
+	// mustLine matches lines that make their requirements must-have
+	mustLine = regexp.MustCompile(`(?i)\b(must|required|mandatory|essential|need to have|you have)\b`)
+
+	// yearsPattern matches the minimum years of experience asked for, e.g. "5+ years"
+	// or "3-5 years"
+	yearsPattern = regexp.MustCompile(`(?i)\b(\d{1,2}|one|two|three|four|five|six|seven|eight|nine|ten)\s*\+?\s*(?:(?:-|–|to)\s*\d{1,2}\s*\+?\s*)?(?:\(\d{1,2}\)\s*)?(?:years?|yrs?)\b`)
+	// experienceWord matches sentences about professional experience
+	experienceWord = regexp.MustCompile(`(?i)\b(experience|experienced|track record|background)\b`)
+
+	// languageContext matches the words that make a language name a spoken language
+	languageContext = regexp.MustCompile(`(?i)\b(fluent|fluency|native|mother tongue|proficien\w*|business[- ]level|working (knowledge|proficiency)|professional (level|proficiency)|speak\w*|spoken|written|verbal|language|[ABC][12]|level)\b`)
+	// languageLevel matches the level a spoken language is asked at
+	languageLevel = regexp.MustCompile(`(?i)\b(native|mother tongue|fluent|fluency|business[- ]level|professional (level|proficiency)|working (knowledge|proficiency)|conversational|basic)\b`)
+	// cefrLevel matches the levels of the Common European Framework, e.g. C1
+	cefrLevel = regexp.MustCompile(`\b[ABC][12]\b`)
+	// languageName matches the words that may be a spoken language
+	languageName = regexp.MustCompile(`\b[A-Z][a-z]+\b`)
+
+	// clauseSeparator splits lines into the parts of a list
+	clauseSeparator = regexp.MustCompile(`[,;/]|\band\b`)
+	// sentenceEnd splits lines into sentences
+	sentenceEnd = regexp.MustCompile(`[.;!?](\s+|$)`)
+)
+
+// degrees recognise the education requirements, highest first
+var degrees = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"PhD", regexp.MustCompile(`(?i)\b(ph\.?\s?d\.?|doctorate|doctoral degree)`)},
+	{"MBA", regexp.MustCompile(`\bMBA\b`)},
+	{"Master's degree", regexp.MustCompile(`(?i)\b(master'?s'?\s+(degree|in|of)|masters?\s+degree|m\.?sc\.?\b|m\.s\.|ms degree)`)},
+	{"Bachelor's degree", regexp.MustCompile(`(?i)\b(bachelor'?s'?|b\.?sc\.?\b|b\.s\.|bs degree|undergraduate degree)`)},
+	{"Degree", regexp.MustCompile(`(?i)\b((university|college|academic) degree|degree in|degree (or|in a) (related|equivalent|similar))`)},
+}
+
+var (
+	// degreeField matches the field of study after a degree, e.g. "in Computer Science"
+	degreeField = regexp.MustCompile(`(?i)^[^.;:]{0,20}?\bin\s+(?:an?\s+|the\s+)?([a-z][a-z /&-]{2,60}?)(?:\s*,|\s+or\b|\s+and\b|\s*\.|\s*;|\s*\(|\s*$)`)
+	// equivalentExperience matches degree requirements that experience can replace
+	equivalentExperience = regexp.MustCompile(`(?i)\b(or equivalent|or similar|equivalent (practical |work |professional )?experience|or comparable)\b`)
+)
+
+// Extract reads the requirements of a job description by the bundled taxonomy, in the
+// order they are first mentioned. A requirement mentioned several times is must-have
+// if any mention is, with the most years asked for by any mention.
+func Extract(description string) []models.JobSkill {
+	var found requirements
+	nice := false
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if heading, ok := headingText(line); ok {
+			nice = niceHeading.MatchString(heading)
+			// Headings such as "Nice to have: Kafka" also list requirements
+			if !strings.Contains(heading, ":") || strings.HasSuffix(heading, ":") {
+				continue
+			}
+		}
+
+		required := isRequired(line, !nice)
+		for _, sentence := range sentences(line) {
+			found.addSentence(sentence, required)
+		}
+		found.addLanguages(line, !nice)
+		found.addEducation(line, required)
+	}
+	return found.list
+}
+
+// isRequired reports whether the requirements of a text are must-have, by the words
+// of the text and otherwise by its section
+func isRequired(text string, inSection bool) bool {
+	if niceLine.MatchString(text) {
+		return false
+	}
+	if mustLine.MatchString(text) {
+		return true
+	}
+	return inSection
+}
+
+// requirements collects requirements, merging the mentions of the same one
+type requirements struct {
+	list  []models.JobSkill
+	index map[string]int // Position in list by kind and lower case name
+}
+
+func (r *requirements) add(skill models.JobSkill) {
+	if r.index == nil {
+		r.index = make(map[string]int)
+	}
+	key := string(skill.Kind) + "\x00" + strings.ToLower(skill.Name)
+	i, ok := r.index[key]
+	if !ok {
+		r.index[key] = len(r.list)
+		r.list = append(r.list, skill)
+		return
+	}
+	existing := &r.list[i]
+	existing.Required = existing.Required || skill.Required
+	if skill.Years != nil && (existing.Years == nil || *skill.Years > *existing.Years) {
+		existing.Years = skill.Years
+	}
+	if existing.Detail == "" {
+		existing.Detail = skill.Detail
+	}
+}
+
+// addSentence adds the taxonomy skills and certifications of a sentence with the
+// years of experience it asks for
+func (r *requirements) addSentence(sentence string, required bool) {
+	years := yearsOf(sentence)
+	matches := matchTaxonomy(sentence)
+	for _, e := range matches {
+		r.add(models.JobSkill{
+			Kind:     e.skillKind(),
+			Name:     e.name,
+			Category: e.category,
+			Years:    years,
+			Required: required,
+			Source:   models.SkillSourceTaxonomy,
+		})
+	}
+	if len(matches) == 0 && years != nil && experienceWord.MatchString(sentence) {
+		r.add(models.JobSkill{
+			Kind:     models.SkillKindExperience,
+			Name:     ExperienceName,
+			Years:    years,
+			Required: required,
+			Detail:   truncate(strings.Trim(sentence, " -*#.;")),
+			Source:   models.SkillSourceTaxonomy,
+		})
+	}
+}
+
+// addLanguages adds the spoken languages of a line that talks about languages. Lines
+// often list several, as in "fluent English (C1), German is a plus", so the level and
+// must-have words of a comma separated part carry over to the parts after it only.
+func (r *requirements) addLanguages(line string, inSection bool) {
+	if !languageContext.MatchString(line) {
+		return
+	}
+	level, required := "", inSection
+	for _, clause := range clauseSeparator.Split(line, -1) {
+		if niceLine.MatchString(clause) || mustLine.MatchString(clause) {
+			if clauseRequired := isRequired(clause, inSection); clauseRequired != required {
+				required, level = clauseRequired, ""
+			}
+		}
+		if clauseLevel := levelOf(clause); clauseLevel != "" {
+			level = clauseLevel
+		}
+		for _, word := range languageName.FindAllString(clause, -1) {
+			if name, ok := spokenLanguages[strings.ToLower(word)]; ok {
+				r.add(models.JobSkill{
+					Kind:     models.SkillKindLanguage,
+					Name:     name,
+					Required: required,
+					Detail:   level,
+					Source:   models.SkillSourceTaxonomy,
+				})
+			}
+		}
+	}
+}
+
+// levelOf returns the level a text asks a spoken language at, a CEFR level if it has one
+func levelOf(text string) string {
+	if m := cefrLevel.FindString(text); m != "" {
+		return m
+	}
+	return strings.ToLower(languageLevel.FindString(text))
+}
+
+// addEducation adds the degree a line asks for, the highest if it names several
+func (r *requirements) addEducation(line string, required bool) {
+	for _, degree := range degrees {
+		loc := degree.pattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		detail := ""
+		if m := degreeField.FindStringSubmatch(line[loc[1]:]); m != nil {
+			detail = strings.TrimSpace(m[1])
+		}
+		r.add(models.JobSkill{
+			Kind:     models.SkillKindEducation,
+			Name:     degree.name,
+			Required: required && !equivalentExperience.MatchString(line),
+			Detail:   truncate(detail),
+			Source:   models.SkillSourceTaxonomy,
+		})
+		return
+	}
+}
+
+// headingText returns the text of a markdown heading, a bold-only line or a short line
+// ending with a colon
+func headingText(line string) (string, bool) {
+	switch {
+	case strings.HasPrefix(line, "#"):
+		return strings.TrimSpace(strings.TrimLeft(line, "#")), true
+	case strings.HasPrefix(line, "**") && strings.HasSuffix(line, "**") && len(line) > 4:
+		return strings.TrimSpace(strings.Trim(line, "*")), true
+	case strings.HasSuffix(strings.TrimRight(line, "*"), ":") && utf8.RuneCountInString(line) <= 60 && !strings.HasPrefix(line, "- "):
+		return strings.TrimSpace(strings.Trim(line, "*")), true
+	}
+	return "", false
+}
+
+// sentences splits a line into its sentences
+func sentences(line string) []string {
+	var parts []string
+	start := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(line, -1) {
+		// Keep the dots of names such as Node.js and ASP.NET
+		if loc[1]-loc[0] == 1 && loc[1] < len(line) {
+			continue
+		}
+		parts = append(parts, line[start:loc[1]])
+		start = loc[1]
+	}
+	if start < len(line) {
+		parts = append(parts, line[start:])
+	}
+	return parts
+}
+
+// numberWords are the spelled out numbers of years
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// yearsOf returns the minimum years of experience a sentence asks for, nil if none
+func yearsOf(sentence string) *int {
+	m := yearsPattern.FindStringSubmatch(sentence)
+	if m == nil {
+		return nil
+	}
+	years, err := strconv.Atoi(m[1])
+	if err != nil {
+		years = numberWords[strings.ToLower(m[1])]
+	}
+	if years <= 0 || years > 30 {
+		return nil
+	}
+	return &years
+}
+
+// match is a taxonomy entry found in a text
+type match struct {
+	start, end int
+	entry      int
+}
+
+// matchTaxonomy returns the taxonomy entries mentioned in a text, once each, in the
+// order of their first mention. Longer aliases win over the shorter ones they contain,
+// so "React Native" is not also React.
+func matchTaxonomy(text string) []entry {
+	lower := asciiLower(text)
+	var candidates []match
+	for i, e := range taxonomy {
+		for _, alias := range e.aliases {
+			candidates = appendMatches(candidates, lower, alias, e, i)
+		}
+		for _, alias := range e.exact {
+			candidates = appendMatches(candidates, text, alias, e, i)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		la, lb := candidates[a].end-candidates[a].start, candidates[b].end-candidates[b].start
+		if la != lb {
+			return la > lb
+		}
+		return candidates[a].start < candidates[b].start
+	})
+
+	var kept []match
+	for _, c := range candidates {
+		overlaps := false
+		for _, k := range kept {
+			if c.start < k.end && k.start < c.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, c)
+		}
+	}
+	sort.Slice(kept, func(a, b int) bool { return kept[a].start < kept[b].start })
+
+	seen := make(map[int]bool)
+	var entries []entry
+	for _, k := range kept {
+		if !seen[k.entry] {
+			seen[k.entry] = true
+			entries = append(entries, taxonomy[k.entry])
+		}
+	}
+	return entries
+}
+
+// appendMatches appends every mention of an alias in text that stands on its own
+func appendMatches(matches []match, text, alias string, e entry, index int) []match {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], alias)
+		if i < 0 {
+			return matches
+		}
+		start := offset + i
+		end := start + len(alias)
+		offset = start + 1
+		if !boundaryBefore(text, start) || !boundaryAfter(text, end) || followedBy(text[end:], e.notFollowedBy) {
+			continue
+		}
+		matches = append(matches, match{start, end, index})
+	}
+}
+
+// boundaryBefore reports whether no word continues before position i. Dots count, so
+// "js" in "node.js" is not a mention.
+func boundaryBefore(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '_' && r != '-'
+}
+
+// boundaryAfter reports whether no word continues after position i. Plus and hash
+// signs count, so "C" in "C++" and "C#" would not be a mention.
+func boundaryAfter(text string, i int) bool {
+	if i == len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	if r == '.' {
+		// A dot ends a sentence, but continues names such as "Vue.js"
+		next, _ := utf8.DecodeRuneInString(text[i+1:])
+		return !unicode.IsLetter(next)
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '_'
+}
+
+func followedBy(rest string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(rest, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// asciiLower lower cases the ASCII letters of s only, keeping byte offsets unchanged
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func truncate(s string) string {
+	if utf8.RuneCountInString(s) <= maxDetail {
+		return s
+	}
+	return string([]rune(s)[:maxDetail-1]) + "…"
+}
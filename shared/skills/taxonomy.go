@@ -0,0 +1,235 @@
+package skills
+
+import (
+	"strings"
+
+	"github.com/hirepilot/shared/models"
+)
+
+// Categories of skills in the taxonomy
+const (
+	CategoryLanguage  = "language" // Programming language
+	CategoryFramework = "framework"
+	CategoryDatabase  = "database"
+	CategoryCloud     = "cloud"
+	CategoryDevOps    = "devops"
+	CategoryData      = "data" // Data engineering, analytics and machine learning
+	CategoryTool      = "tool"
+	CategoryPractice  = "practice"
+)
+
+// entry is a skill or certification of the taxonomy. Aliases match case-insensitively,
+// exact aliases only as written, for names that are common words in lower case.
+type entry struct {
+	name          string
+	category      string
+	kind          models.SkillKind // Skill unless set
+	aliases       []string
+	exact         []string
+	notFollowedBy []string // Text after an alias that means it is not the skill, e.g. "Go to"
+}
+
+func (e entry) skillKind() models.SkillKind {
+	if e.kind == "" {
+		return models.SkillKindSkill
+	}
+	return e.kind
+}
+
+// taxonomy is the bundled list of skills. Names are the canonical spelling stored in
+// the index; single letter languages such as C and R are left out as they cannot be
+// told apart from plain letters.
+var taxonomy = []entry{
+	// Programming languages
+	{name: "Go", category: CategoryLanguage, aliases: []string{"golang"}, exact: []string{"Go"},
+		notFollowedBy: []string{" to ", "-to", " ahead", " beyond", " live", " the extra"}},
+	{name: "Python", category: CategoryLanguage, aliases: []string{"python"}},
+	{name: "Java", category: CategoryLanguage, aliases: []string{"java"}},
+	{name: "JavaScript", category: CategoryLanguage, aliases: []string{"javascript", "ecmascript"}, exact: []string{"JS"}},
+	{name: "TypeScript", category: CategoryLanguage, aliases: []string{"typescript"}},
+	{name: "C++", category: CategoryLanguage, aliases: []string{"c++", "cpp"}},
+	{name: "C#", category: CategoryLanguage, aliases: []string{"c#", "csharp", "c sharp"}},
+	{name: "Rust", category: CategoryLanguage, aliases: []string{"rust"}},
+	{name: "Kotlin", category: CategoryLanguage, aliases: []string{"kotlin"}},
+	{name: "Swift", category: CategoryLanguage, exact: []string{"Swift"}},
+	{name: "Objective-C", category: CategoryLanguage, aliases: []string{"objective-c", "objective c"}},
+	{name: "Ruby", category: CategoryLanguage, aliases: []string{"ruby"}},
+	{name: "PHP", category: CategoryLanguage, aliases: []string{"php"}},
+	{name: "Scala", category: CategoryLanguage, aliases: []string{"scala"}},
+	{name: "Elixir", category: CategoryLanguage, aliases: []string{"elixir"}},
+	{name: "Erlang", category: CategoryLanguage, aliases: []string{"erlang"}},
+	{name: "Haskell", category: CategoryLanguage, aliases: []string{"haskell"}},
+	{name: "Clojure", category: CategoryLanguage, aliases: []string{"clojure"}},
+	{name: "Perl", category: CategoryLanguage, aliases: []string{"perl"}},
+	{name: "Lua", category: CategoryLanguage, aliases: []string{"lua"}},
+	{name: "Dart", category: CategoryLanguage, exact: []string{"Dart"}},
+	{name: "MATLAB", category: CategoryLanguage, aliases: []string{"matlab"}},
+	{name: "Solidity", category: CategoryLanguage, aliases: []string{"solidity"}},
+	{name: "SQL", category: CategoryLanguage, aliases: []string{"sql"}},
+	{name: "Bash", category: CategoryLanguage, aliases: []string{"bash", "shell scripting"}},
+	{name: "PowerShell", category: CategoryLanguage, aliases: []string{"powershell"}},
+	{name: "HTML", category: CategoryLanguage, aliases: []string{"html", "html5"}},
+	{name: "CSS", category: CategoryLanguage, aliases: []string{"css", "css3"}},
+
+	// Frameworks and libraries
+	{name: "React", category: CategoryFramework, aliases: []string{"react.js", "reactjs"}, exact: []string{"React"}},
+	{name: "React Native", category: CategoryFramework, aliases: []string{"react native"}},
+	{name: "Next.js", category: CategoryFramework, aliases: []string{"next.js", "nextjs"}},
+	{name: "Vue.js", category: CategoryFramework, aliases: []string{"vue", "vue.js", "vuejs"}},
+	{name: "Angular", category: CategoryFramework, aliases: []string{"angular", "angularjs"}},
+	{name: "Svelte", category: CategoryFramework, aliases: []string{"svelte", "sveltekit"}},
+	{name: "jQuery", category: CategoryFramework, aliases: []string{"jquery"}},
+	{name: "Tailwind CSS", category: CategoryFramework, aliases: []string{"tailwind", "tailwindcss", "tailwind css"}},
+	{name: "Sass", category: CategoryFramework, aliases: []string{"sass", "scss"}},
+	{name: "Node.js", category: CategoryFramework, aliases: []string{"node.js", "nodejs"}, exact: []string{"Node"}},
+	{name: "Express", category: CategoryFramework, aliases: []string{"express.js", "expressjs"}},
+	{name: "NestJS", category: CategoryFramework, aliases: []string{"nestjs", "nest.js"}},
+	{name: "Django", category: CategoryFramework, aliases: []string{"django"}},
+	{name: "Flask", category: CategoryFramework, exact: []string{"Flask"}},
+	{name: "FastAPI", category: CategoryFramework, aliases: []string{"fastapi"}},
+	{name: "Spring Boot", category: CategoryFramework, aliases: []string{"spring boot", "springboot"}, exact: []string{"Spring"}},
+	{name: "Ruby on Rails", category: CategoryFramework, aliases: []string{"ruby on rails", "rails"}},
+	{name: "Laravel", category: CategoryFramework, aliases: []string{"laravel"}},
+	{name: "Symfony", category: CategoryFramework, aliases: []string{"symfony"}},
+	{name: ".NET", category: CategoryFramework, aliases: []string{".net", "dotnet", ".net core", "asp.net", "asp.net core"}},
+	{name: "Flutter", category: CategoryFramework, aliases: []string{"flutter"}},
+	{name: "iOS", category: CategoryFramework, aliases: []string{"ios"}},
+	{name: "Android", category: CategoryFramework, aliases: []string{"android"}},
+	{name: "GraphQL", category: CategoryFramework, aliases: []string{"graphql"}},
+	{name: "gRPC", category: CategoryFramework, aliases: []string{"grpc"}},
+	{name: "REST", category: CategoryFramework, aliases: []string{"restful", "rest api", "rest apis"}, exact: []string{"REST"}},
+
+	// Databases and messaging
+	{name: "PostgreSQL", category: CategoryDatabase, aliases: []string{"postgresql", "postgres"}},
+	{name: "MySQL", category: CategoryDatabase, aliases: []string{"mysql", "mariadb"}},
+	{name: "SQL Server", category: CategoryDatabase, aliases: []string{"sql server", "mssql", "t-sql"}},
+	{name: "Oracle Database", category: CategoryDatabase, aliases: []string{"oracle database", "oracle db", "pl/sql"}},
+	{name: "SQLite", category: CategoryDatabase, aliases: []string{"sqlite"}},
+	{name: "MongoDB", category: CategoryDatabase, aliases: []string{"mongodb", "mongo"}},
+	{name: "Redis", category: CategoryDatabase, aliases: []string{"redis"}},
+	{name: "Elasticsearch", category: CategoryDatabase, aliases: []string{"elasticsearch", "elastic search", "opensearch"}},
+	{name: "Cassandra", category: CategoryDatabase, aliases: []string{"cassandra"}},
+	{name: "DynamoDB", category: CategoryDatabase, aliases: []string{"dynamodb"}},
+	{name: "NoSQL", category: CategoryDatabase, aliases: []string{"nosql"}},
+	{name: "Kafka", category: CategoryDatabase, aliases: []string{"kafka", "apache kafka"}},
+	{name: "RabbitMQ", category: CategoryDatabase, aliases: []string{"rabbitmq"}},
+	{name: "NATS", category: CategoryDatabase, exact: []string{"NATS"}},
+
+	// Cloud
+	{name: "AWS", category: CategoryCloud, aliases: []string{"aws", "amazon web services"}},
+	{name: "AWS Lambda", category: CategoryCloud, aliases: []string{"aws lambda", "lambda functions"}},
+	{name: "Azure", category: CategoryCloud, aliases: []string{"azure", "microsoft azure"}},
+	{name: "Google Cloud", category: CategoryCloud, aliases: []string{"gcp", "google cloud", "google cloud platform"}},
+	{name: "Serverless", category: CategoryCloud, aliases: []string{"serverless"}},
+
+	// DevOps
+	{name: "Docker", category: CategoryDevOps, aliases: []string{"docker"}},
+	{name: "Kubernetes", category: CategoryDevOps, aliases: []string{"kubernetes", "k8s", "eks", "gke", "aks"}},
+	{name: "OpenShift", category: CategoryDevOps, aliases: []string{"openshift"}},
+	{name: "Helm", category: CategoryDevOps, exact: []string{"Helm"}},
+	{name: "Istio", category: CategoryDevOps, aliases: []string{"istio"}},
+	{name: "Terraform", category: CategoryDevOps, aliases: []string{"terraform"}},
+	{name: "Pulumi", category: CategoryDevOps, aliases: []string{"pulumi"}},
+	{name: "Ansible", category: CategoryDevOps, aliases: []string{"ansible"}},
+	{name: "Jenkins", category: CategoryDevOps, aliases: []string{"jenkins"}},
+	{name: "GitHub Actions", category: CategoryDevOps, aliases: []string{"github actions"}},
+	{name: "GitLab CI", category: CategoryDevOps, aliases: []string{"gitlab ci", "gitlab ci/cd"}},
+	{name: "Argo CD", category: CategoryDevOps, aliases: []string{"argo cd", "argocd"}},
+	{name: "CI/CD", category: CategoryDevOps, aliases: []string{"ci/cd", "continuous integration", "continuous delivery", "continuous deployment"}},
+	{name: "Linux", category: CategoryDevOps, aliases: []string{"linux", "unix"}},
+	{name: "Nginx", category: CategoryDevOps, aliases: []string{"nginx"}},
+	{name: "Prometheus", category: CategoryDevOps, aliases: []string{"prometheus"}},
+	{name: "Grafana", category: CategoryDevOps, aliases: []string{"grafana"}},
+	{name: "Datadog", category: CategoryDevOps, aliases: []string{"datadog"}},
+
+	// Data and machine learning
+	{name: "Spark", category: CategoryData, aliases: []string{"apache spark", "pyspark"}, exact: []string{"Spark"}},
+	{name: "Hadoop", category: CategoryData, aliases: []string{"hadoop"}},
+	{name: "Airflow", category: CategoryData, aliases: []string{"airflow", "apache airflow"}},
+	{name: "dbt", category: CategoryData, exact: []string{"dbt", "DBT"}},
+	{name: "Snowflake", category: CategoryData, exact: []string{"Snowflake"}},
+	{name: "BigQuery", category: CategoryData, aliases: []string{"bigquery"}},
+	{name: "Pandas", category: CategoryData, aliases: []string{"pandas"}},
+	{name: "NumPy", category: CategoryData, aliases: []string{"numpy"}},
+	{name: "scikit-learn", category: CategoryData, aliases: []string{"scikit-learn", "sklearn", "scikit learn"}},
+	{name: "PyTorch", category: CategoryData, aliases: []string{"pytorch"}},
+	{name: "TensorFlow", category: CategoryData, aliases: []string{"tensorflow"}},
+	{name: "Machine Learning", category: CategoryData, aliases: []string{"machine learning"}, exact: []string{"ML"}},
+	{name: "NLP", category: CategoryData, aliases: []string{"nlp", "natural language processing"}},
+	{name: "LLMs", category: CategoryData, aliases: []string{"llm", "llms", "large language models", "generative ai", "genai"}},
+	{name: "Tableau", category: CategoryData, aliases: []string{"tableau"}},
+	{name: "Power BI", category: CategoryData, aliases: []string{"power bi", "powerbi"}},
+
+	// Tools
+	{name: "Git", category: CategoryTool, aliases: []string{"git"}},
+	{name: "Jira", category: CategoryTool, aliases: []string{"jira"}},
+	{name: "Figma", category: CategoryTool, aliases: []string{"figma"}},
+	{name: "Excel", category: CategoryTool, exact: []string{"Excel"}},
+	{name: "Selenium", category: CategoryTool, aliases: []string{"selenium"}},
+	{name: "Cypress", category: CategoryTool, aliases: []string{"cypress"}},
+	{name: "Playwright", category: CategoryTool, aliases: []string{"playwright"}},
+	{name: "Jest", category: CategoryTool, aliases: []string{"jest"}},
+	{name: "OpenAPI", category: CategoryTool, aliases: []string{"openapi", "swagger"}},
+
+	// Practices
+	{name: "Microservices", category: CategoryPractice, aliases: []string{"microservices", "microservice", "micro-services"}},
+	{name: "Distributed Systems", category: CategoryPractice, aliases: []string{"distributed systems"}},
+	{name: "TDD", category: CategoryPractice, aliases: []string{"tdd", "test-driven development", "test driven development"}},
+	{name: "Agile", category: CategoryPractice, aliases: []string{"agile"}},
+	{name: "Scrum", category: CategoryPractice, aliases: []string{"scrum"}},
+	{name: "Domain-Driven Design", category: CategoryPractice, aliases: []string{"domain-driven design", "domain driven design", "ddd"}},
+	{name: "System Design", category: CategoryPractice, aliases: []string{"system design"}},
+
+	// Certifications
+	{name: "AWS Certified Solutions Architect", kind: models.SkillKindCertification, aliases: []string{"aws certified solutions architect", "aws solutions architect"}},
+	{name: "AWS Certified Developer", kind: models.SkillKindCertification, aliases: []string{"aws certified developer"}},
+	{name: "AWS Certification", kind: models.SkillKindCertification, aliases: []string{"aws certification", "aws certified"}},
+	{name: "Azure Certification", kind: models.SkillKindCertification, aliases: []string{"azure certification", "azure certified", "az-900", "az-104", "az-204", "az-305"}},
+	{name: "Google Cloud Certification", kind: models.SkillKindCertification, aliases: []string{"google cloud certification", "google cloud certified", "gcp certification"}},
+	{name: "CKA", kind: models.SkillKindCertification, aliases: []string{"cka", "certified kubernetes administrator"}},
+	{name: "CKAD", kind: models.SkillKindCertification, aliases: []string{"ckad", "certified kubernetes application developer"}},
+	{name: "Scrum Master Certification", kind: models.SkillKindCertification, aliases: []string{"csm", "psm", "certified scrum master", "professional scrum master"}},
+	{name: "PMP", kind: models.SkillKindCertification, aliases: []string{"pmp", "project management professional"}},
+	{name: "PRINCE2", kind: models.SkillKindCertification, aliases: []string{"prince2"}},
+	{name: "ITIL", kind: models.SkillKindCertification, aliases: []string{"itil"}},
+	{name: "CISSP", kind: models.SkillKindCertification, aliases: []string{"cissp"}},
+	{name: "OSCP", kind: models.SkillKindCertification, aliases: []string{"oscp"}},
+	{name: "CompTIA Security+", kind: models.SkillKindCertification, aliases: []string{"security+", "comptia security+"}},
+	{name: "ISTQB", kind: models.SkillKindCertification, aliases: []string{"istqb"}},
+}
+
+// spokenLanguages are the spoken languages recognised, by lower case name
+var spokenLanguages = map[string]string{
+	"english": "English", "german": "German", "french": "French", "spanish": "Spanish",
+	"italian": "Italian", "portuguese": "Portuguese", "dutch": "Dutch", "polish": "Polish",
+	"swedish": "Swedish", "danish": "Danish", "norwegian": "Norwegian", "finnish": "Finnish",
+	"czech": "Czech", "greek": "Greek", "turkish": "Turkish", "russian": "Russian",
+	"ukrainian": "Ukrainian", "arabic": "Arabic", "hebrew": "Hebrew", "hindi": "Hindi",
+	"japanese": "Japanese", "korean": "Korean", "mandarin": "Mandarin", "chinese": "Mandarin",
+	"cantonese": "Cantonese",
+}
+
+// byAlias finds taxonomy entries by their lower case name and aliases
+var byAlias = func() map[string]int {
+	index := make(map[string]int)
+	for i, e := range taxonomy {
+		index[strings.ToLower(e.name)] = i
+		for _, alias := range append(append([]string{}, e.aliases...), e.exact...) {
+			if _, ok := index[strings.ToLower(alias)]; !ok {
+				index[strings.ToLower(alias)] = i
+			}
+		}
+	}
+	return index
+}()
+
+// Lookup returns the taxonomy skill or certification a name refers to, by its
+// canonical name or any alias
+func Lookup(name string) (kind models.SkillKind, canonical, category string, ok bool) {
+	i, ok := byAlias[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", "", "", false
+	}
+	e := taxonomy[i]
+	return e.skillKind(), e.name, e.category, true
+}
@@ -0,0 +1,37 @@
+// Package worker runs the periodic work of the services
+package worker
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Every runs run now and then every interval, read from the environment variable
+// envVar with def as the default. It never returns; services that do other work start
+// it in a goroutine. A run that takes longer than the interval delays the next one.
+func Every(name, envVar string, def time.Duration, run func()) {
+	interval := DurationFromEnv(envVar, def)
+	log.Printf("Running %s every %s", name, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		run()
+		<-ticker.C
+	}
+}
+
+// DurationFromEnv returns the duration in an environment variable, or def when it is
+// not set. An invalid or non-positive duration stops the service.
+func DurationFromEnv(envVar string, def time.Duration) time.Duration {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return def
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Fatalf("Invalid %s %q, expected a duration such as 30m or 6h", envVar, value)
+	}
+	return parsed
+}